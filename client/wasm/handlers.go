@@ -7,6 +7,9 @@
 package main
 
 import (
+	"fmt"
+	"strconv"
+	"strings"
 	"sync"
 	"syscall/js"
 	"time"
@@ -21,8 +24,18 @@ const (
 	messageDisplayTime      = 3 * time.Second
 	copyButtonResetTime     = 2 * time.Second
 	errorMessageDisplayTime = 5 * time.Second
+	matchIntroDuration      = 1500 * time.Millisecond
 )
 
+// findGameOnWelcome starts a matchmaking search once the server returns us to the lobby
+var findGameOnWelcome bool
+
+// matchIntroPending shows the opponent found transition on the next game start, set while searching
+var matchIntroPending bool
+
+// matchIntroTimer hides the opponent found transition
+var matchIntroTimer *time.Timer
+
 // setupEventListeners attaches all UI event listeners
 func setupEventListeners() {
 	// Login screen
@@ -42,20 +55,101 @@ func setupEventListeners() {
 	attachKeyPressListener("join-code-input", handleJoinGame)
 	attachEventListener("copy-code-btn", "click", handleCopyCode)
 
+	// Lobby chat
+	attachEventListener("lobby-chat-send-btn", "click", handleSendLobbyChat)
+	attachEventListener("leaderboard", "toggle", handleLeaderboardToggle)
+	attachEventListener("recent-games", "toggle", handleRecentGamesToggle)
+	attachKeyPressListener("lobby-chat-input", handleSendLobbyChat)
+
 	// Matchmaking mode
 	attachEventListener("cancel-matchmaking-btn", "click", handleCancelMatchmaking)
+	attachEventListener("ready-accept-btn", "click", handleReadyAccept)
+	attachEventListener("ready-decline-btn", "click", handleReadyDecline)
 
 	// Game screen
 	attachEventListener("copy-code-game-btn", "click", handleCopyGameCode)
 	attachEventListener("replay-btn", "click", handleReplay)
+	attachEventListener("find-game-btn", "click", handleFindNewGame)
 	attachEventListener("forfeit-btn", "click", handleForfeit)
+	attachEventListener("resign-round-btn", "click", handleResignRound)
 	attachEventListener("back-to-lobby-btn", "click", handleBackToLobby)
 	attachEventListener("cancel-game-btn", "click", handleCancelGame)
 
+	// Replay review
+	attachEventListener("load-replay-btn", "click", handleLoadReplay)
+	attachEventListener("replay-file-input", "change", handleReplayFile)
+	attachEventListener("replay-first-btn", "click", handleReplayFirst)
+	attachEventListener("replay-prev-btn", "click", handleReplayPrev)
+	attachEventListener("replay-next-btn", "click", handleReplayNext)
+	attachEventListener("replay-last-btn", "click", handleReplayLast)
+	attachEventListener("replay-exit-btn", "click", handleReplayExit)
+
+	// Board editor
+	attachEventListener("board-editor-btn", "click", handleOpenEditor)
+	attachEventListener("editor-side-btn", "click", handleEditorSide)
+	attachEventListener("editor-clear-btn", "click", handleEditorClear)
+	attachEventListener("editor-copy-btn", "click", handleEditorCopy)
+	attachEventListener("editor-exit-btn", "click", handleEditorExit)
+
+	// Puzzle rush
+	attachEventListener("puzzle-rush-btn", "click", handlePuzzleRush)
+	attachEventListener("puzzle-restart-btn", "click", handlePuzzleRush)
+	attachEventListener("puzzle-exit-btn", "click", handlePuzzleExit)
+	attachEventListener("watch-featured-btn", "click", handleWatchFeatured)
+	attachEventListener("watch-exit-btn", "click", handleWatchExit)
+
+	// Announcements
+	attachEventListener("announcement-dismiss-btn", "click", handleDismissAnnouncement)
+	attachEventListener("update-reload-btn", "click", handleReloadPage)
+
+	// Settings
+	attachEventListener("settings-btn", "click", handleToggleSettings)
+	attachEventListener("settings-close-btn", "click", handleToggleSettings)
+	attachEventListener("setting-render-style", "change", handleRenderStyleChange)
+	attachEventListener("setting-disc-skin", "change", handleDiscSkinChange)
+	attachEventListener("setting-drop-easing", "change", handleDropEasingChange)
+	attachEventListener("setting-highlight-color", "change", handleHighlightColorChange)
+	attachEventListener("setting-highlight-pulse", "change", handleHighlightPulseChange)
+	attachEventListener("setting-winning-preview", "change", handleWinningPreviewChange)
+	attachEventListener("setting-gravity-trail", "change", handleGravityTrailChange)
+	attachEventListener("setting-mirror-board", "change", handleMirrorBoardChange)
+	attachEventListener("setting-high-contrast", "change", handleHighContrastChange)
+	attachEventListener("setting-stream-palette", "change", handleStreamPaletteChange)
+	attachEventListener("setting-move-numbers", "change", handleMoveNumbersChange)
+	attachEventListener("setting-me-first", "change", handleMeFirstChange)
+	attachEventListener("setting-block-hints", "change", handleBlockHintsChange)
+	attachEventListener("setting-confirm-moves", "change", handleConfirmMovesChange)
+	attachEventListener("setting-turn-alerts", "change", handleTurnAlertsChange)
+	attachEventListener("setting-auto-rematch", "change", handleAutoRematchChange)
+	attachEventListener("setting-sync-prefs", "change", handleSyncPrefsChange)
+	syncSettingsControls()
+
 	// Board interactions
 	setupBoardListeners()
 }
 
+// syncSettingsControls reflects the saved preferences in the settings panel
+func syncSettingsControls() {
+	settings := lib.GetSettings()
+	lib.SetValue("setting-render-style", settings.GetRenderStyle())
+	lib.SetValue("setting-disc-skin", settings.GetDiscSkin())
+	lib.SetValue("setting-drop-easing", settings.GetDropEasing())
+	lib.SetValue("setting-highlight-color", settings.GetHighlightColor())
+	lib.SetChecked("setting-highlight-pulse", settings.GetHighlightPulse())
+	lib.SetChecked("setting-winning-preview", settings.GetWinningPreview())
+	lib.SetChecked("setting-gravity-trail", settings.GetGravityTrail())
+	lib.SetChecked("setting-mirror-board", settings.GetMirrorBoard())
+	lib.SetChecked("setting-high-contrast", settings.GetHighContrast())
+	lib.SetChecked("setting-stream-palette", settings.GetStreamPalette())
+	lib.SetChecked("setting-move-numbers", settings.GetMoveNumbers())
+	lib.SetChecked("setting-me-first", settings.GetMeFirst())
+	lib.SetChecked("setting-block-hints", settings.GetBlockHints())
+	lib.SetChecked("setting-confirm-moves", settings.GetConfirmMoves())
+	lib.SetChecked("setting-turn-alerts", settings.GetTurnAlerts())
+	lib.SetChecked("setting-sync-prefs", settings.GetSyncPrefs())
+	lib.SetChecked("setting-auto-rematch", settings.GetAutoRematch())
+}
+
 // attachEventListener attaches a simple event listener
 func attachEventListener(elementID, eventType string, handler func(js.Value, []js.Value) interface{}) {
 	element := lib.GetElement(elementID)
@@ -99,6 +193,36 @@ func setupBoardListeners() {
 		lib.HandleLeave(args[0])
 		return nil
 	}))
+
+	// Escape or a tap anywhere else on the game screen cancels a column armed for confirmation
+	gameScreen := lib.GetElement("game-screen")
+	if gameScreen.IsNull() {
+		return
+	}
+
+	gameScreen.Call("addEventListener", "keydown", js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		if args[0].Get("key").String() == "Escape" {
+			cancelArmedMove()
+		}
+		return nil
+	}))
+
+	gameScreen.Call("addEventListener", "pointerdown", js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		if !args[0].Get("target").Equal(canvas) {
+			cancelArmedMove()
+		}
+		return nil
+	}))
+}
+
+// cancelArmedMove drops the local preview of an armed column, nothing is sent to the server
+func cancelArmedMove() {
+	if !lib.Get().IsMoveArmed() {
+		return
+	}
+
+	// Clears the preview, redraws and puts the turn message back in place of the confirmation hint
+	updateGameStatus()
 }
 
 // ============================================================================
@@ -119,7 +243,14 @@ func handleConnect(this js.Value, args []js.Value) interface{} {
 
 // handleCreateGame creates a new private game
 func handleCreateGame(this js.Value, args []js.Value) interface{} {
-	lib.SendMessage("create_game", map[string]interface{}{})
+	clock, increment := timeControlChoice()
+	lib.SendMessage("create_game", map[string]interface{}{
+		"pause_on_disconnect": lib.GetChecked("pause-on-disconnect"),
+		"best_of":             bestOfChoice(),
+		"invite":              strings.TrimSpace(lib.GetValue("invite-input")),
+		"clock_seconds":       clock,
+		"increment_seconds":   increment,
+	})
 	showWaitingArea()
 	return nil
 }
@@ -173,19 +304,64 @@ func handleCopyGameCode(this js.Value, args []js.Value) any {
 func handleReplay(this js.Value, args []js.Value) interface{} {
 	state := lib.Get()
 	state.SetReplayRequested(true)
-	lib.SendMessage("replay", map[string]interface{}{})
+	lib.SendMessage("replay", map[string]interface{}{
+		"keep_seats": lib.GetChecked("keep-seats"),
+	})
 	updateReplayButton()
 	return nil
 }
 
-// handleForfeit forfeits the current game
+// handleFindNewGame leaves a finished matchmaking game and searches again
+func handleFindNewGame(this js.Value, args []js.Value) interface{} {
+	findGameOnWelcome = true
+	lib.SendMessage("leave_lobby", map[string]interface{}{})
+	return nil
+}
+
+// bestOfChoice returns the series length picked for a new friend game
+func bestOfChoice() int {
+	bestOf, err := strconv.Atoi(lib.GetValue("best-of-select"))
+	if err != nil {
+		return 1
+	}
+	return bestOf
+}
+
+// timeControlChoice returns the clock and increment in seconds picked for a new friend game
+// The preset values read "clock+increment", zeros let the server pick its default
+func timeControlChoice() (clock, increment int) {
+	clockValue, incrementValue, found := strings.Cut(lib.GetValue("time-control-select"), "+")
+	if !found {
+		return 0, 0
+	}
+	clock, clockErr := strconv.Atoi(clockValue)
+	increment, incrementErr := strconv.Atoi(incrementValue)
+	if clockErr != nil || incrementErr != nil {
+		return 0, 0
+	}
+	return clock, increment
+}
+
+// handleForfeit forfeits the current game, in a series the whole match
 func handleForfeit(this js.Value, args []js.Value) interface{} {
-	if lib.Confirm("Are you sure you want to forfeit? Your opponent will win.") {
+	message := "Are you sure you want to forfeit? Your opponent will win."
+	if lib.Get().InSeries() {
+		message = "Resign the whole match? Your opponent wins the series."
+	}
+	if lib.Confirm(message) {
 		lib.SendMessage("forfeit", map[string]interface{}{})
 	}
 	return nil
 }
 
+// handleResignRound concedes only the current round of a series
+func handleResignRound(this js.Value, args []js.Value) interface{} {
+	if lib.Confirm("Resign this round? Your opponent wins it and the next round starts.") {
+		lib.SendMessage("resign_round", map[string]interface{}{})
+	}
+	return nil
+}
+
 // handleBackToLobby returns to lobby from game
 func handleBackToLobby(this js.Value, args []js.Value) interface{} {
 	lib.SendMessage("leave_lobby", map[string]interface{}{})
@@ -200,9 +376,16 @@ func handleCancelGame(this js.Value, args []js.Value) interface{} {
 
 // handleLogout logs out the current user
 func handleLogout(this js.Value, args []js.Value) interface{} {
-	lib.RemoveLocalStorage("playerID")
+	lib.RemoveLocalStorage("resumeToken")
 	lib.RemoveLocalStorage("username")
 
+	disconnect()
+	lib.SetValue("username-input", "")
+	return nil
+}
+
+// disconnect closes the connection, forgets the session and returns to the login screen
+func disconnect() {
 	lib.Close()
 	lib.Stop()
 
@@ -211,15 +394,13 @@ func handleLogout(this js.Value, args []js.Value) interface{} {
 	state.SetGameCode("")
 	state.SetPlayerIdx(-1)
 
-	lib.SetValue("username-input", "")
 	lib.Hide("header-user-info")
 	lib.ShowScreen("login")
-
-	return nil
 }
 
 // handleFriendMode shows friend mode panel
 func handleFriendMode(this js.Value, args []js.Value) interface{} {
+	matchIntroPending = false
 	lib.Hide("mode-selection")
 	lib.Hide("matchmaking-panel")
 	lib.Show("friend-mode-panel")
@@ -228,6 +409,7 @@ func handleFriendMode(this js.Value, args []js.Value) interface{} {
 
 // handleMatchmakingMode starts matchmaking
 func handleMatchmakingMode(this js.Value, args []js.Value) interface{} {
+	matchIntroPending = true
 	lib.Hide("mode-selection")
 	lib.Hide("friend-mode-panel")
 	lib.Show("matchmaking-panel")
@@ -246,6 +428,7 @@ func handleMatchmakingMode(this js.Value, args []js.Value) interface{} {
 
 // handleBackToModes returns to mode selection
 func handleBackToModes(this js.Value, args []js.Value) interface{} {
+	matchIntroPending = false
 	lib.Hide("friend-mode-panel")
 	lib.Hide("matchmaking-panel")
 	lib.Hide("waiting-area")
@@ -253,6 +436,11 @@ func handleBackToModes(this js.Value, args []js.Value) interface{} {
 
 	lib.SendMessage("leave_matchmaking", map[string]interface{}{})
 
+	// A hosted game still waiting for an opponent is abandoned with the panel
+	if lib.Get().GetGameCode() != "" {
+		lib.SendMessage("leave_lobby", map[string]interface{}{})
+	}
+
 	clearMessage("lobby-message")
 
 	lib.Show("mode-selection")
@@ -271,8 +459,533 @@ func handleCancelMatchmaking(this js.Value, args []js.Value) interface{} {
 	return nil
 }
 
+// handleSendLobbyChat sends the typed message to the lobby
+func handleSendLobbyChat(this js.Value, args []js.Value) interface{} {
+	text := strings.TrimSpace(lib.GetValue("lobby-chat-input"))
+	if text == "" {
+		return nil
+	}
+
+	lib.SendMessage("lobby_chat", map[string]interface{}{"text": text})
+	lib.SetValue("lobby-chat-input", "")
+	return nil
+}
+
+// handleRecentGamesToggle asks for the player's last games each time their panel is opened
+func handleRecentGamesToggle(this js.Value, args []js.Value) interface{} {
+	if lib.GetElement("recent-games").Get("open").Bool() {
+		lib.SetText("recent-games-status", "Loading...")
+		lib.SendMessage("history_request", map[string]interface{}{"limit": lib.RecentGamesLimit})
+	}
+	return nil
+}
+
+// handleLeaderboardToggle refreshes the leaderboard each time its panel is opened
+func handleLeaderboardToggle(this js.Value, args []js.Value) interface{} {
+	if lib.GetElement("leaderboard").Get("open").Bool() {
+		lib.LoadLeaderboard()
+	}
+	return nil
+}
+
+// handleReadyAccept confirms the found match
+func handleReadyAccept(this js.Value, args []js.Value) interface{} {
+	lib.SendMessage("ready_response", map[string]interface{}{"accept": true})
+	lib.Hide("ready-check-actions")
+	lib.SetText("ready-check-status", "Waiting for your opponent...")
+	return nil
+}
+
+// handleReadyDecline declines the found match and returns to mode selection
+func handleReadyDecline(this js.Value, args []js.Value) interface{} {
+	lib.SendMessage("ready_response", map[string]interface{}{"accept": false})
+	lib.Hide("ready-check")
+	lib.Hide("matchmaking-panel")
+	lib.Show("mode-selection")
+	return nil
+}
+
+// handleLoadReplay opens the file picker for a transcript
+func handleLoadReplay(this js.Value, args []js.Value) interface{} {
+	input := lib.GetElement("replay-file-input")
+	if !input.IsNull() {
+		input.Set("value", "")
+		input.Call("click")
+	}
+	return nil
+}
+
+// handleReplayFile reads the selected transcript and starts the replay
+func handleReplayFile(this js.Value, args []js.Value) interface{} {
+	files := lib.GetElement("replay-file-input").Get("files")
+	if files.IsNull() || files.IsUndefined() || files.Get("length").Int() == 0 {
+		return nil
+	}
+
+	var onLoad js.Func
+	onLoad = js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		onLoad.Release()
+
+		replay, err := lib.ParseTranscript([]byte(args[0].String()))
+		if err != nil {
+			lib.ShowMessage("lobby-message", err.Error(), "error")
+			time.AfterFunc(errorMessageDisplayTime, func() {
+				clearMessage("lobby-message")
+			})
+			return nil
+		}
+
+		startReplay(replay)
+		return nil
+	})
+
+	files.Index(0).Call("text").Call("then", onLoad)
+	return nil
+}
+
+// handleReplayFirst rewinds the replay to the empty board
+func handleReplayFirst(this js.Value, args []js.Value) interface{} {
+	if replay := lib.Get().GetReplay(); replay != nil {
+		replay.Seek(0)
+		showReplayStep(false)
+	}
+	return nil
+}
+
+// handleReplayPrev steps the replay back one move
+func handleReplayPrev(this js.Value, args []js.Value) interface{} {
+	if replay := lib.Get().GetReplay(); replay != nil && replay.Prev() {
+		showReplayStep(false)
+	}
+	return nil
+}
+
+// handleReplayNext steps the replay forward one move with a drop animation
+func handleReplayNext(this js.Value, args []js.Value) interface{} {
+	if replay := lib.Get().GetReplay(); replay != nil {
+		if _, ok := replay.Next(); ok {
+			showReplayStep(true)
+		}
+	}
+	return nil
+}
+
+// handleReplayLast jumps to the final position of the replay
+func handleReplayLast(this js.Value, args []js.Value) interface{} {
+	if replay := lib.Get().GetReplay(); replay != nil {
+		replay.Seek(replay.Len())
+		showReplayStep(false)
+	}
+	return nil
+}
+
+// handleReplayExit leaves the replay and returns to the lobby
+func handleReplayExit(this js.Value, args []js.Value) interface{} {
+	stopReplay()
+	return nil
+}
+
+// handleOpenEditor opens the board editor
+func handleOpenEditor(this js.Value, args []js.Value) interface{} {
+	startEditor()
+	return nil
+}
+
+// handleEditorSide switches the side to move of the edited position
+func handleEditorSide(this js.Value, args []js.Value) interface{} {
+	editor := lib.Get().GetEditor()
+	if editor == nil {
+		return nil
+	}
+
+	editor.ToMove = 1 - editor.ToMove
+	if editor.ToMove == 0 {
+		lib.SetText("editor-side-btn", "Red to move")
+	} else {
+		lib.SetText("editor-side-btn", "Yellow to move")
+	}
+	lib.UpdateEditorStatus()
+	return nil
+}
+
+// handleEditorClear empties the edited board
+func handleEditorClear(this js.Value, args []js.Value) interface{} {
+	state := lib.Get()
+	editor := state.GetEditor()
+	if editor == nil {
+		return nil
+	}
+
+	editor.Board = [lib.Rows][lib.Cols]int{}
+	state.ResetBoard()
+	lib.Draw()
+	lib.UpdateEditorStatus()
+	return nil
+}
+
+// handleEditorCopy copies the edited position to the clipboard once it validates
+func handleEditorCopy(this js.Value, args []js.Value) interface{} {
+	editor := lib.Get().GetEditor()
+	if editor == nil || editor.Validate() != nil {
+		return nil
+	}
+
+	js.Global().Get("navigator").Get("clipboard").Call("writeText", editor.String())
+
+	lib.SetText("editor-copy-btn", "Copied!")
+	time.AfterFunc(copyButtonResetTime, func() {
+		lib.SetText("editor-copy-btn", "Copy position")
+	})
+	return nil
+}
+
+// handleEditorExit closes the board editor
+func handleEditorExit(this js.Value, args []js.Value) interface{} {
+	stopEditor()
+	return nil
+}
+
+// handlePuzzleRush starts a new puzzle rush
+func handlePuzzleRush(this js.Value, args []js.Value) interface{} {
+	lib.SendMessage("puzzle_start", map[string]interface{}{})
+	return nil
+}
+
+// handlePuzzleExit leaves the puzzle rush
+func handlePuzzleExit(this js.Value, args []js.Value) interface{} {
+	stopPuzzle()
+	return nil
+}
+
+// handlePuzzleNext shows the next position of the puzzle rush
+func handlePuzzleNext(data interface{}) {
+	var puzzle lib.PuzzleData
+	if err := remarshal(data, &puzzle); err != nil {
+		lib.Console("handlePuzzleNext: remarshal failed: " + err.Error())
+		return
+	}
+
+	if lib.Get().IsReplaying() {
+		stopReplay()
+	}
+	if lib.Get().IsEditing() {
+		stopEditor()
+	}
+	showPuzzle(puzzle)
+}
+
+// handlePuzzleResult shows how an answer was judged, and the final score when the run is over
+func handlePuzzleResult(data interface{}) {
+	var result lib.PuzzleResultData
+	if err := remarshal(data, &result); err != nil {
+		lib.Console("handlePuzzleResult: remarshal failed: " + err.Error())
+		return
+	}
+
+	// The run may end on its timer after the player left it
+	if !lib.Get().InPuzzle() {
+		return
+	}
+
+	lib.SetText("puzzle-score", fmt.Sprintf("%d", result.Score))
+	lib.SetText("puzzle-high-score", fmt.Sprintf("%d", result.HighScore))
+	if !result.Over {
+		lib.SetText("game-status", fmt.Sprintf("Correct! +%d", result.Points))
+		return
+	}
+
+	lib.Stop()
+	lib.Get().SetGameFinished(true)
+
+	columns := make([]string, len(result.Solutions))
+	for i, col := range result.Solutions {
+		columns[i] = fmt.Sprintf("%d", col+1)
+	}
+	message := "Wrong column, the win was in column " + strings.Join(columns, " or ")
+	if result.TimedOut {
+		message = "Time's up, the win was in column " + strings.Join(columns, " or ")
+	}
+	message += fmt.Sprintf(" - Final score %d", result.Score)
+	if result.NewHighScore {
+		message += " (new best!)"
+	}
+
+	lib.SetText("game-status", message)
+	lib.SetStyle("game-status", "color", "var(--danger)")
+	lib.Show("puzzle-restart-btn")
+}
+
+// handleWatchFeatured asks the server which game the lobby may watch
+func handleWatchFeatured(this js.Value, args []js.Value) interface{} {
+	lib.SendMessage("spectate_featured", map[string]interface{}{})
+	return nil
+}
+
+// handleWatchExit stops watching and returns to the lobby
+func handleWatchExit(this js.Value, args []js.Value) interface{} {
+	stopWatching()
+	return nil
+}
+
+// handleFeaturedGame starts watching the game picked by the server
+func handleFeaturedGame(data interface{}) {
+	var featured lib.FeaturedGameData
+	if err := remarshal(data, &featured); err != nil {
+		lib.Console("handleFeaturedGame: remarshal failed: " + err.Error())
+		return
+	}
+
+	// Watching is only offered from the lobby, ignore an answer that arrives too late
+	state := lib.Get()
+	if state.IsReplaying() || state.InPuzzle() || state.IsEditing() || state.GetGameCode() != "" {
+		return
+	}
+	startWatching(featured.Code, featured.Players)
+}
+
+// handleHistoryResponse lists the player's last finished games in the lobby
+func handleHistoryResponse(data interface{}) {
+	var history lib.HistoryResponseData
+	if err := remarshal(data, &history); err != nil {
+		lib.Console("handleHistoryResponse: remarshal failed: " + err.Error())
+		return
+	}
+	lib.ShowRecentGames(history)
+}
+
+// handleWatchEvent processes an update of the watched game
+func handleWatchEvent(msg lib.Message) {
+	state := lib.Get()
+	if !state.IsWatching() {
+		return
+	}
+
+	switch msg.Type {
+	case "game_state":
+		var gameState lib.GameStateData
+		if err := remarshal(msg.Data, &gameState); err != nil {
+			lib.Console("handleWatchEvent: remarshal failed: " + err.Error())
+			return
+		}
+		state.SetBoard(gameState.Board)
+		state.SetMoveOrder(gameState.Moves)
+		state.SetPlayers(gameState.Players)
+		state.SetCurrentTurn(gameState.CurrentTurn)
+		state.SetTimeRemaining(gameState.TimeRemaining)
+		if gameState.LastMove != nil {
+			state.SetLastMove(gameState.LastMove.Col, gameState.LastMove.Row)
+		}
+		updatePlayers()
+		lib.Draw()
+		if gameState.Status == 2 {
+			showWatchedResult(gameState.Result, gameState.DrawReason)
+			return
+		}
+		showWatchStatus()
+		lib.Start()
+
+	case "move":
+		var move lib.MoveData
+		if err := remarshal(msg.Data, &move); err != nil {
+			lib.Console("handleWatchEvent: remarshal failed: " + err.Error())
+			return
+		}
+		state.SetBoard(move.Board)
+		state.SetCurrentTurn(move.NextTurn)
+		state.SetTimeRemaining(move.TimeRemaining)
+		state.SetLastMove(move.Column, move.Row)
+		state.RecordMove(move.Column, move.Row)
+		lib.AnimateDrop(move.Column, move.Row, 1-move.NextTurn)
+		showWatchStatus()
+
+	case "game_over":
+		var gameOver lib.GameOverData
+		if err := remarshal(msg.Data, &gameOver); err != nil {
+			lib.Console("handleWatchEvent: remarshal failed: " + err.Error())
+			return
+		}
+		state.SetBoard(gameOver.Board)
+		state.SetWinningCells(gameOver.WinningCells)
+		lib.Draw()
+		showWatchedResult(gameOver.Result, gameOver.DrawReason)
+	}
+}
+
+// handleWatchEnd reports a watched game whose stream closed before it finished
+func handleWatchEnd() {
+	state := lib.Get()
+	if !state.IsWatching() || state.GetGameFinished() {
+		return
+	}
+
+	lib.Stop()
+	lib.SetText("game-status", "Watching - The game is no longer available")
+	lib.SetStyle("game-status", "color", "var(--warning)")
+}
+
+// showWatchedResult names the winner of the watched game and stops its clocks
+func showWatchedResult(result int, drawReason string) {
+	state := lib.Get()
+	state.SetGameFinished(true)
+	lib.Stop()
+
+	message := formatDraw(drawReason)
+	if result == 1 || result == 2 {
+		message = state.GetPlayers()[result-1].Username + " won"
+	}
+	lib.SetText("game-status", "Watching - "+message)
+	lib.SetStyle("game-status", "color", "var(--text-secondary)")
+}
+
+// handleToggleSettings opens or closes the settings panel
+func handleToggleSettings(this js.Value, args []js.Value) interface{} {
+	panel := lib.GetElement("settings-panel")
+	if panel.IsNull() {
+		return nil
+	}
+
+	if panel.Get("classList").Call("contains", "d-none").Bool() {
+		lib.ShowFlex("settings-panel")
+		lib.FocusFirst("setting-render-style")
+	} else {
+		lib.Hide("settings-panel")
+		lib.FocusFirst("settings-btn")
+	}
+	return nil
+}
+
+// handleRenderStyleChange switches between glossy and flat tokens
+func handleRenderStyleChange(this js.Value, args []js.Value) interface{} {
+	lib.GetSettings().SetRenderStyle(lib.GetValue("setting-render-style"))
+	lib.RefreshBoard()
+	return nil
+}
+
+// handleDropEasingChange picks how discs fall into place
+func handleDropEasingChange(this js.Value, args []js.Value) interface{} {
+	lib.GetSettings().SetDropEasing(lib.GetValue("setting-drop-easing"))
+	return nil
+}
+
+// handleHighlightColorChange changes the color of the last move ring
+func handleHighlightColorChange(this js.Value, args []js.Value) interface{} {
+	lib.GetSettings().SetHighlightColor(lib.GetValue("setting-highlight-color"))
+	lib.Draw()
+	return nil
+}
+
+// handleHighlightPulseChange toggles the pulsing last move ring
+func handleHighlightPulseChange(this js.Value, args []js.Value) interface{} {
+	lib.GetSettings().SetHighlightPulse(lib.GetChecked("setting-highlight-pulse"))
+	lib.Draw()
+	return nil
+}
+
+// handleSyncPrefsChange toggles saving preferences to the server
+func handleSyncPrefsChange(this js.Value, args []js.Value) interface{} {
+	lib.GetSettings().SetSyncPrefs(lib.GetChecked("setting-sync-prefs"))
+	return nil
+}
+
+// handleDiscSkinChange switches the disc images and board color
+func handleDiscSkinChange(this js.Value, args []js.Value) interface{} {
+	lib.GetSettings().SetDiscSkin(lib.GetValue("setting-disc-skin"))
+	lib.RefreshBoard()
+	return nil
+}
+
+// handleWinningPreviewChange toggles the winning move coaching hint
+func handleWinningPreviewChange(this js.Value, args []js.Value) interface{} {
+	lib.GetSettings().SetWinningPreview(lib.GetChecked("setting-winning-preview"))
+	lib.Draw()
+	return nil
+}
+
+// handleBlockHintsChange toggles the forced block coaching hint
+func handleBlockHintsChange(this js.Value, args []js.Value) interface{} {
+	lib.GetSettings().SetBlockHints(lib.GetChecked("setting-block-hints"))
+	lib.Draw()
+	return nil
+}
+
+// handleGravityTrailChange toggles the hover drop path
+func handleGravityTrailChange(this js.Value, args []js.Value) interface{} {
+	lib.GetSettings().SetGravityTrail(lib.GetChecked("setting-gravity-trail"))
+	lib.Draw()
+	return nil
+}
+
+// handleMirrorBoardChange toggles the mirrored board for the second player
+func handleMirrorBoardChange(this js.Value, args []js.Value) interface{} {
+	lib.GetSettings().SetMirrorBoard(lib.GetChecked("setting-mirror-board"))
+	lib.Get().ClearHover()
+	lib.Draw()
+	return nil
+}
+
+// handleHighContrastChange toggles the high contrast palette and rebuilds the board frame
+func handleHighContrastChange(this js.Value, args []js.Value) interface{} {
+	lib.GetSettings().SetHighContrast(lib.GetChecked("setting-high-contrast"))
+	lib.RefreshBoard()
+	updatePlayers()
+	return nil
+}
+
+// handleStreamPaletteChange swaps to capture friendly token colors and rebuilds the board frame
+func handleStreamPaletteChange(this js.Value, args []js.Value) interface{} {
+	lib.GetSettings().SetStreamPalette(lib.GetChecked("setting-stream-palette"))
+	lib.RefreshBoard()
+	updatePlayers()
+	return nil
+}
+
+// handleMoveNumbersChange toggles the order of play drawn on tokens
+func handleMoveNumbersChange(this js.Value, args []js.Value) interface{} {
+	lib.GetSettings().SetMoveNumbers(lib.GetChecked("setting-move-numbers"))
+	lib.Draw()
+	return nil
+}
+
+// handleMeFirstChange reorders the player cards, the thinking hint waits for the next bot turn
+func handleMeFirstChange(this js.Value, args []js.Value) interface{} {
+	lib.GetSettings().SetMeFirst(lib.GetChecked("setting-me-first"))
+	hideThinking()
+	updatePlayers()
+	updateSeries()
+	lib.UpdateDisplay()
+	return nil
+}
+
+// handleConfirmMovesChange toggles the move confirmation step
+func handleConfirmMovesChange(this js.Value, args []js.Value) interface{} {
+	enabled := lib.GetChecked("setting-confirm-moves")
+	lib.GetSettings().SetConfirmMoves(enabled)
+	if !enabled {
+		lib.DisarmMove()
+	}
+	return nil
+}
+
+// handleTurnAlertsChange toggles background turn alerts and asks for notification permission
+func handleTurnAlertsChange(this js.Value, args []js.Value) interface{} {
+	enabled := lib.GetChecked("setting-turn-alerts")
+	lib.GetSettings().SetTurnAlerts(enabled)
+	if enabled {
+		lib.RequestNotificationPermission()
+	} else {
+		lib.ClearTurnAlert()
+	}
+	return nil
+}
+
+// handleAutoRematchChange toggles automatic replay requests
+func handleAutoRematchChange(this js.Value, args []js.Value) interface{} {
+	lib.GetSettings().SetAutoRematch(lib.GetChecked("setting-auto-rematch"))
+	return nil
+}
+
 // autoConnect attempts to reconnect with saved credentials
-func autoConnect(username, savedPlayerID string) {
+func autoConnect(username, savedToken string) {
 	done := make(chan struct{})
 	timedOut := make(chan struct{})
 	var once sync.Once
@@ -283,7 +996,7 @@ func autoConnect(username, savedPlayerID string) {
 		case <-time.After(autoConnectTimeout):
 			close(timedOut)
 			lib.Console("Auto-reconnect timeout - clearing saved credentials")
-			lib.RemoveLocalStorage("playerID")
+			lib.RemoveLocalStorage("resumeToken")
 			lib.RemoveLocalStorage("username")
 			lib.Close()
 			lib.ShowScreen("login")
@@ -304,7 +1017,7 @@ func autoConnect(username, savedPlayerID string) {
 		handleMessage(msg)
 	}
 
-	lib.Connect(username, savedPlayerID, customHandler)
+	lib.Connect(username, savedToken, customHandler)
 }
 
 // ============================================================================
@@ -326,12 +1039,48 @@ func handleMessage(msg lib.Message) {
 		handleMove(msg.Data)
 	case "game_over":
 		handleGameOver(msg.Data)
+	case "time_sync":
+		handleTimeSync(msg.Data)
 	case "replay_request":
 		handleReplayRequest(msg.Data)
+	case "replay_declined":
+		handleReplayDeclined(msg.Data)
 	case "matchmaking_searching":
 		handleMatchmakingSearching(msg.Data)
 	case "queue_update":
 		handleQueueUpdate(msg.Data)
+	case "ready_check":
+		handleReadyCheck(msg.Data)
+	case "ready_check_failed":
+		handleReadyCheckFailed(msg.Data)
+	case "lobby_chat":
+		handleLobbyChat(msg.Data)
+	case "cooldown":
+		handleCooldown(msg.Data)
+	case "opponent_thinking":
+		handleOpponentThinking(msg.Data)
+	case "opponent_disconnected":
+		handlePresence(msg.Data, true)
+	case "opponent_reconnected":
+		handlePresence(msg.Data, false)
+	case "stats":
+		handleStats(msg.Data)
+	case "puzzle_next":
+		handlePuzzleNext(msg.Data)
+	case "featured_game":
+		handleFeaturedGame(msg.Data)
+	case "history_response":
+		handleHistoryResponse(msg.Data)
+	case "puzzle_result":
+		handlePuzzleResult(msg.Data)
+	case "announcement":
+		handleAnnouncement(msg.Data)
+	case "server_shutdown":
+		handleServerShutdown()
+	case "session_taken":
+		handleSessionTaken(msg.Data)
+	case "version_mismatch":
+		handleVersionMismatch(msg.Data)
 	case "error":
 		handleError(msg.Data)
 	}
@@ -348,17 +1097,54 @@ func handleWelcome(data interface{}) {
 	state := lib.Get()
 	state.SetPlayerID(welcome.PlayerID)
 
-	lib.SetLocalStorage("playerID", welcome.PlayerID)
+	// Only the signed token is persisted, the player ID stays in memory
+	lib.RemoveLocalStorage("playerID")
+	lib.SetLocalStorage("resumeToken", welcome.ResumeToken)
 	lib.SetLocalStorage("username", welcome.Username)
 
 	lib.SetText("header-username", welcome.Username)
 	lib.ShowFlex("header-user-info")
+	applyUnlocks(welcome.Unlocks)
+	lib.Get().SetCapabilities(welcome.Capabilities)
+	applyCapabilities()
+	checkBuild(welcome.BuildHash)
+
+	// Synced preferences from another device win over the local ones
+	if lib.GetSettings().GetSyncPrefs() {
+		if len(welcome.Prefs) > 0 {
+			lib.MergePrefs(welcome.Prefs)
+			syncSettingsControls()
+			lib.RefreshBoard()
+		} else {
+			lib.PushPrefs()
+		}
+	}
 
 	resetLobby()
 	lib.Show("mode-selection")
 	lib.Hide("friend-mode-panel")
 	lib.Hide("matchmaking-panel")
 	lib.ShowScreen("lobby")
+
+	if findGameOnWelcome {
+		findGameOnWelcome = false
+		handleMatchmakingMode(js.Undefined(), nil)
+	}
+}
+
+// applyCapabilities hides the features the server does not offer
+func applyCapabilities() {
+	capabilities := lib.Get().GetCapabilities()
+
+	lib.ToggleClass("mode-friend", "d-none", !capabilities.FriendGames)
+	lib.ToggleClass("mode-matchmaking", "d-none", !capabilities.Matchmaking)
+	lib.ToggleClass("find-game-btn", "d-none", !capabilities.Matchmaking)
+	lib.ToggleClass("lobby-chat", "d-none", !capabilities.Chat)
+	lib.ToggleClass("best-of-option", "d-none", !capabilities.Series)
+	lib.ToggleClass("puzzle-rush-btn", "d-none", !capabilities.Puzzles)
+	if !capabilities.Series {
+		lib.SetValue("best-of-select", "1")
+	}
 }
 
 // handleGameCreated processes game created confirmation
@@ -370,6 +1156,9 @@ func handleGameCreated(data interface{}) {
 	}
 
 	lib.Get().SetGameCode(created.Code)
+	if created.Invite != "" {
+		lib.SetText("waiting-title", "Waiting for "+created.Invite+"...")
+	}
 	showWaitingArea()
 }
 
@@ -381,6 +1170,20 @@ func handleGameStart(data interface{}) {
 		return
 	}
 
+	// A live game always takes over from an offline replay, a puzzle rush or a watched game
+	if lib.Get().IsReplaying() {
+		stopReplay()
+	}
+	if lib.Get().IsWatching() {
+		stopWatching()
+	}
+	if lib.Get().InPuzzle() {
+		stopPuzzle()
+	}
+	if lib.Get().IsEditing() {
+		stopEditor()
+	}
+
 	state := lib.Get()
 	state.SetGameCode(start.Code)
 	state.SetCurrentTurn(start.CurrentTurn)
@@ -388,13 +1191,23 @@ func handleGameStart(data interface{}) {
 	state.SetReplayRequested(false)
 	state.SetOpponentRequestedReplay(false)
 	state.SetTimeRemaining(start.TimeRemaining)
+	state.SetReplayAllowed(start.AllowReplay)
+	state.SetRanked(start.Ranked)
+	state.SetPaused(false)
 	state.SetGameFinished(false)
+	state.SetSeries(lib.Series{BestOf: start.BestOf, Score: start.Score})
+	lib.StopGraceCountdown()
+	hideThinking()
+	clearPresence()
+	clearMessage("unlock-message")
+	lib.Hide("rating-change")
 
 	state.ResetBoard()
 	state.ClearHover()
 	state.FindPlayerIndex()
 
 	updatePlayers()
+	updateSeries()
 	hideGameCode()
 	hideReplayArea()
 	showGameActions()
@@ -402,6 +1215,28 @@ func handleGameStart(data interface{}) {
 	lib.Draw()
 	updateGameStatus()
 	lib.Start()
+
+	// Matched players get a moment to see who they face, friends already know
+	if matchIntroPending {
+		matchIntroPending = false
+		if playerIdx := state.GetPlayerIdx(); playerIdx == 0 || playerIdx == 1 {
+			showMatchIntro(start.Players[1-playerIdx].Username)
+		}
+	}
+}
+
+// showMatchIntro covers the game screen with the opponent found transition
+// The game and its clocks start underneath so nothing drifts from the server
+func showMatchIntro(opponent string) {
+	lib.SetText("match-intro-name", fmt.Sprintf("Opponent found: %s!", opponent))
+	lib.Show("match-intro")
+
+	if matchIntroTimer != nil {
+		matchIntroTimer.Stop()
+	}
+	matchIntroTimer = time.AfterFunc(matchIntroDuration, func() {
+		lib.Hide("match-intro")
+	})
 }
 
 // handleGameState processes full game state (reconnection)
@@ -412,12 +1247,22 @@ func handleGameState(data interface{}) {
 		return
 	}
 
+	// A live game always takes over from an offline replay
+	if lib.Get().IsReplaying() {
+		stopReplay()
+	}
+
 	state := lib.Get()
 	state.SetGameCode(gameState.Code)
 	state.SetCurrentTurn(gameState.CurrentTurn)
 	state.SetBoard(gameState.Board)
+	state.SetMoveOrder(gameState.Moves)
 	state.SetPlayers(gameState.Players)
 	state.SetTimeRemaining(gameState.TimeRemaining)
+	state.SetReplayAllowed(gameState.AllowReplay)
+	state.SetRanked(gameState.Ranked)
+	state.SetPaused(gameState.Paused)
+	state.SetSeries(lib.Series{BestOf: gameState.BestOf, Score: gameState.Score, Over: gameState.SeriesOver, Result: gameState.SeriesResult})
 
 	state.FindPlayerIndex()
 
@@ -437,6 +1282,14 @@ func handleGameState(data interface{}) {
 	}
 
 	updatePlayers()
+	updateSeries()
+
+	// Tell reconnecting players how long an idle game is kept
+	if gameState.Status == 0 {
+		lib.StartGraceCountdown("waiting-grace", gameState.GraceRemaining)
+	} else {
+		lib.StartGraceCountdown("game-grace", gameState.GraceRemaining)
+	}
 
 	switch gameState.Status {
 	case 1: // Playing
@@ -448,7 +1301,12 @@ func handleGameState(data interface{}) {
 		lib.ShowScreen("game")
 		lib.Draw()
 		updateGameStatus()
-		lib.Start()
+		if gameState.Paused {
+			lib.Stop()
+			lib.UpdateDisplay()
+		} else {
+			lib.Start()
+		}
 
 	case 0: // Waiting
 		state.SetGameFinished(false)
@@ -464,7 +1322,7 @@ func handleGameState(data interface{}) {
 		showGameActions()
 		lib.ShowScreen("game")
 		lib.Draw()
-		showGameOver(gameState.Result)
+		showGameOver(gameState.Result, gameState.DrawReason)
 		lib.Stop()
 	}
 }
@@ -478,13 +1336,18 @@ func handleMove(data interface{}) {
 	}
 
 	state := lib.Get()
+	pending := state.TakePendingMove()
 	state.SetBoard(move.Board)
 	state.SetCurrentTurn(move.NextTurn)
 	state.SetTimeRemaining(move.TimeRemaining)
 	state.SetLastMove(move.Column, move.Row)
+	state.RecordMove(move.Column, move.Row)
 
-	playedBy := 1 - move.NextTurn
-	lib.AnimateDrop(move.Column, move.Row, playedBy)
+	// Our predicted token is already on the board, do not drop it twice
+	if pending == nil || pending.Col != move.Column || pending.Row != move.Row {
+		playedBy := 1 - move.NextTurn
+		lib.AnimateDrop(move.Column, move.Row, playedBy)
+	}
 	updateGameStatus()
 }
 
@@ -498,10 +1361,20 @@ func handleGameOver(data interface{}) {
 
 	state := lib.Get()
 	state.SetBoard(gameOver.Board)
+	state.SetWinningCells(gameOver.WinningCells)
 	state.SetGameFinished(true)
+	series := state.GetSeries()
+	series.Score = gameOver.Score
+	series.Over = gameOver.SeriesOver
+	series.Result = gameOver.SeriesResult
+	state.SetSeries(series)
+	updateSeries()
+	lib.DisarmMove()
+	hideThinking()
 	lib.Draw()
-	showGameOver(gameOver.Result)
+	showGameOver(gameOver.Result, gameOver.DrawReason)
 	lib.Stop()
+	autoRequestReplay()
 }
 
 // handleReplayRequest processes replay request from opponent
@@ -514,8 +1387,27 @@ func handleReplayRequest(data interface{}) {
 
 	state := lib.Get()
 	if req.PlayerIdx != state.GetPlayerIdx() {
+		// A different seat choice replaces our request, the opponent waits for us to accept theirs
+		if state.IsReplayRequested() && req.KeepSeats != lib.GetChecked("keep-seats") {
+			state.SetReplayRequested(false)
+		}
+		lib.SetChecked("keep-seats", req.KeepSeats)
 		state.SetOpponentRequestedReplay(true)
 		updateReplayButton()
+		autoRequestReplay()
+	}
+}
+
+// handleReplayDeclined stops waiting for a rematch the opponent will not play
+func handleReplayDeclined(data interface{}) {
+	state := lib.Get()
+	state.SetRematchDeclined(true)
+	lib.Hide("auto-rematch-indicator")
+
+	button := lib.GetElement("replay-btn")
+	if !button.IsNull() {
+		button.Set("textContent", "Opponent left")
+		button.Set("disabled", true)
 	}
 }
 
@@ -546,6 +1438,264 @@ func handleQueueUpdate(data interface{}) {
 	}
 }
 
+// handleLobbyChat shows a message sent to the lobby
+func handleLobbyChat(data interface{}) {
+	var chat lib.LobbyChatMessageData
+	if err := remarshal(data, &chat); err != nil {
+		return
+	}
+	lib.AppendLobbyChat(chat.Tag, chat.Username, chat.Text)
+}
+
+// handleReadyCheck asks the player to confirm a found match
+func handleReadyCheck(data interface{}) {
+	var check lib.ReadyCheckData
+	if err := remarshal(data, &check); err != nil {
+		return
+	}
+
+	lib.Hide("matchmaking-searching")
+	lib.Show("ready-check")
+	lib.Show("ready-check-actions")
+	lib.SetText("ready-check-status", fmt.Sprintf("Confirm you are ready to play within %ds", check.TimeoutSeconds))
+	lib.FocusFirst("ready-accept-btn")
+	lib.NotifyTurn()
+}
+
+// handleReadyCheckFailed resumes searching or returns to mode selection after a cancelled match
+func handleReadyCheckFailed(data interface{}) {
+	var failed lib.ReadyCheckFailedData
+	if err := remarshal(data, &failed); err != nil {
+		return
+	}
+
+	lib.Hide("ready-check")
+	lib.ClearTurnAlert()
+
+	if failed.Requeued {
+		lib.Show("matchmaking-searching")
+		lib.SetText("matchmaking-status", "Opponent wasn't ready, searching again...")
+		return
+	}
+
+	lib.Hide("matchmaking-panel")
+	lib.Show("mode-selection")
+	lib.ShowMessage("lobby-message", "Match cancelled.", "error")
+
+	time.AfterFunc(errorMessageDisplayTime, func() {
+		clearMessage("lobby-message")
+	})
+}
+
+// handleOpponentThinking toggles the thinking indicator on a bot's player card
+func handleOpponentThinking(data interface{}) {
+	var thinking lib.ThinkingData
+	if err := remarshal(data, &thinking); err != nil {
+		lib.Console("handleOpponentThinking: remarshal failed: " + err.Error())
+		return
+	}
+
+	if thinking.PlayerIdx < 0 || thinking.PlayerIdx > 1 {
+		return
+	}
+	if thinking.Thinking {
+		lib.Show(fmt.Sprintf("thinking-%d", lib.CardSlot(thinking.PlayerIdx)))
+	} else {
+		lib.Hide(fmt.Sprintf("thinking-%d", lib.CardSlot(thinking.PlayerIdx)))
+	}
+}
+
+// hideThinking clears the thinking indicators of both player cards
+func hideThinking() {
+	lib.Hide("thinking-0")
+	lib.Hide("thinking-1")
+}
+
+// handlePresence marks a side as disconnected or back and freezes its clock while paused
+func handlePresence(data interface{}, offline bool) {
+	var presence lib.PresenceData
+	if err := remarshal(data, &presence); err != nil {
+		lib.Console("handlePresence: remarshal failed: " + err.Error())
+		return
+	}
+
+	if presence.PlayerIdx < 0 || presence.PlayerIdx > 1 {
+		return
+	}
+
+	state := lib.Get()
+	state.SetPlayerOffline(presence.PlayerIdx, offline)
+	state.SetPaused(presence.Paused)
+	showPresence(presence.PlayerIdx)
+	lib.UpdateDisplay()
+	updateGameStatus()
+}
+
+// showPresence toggles the reconnecting indicator of a player card
+func showPresence(idx int) {
+	cardID := fmt.Sprintf("player-%d", lib.CardSlot(idx))
+	reconnectingID := fmt.Sprintf("reconnecting-%d", lib.CardSlot(idx))
+	if lib.Get().IsPlayerOffline(idx) {
+		lib.AddClass(cardID, "offline")
+		lib.Show(reconnectingID)
+	} else {
+		lib.RemoveClass(cardID, "offline")
+		lib.Hide(reconnectingID)
+	}
+}
+
+// clearPresence marks both sides as connected
+func clearPresence() {
+	state := lib.Get()
+	for i := 0; i < 2; i++ {
+		state.SetPlayerOffline(i, false)
+		showPresence(i)
+	}
+}
+
+// handleStats enables newly unlocked skins after a win and shows the win streak
+func handleStats(data interface{}) {
+	var stats lib.StatsData
+	if err := remarshal(data, &stats); err != nil {
+		lib.Console("handleStats: remarshal failed: " + err.Error())
+		return
+	}
+
+	// A single win is not much of a streak yet
+	if stats.Streak >= 2 {
+		lib.SetText("win-streak", fmt.Sprintf("🔥 %d win streak", stats.Streak))
+		lib.GetElement("win-streak").Set("title", fmt.Sprintf("Best this session: %d", stats.BestStreak))
+		lib.Show("win-streak")
+	} else {
+		lib.Hide("win-streak")
+	}
+
+	// Only ranked games move the rating
+	if stats.RatingGain != 0 {
+		lib.SetText("rating-change", fmt.Sprintf("Rating %d (%+d)", stats.Rating, stats.RatingGain))
+		lib.Show("rating-change")
+	} else {
+		lib.Hide("rating-change")
+	}
+
+	applyUnlocks(stats.Unlocks)
+	for _, name := range stats.NewUnlocks {
+		lib.ShowMessage("unlock-message", fmt.Sprintf("%d wins! New disc skin unlocked: %s", stats.Wins, name), "success")
+	}
+}
+
+// applyUnlocks enables the skin options the player has earned
+func applyUnlocks(unlocks []string) {
+	lib.SetUnlocks(unlocks)
+	for _, name := range lib.LockedSkins() {
+		option := lib.GetElement("skin-option-" + name)
+		if !option.IsNull() {
+			option.Set("disabled", !lib.IsSkinUnlocked(name))
+		}
+	}
+	lib.RefreshBoard()
+}
+
+// handleCooldown returns to mode selection when matchmaking is temporarily blocked
+func handleCooldown(data interface{}) {
+	var cooldown lib.CooldownData
+	if err := remarshal(data, &cooldown); err != nil {
+		return
+	}
+
+	lib.Hide("matchmaking-panel")
+	lib.Hide("matchmaking-searching")
+	lib.Show("mode-selection")
+
+	lib.ShowMessage("lobby-message", fmt.Sprintf("Matchmaking unavailable for %ds.", cooldown.SecondsRemaining), "error")
+
+	time.AfterFunc(errorMessageDisplayTime, func() {
+		clearMessage("lobby-message")
+	})
+}
+
+// handleSessionTaken leaves a tab whose session was taken over by a newer login
+// Saved credentials are kept, they belong to the tab now playing
+func handleSessionTaken(data interface{}) {
+	disconnect()
+	lib.ShowMessage("login-message", "Logged in elsewhere.", "error")
+}
+
+// handleAnnouncement shows an operator notice above the current screen
+func handleAnnouncement(data interface{}) {
+	var announcement lib.AnnouncementData
+	if err := remarshal(data, &announcement); err != nil {
+		lib.Console("handleAnnouncement: remarshal failed: " + err.Error())
+		return
+	}
+
+	lib.SetText("announcement-text", announcement.Text)
+	lib.ToggleClass("announcement-banner", "warning", announcement.Level == "warning")
+	lib.ShowFlex("announcement-banner")
+}
+
+// handleTimeSync corrects the local countdown with the server clocks
+func handleTimeSync(data interface{}) {
+	var clocks lib.TimeSyncData
+	if err := remarshal(data, &clocks); err != nil {
+		lib.Console("handleTimeSync: remarshal failed: " + err.Error())
+		return
+	}
+
+	lib.Reconcile(clocks.TimeRemaining, clocks.CurrentTurn)
+}
+
+// handleServerShutdown warns that the server is going away, the connection closes right after
+func handleServerShutdown() {
+	lib.SetText("announcement-text", "The server is shutting down, games in progress are lost.")
+	lib.ToggleClass("announcement-banner", "warning", true)
+	lib.ShowFlex("announcement-banner")
+}
+
+// handleDismissAnnouncement hides the operator notice until the next one
+func handleDismissAnnouncement(this js.Value, args []js.Value) interface{} {
+	lib.Hide("announcement-banner")
+	return nil
+}
+
+// checkBuild warns when the server runs another build than this cached bundle
+// Development builds and servers that do not announce one are trusted
+func checkBuild(serverHash string) {
+	if buildHash == lib.DevBuild || serverHash == "" || serverHash == lib.DevBuild || serverHash == buildHash {
+		lib.Hide("update-banner")
+		return
+	}
+
+	lib.Console(fmt.Sprintf("Stale bundle: client %s, server %s", buildHash, serverHash))
+	lib.ShowFlex("update-banner")
+}
+
+// handleReloadPage reloads the page to fetch the current bundle
+func handleReloadPage(this js.Value, args []js.Value) interface{} {
+	js.Global().Get("location").Call("reload")
+	return nil
+}
+
+// handleVersionMismatch warns that the client and server protocols differ
+func handleVersionMismatch(data interface{}) {
+	var mismatch lib.VersionMismatchData
+	if err := remarshal(data, &mismatch); err != nil {
+		return
+	}
+
+	lib.Console(fmt.Sprintf("Protocol mismatch: client v%d, server v%d", mismatch.ClientVersion, mismatch.ServerVersion))
+	lib.ShowMessage("lobby-message", "A new version of GOnnect4 is available, please refresh the page", "error")
+}
+
+// rejectedMoveCodes are the error codes the server answers an illegal move with
+// A move out of turn is answered with a fresh game state instead, which drops the prediction itself
+var rejectedMoveCodes = map[string]bool{
+	"OUT_OF_RANGE":     true,
+	"COLUMN_FULL":      true,
+	"GAME_NOT_PLAYING": true,
+	"GAME_PAUSED":      true,
+}
+
 // handleError processes error messages
 func handleError(data interface{}) {
 	var errData lib.ErrorData
@@ -553,6 +1703,12 @@ func handleError(data interface{}) {
 		return
 	}
 
+	// A rejected move takes back the token we predicted, other errors leave it waiting for its echo
+	if rejectedMoveCodes[errData.Code] && lib.Get().RollbackPendingMove() {
+		lib.Draw()
+		updateGameStatus()
+	}
+
 	lib.ShowMessage("lobby-message", errData.Message, "error")
 
 	// Auto-clear error message after delay
@@ -58,3 +58,20 @@ func clearMessage(elementID string) {
 	lib.SetText(elementID, "")
 	lib.GetElement(elementID).Set("className", "message")
 }
+
+// formatDraw returns the end message of a draw including its reason
+func formatDraw(reason string) string {
+	switch reason {
+	case "agreement":
+		return "Draw by agreement"
+	case "stalemate":
+		return "Draw — stalemate"
+	case "move_cap":
+		return "Draw — move limit reached"
+	case "repetition":
+		return "Draw — position repeated three times"
+	default:
+		// Legacy servers send no reason, the board was full
+		return "Draw — board full"
+	}
+}
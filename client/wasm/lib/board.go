@@ -6,17 +6,22 @@
 
 package lib
 
-import "syscall/js"
+import (
+	"math"
+	"strconv"
+	"syscall/js"
+)
 
 // Board rendering constants
 const (
-	CellSize       = 80
 	TokenRadius    = 28
 	HighlightWidth = 8
 	ShineRadius    = 8
 	ShineOffset    = 8
 	ShineAlpha     = 0.65
 	PreviewAlpha   = 0.55
+	TrailAlpha     = 0.15
+	MoveNumberFont = "bold 18px 'Work Sans', sans-serif"
 )
 
 // Token colors
@@ -29,12 +34,15 @@ const (
 	ColorBoardBg      = "#00add8"
 	ColorBoardBorder  = "#5dc9e2"
 	ColorHighlight    = "#5dc9e2"
+	ColorWinningAlpha = "rgba(255, 196, 0, "
 )
 
 // Animation constants
 const (
 	dropAnimationDuration = 550 // milliseconds
 	dropStartY            = -TokenRadius * 2
+	winningPulsePeriod    = 900  // milliseconds
+	highlightPulsePeriod  = 1200 // milliseconds
 )
 
 var (
@@ -46,12 +54,30 @@ var (
 	// This optimization avoids redrawing the board frame every time and allows us to animate drops
 	boardOverlayCanvas js.Value
 	boardOverlayCtx    js.Value
+
+	// Render scheduler, Draw only marks the board dirty and frames are rendered at most once each
+	frameScheduled bool
+	renderFrame    js.Func
+	drop           *dropAnimation // Token currently falling, nil when idle
 )
 
-// Initialize sets up the canvas and creates the board overlay
+// dropAnimation describes a token falling into its cell
+type dropAnimation struct {
+	column, row int
+	centerX     float64
+	endY        float64
+	startTime   float64
+	owner       int
+}
+
+// Initialize picks the storage backend, sets up the canvas and creates the board overlay
 func Initialize() {
+	DetectLocalStorage()
+
 	canvas = js.Global().Get("document").Call("getElementById", "game-board")
 	if canvas.IsNull() {
+		Console("Initialize: game-board canvas not found")
+		ShowMessage("login-message", "The game board could not be created, please refresh the page", "error")
 		return
 	}
 	canvasContext = canvas.Call("getContext", "2d")
@@ -62,11 +88,72 @@ func Initialize() {
 	boardOverlayCanvas.Set("height", canvas.Get("height").Int())
 	boardOverlayCtx = boardOverlayCanvas.Call("getContext", "2d")
 
+	LoadSettings()
+	ApplyContrast()
+	loadSkins()
 	buildBoardOverlay()
 }
 
-// Draw renders the complete game board
+// RefreshBoard rebuilds the board overlay and redraws after a display setting changed
+func RefreshBoard() {
+	if boardOverlayCtx.IsUndefined() || boardOverlayCtx.IsNull() {
+		return
+	}
+	ApplyContrast()
+	buildBoardOverlay()
+	Draw()
+}
+
+// ApplyContrast reflects the high contrast and stream palette settings on the page styles
+func ApplyContrast() {
+	ToggleBodyClass("high-contrast", GetSettings().GetHighContrast())
+	ToggleBodyClass("stream-palette", GetSettings().GetStreamPalette())
+}
+
+// Draw marks the board dirty, it is rendered once on the next animation frame
+// State changes made before that frame are coalesced into a single render
 func Draw() {
+	if frameScheduled {
+		return
+	}
+	frameScheduled = true
+
+	if renderFrame.IsUndefined() {
+		renderFrame = js.FuncOf(func(this js.Value, args []js.Value) any {
+			frameScheduled = false
+			renderAt(args[0].Float())
+			return nil
+		})
+	}
+	js.Global().Call("requestAnimationFrame", renderFrame)
+}
+
+// renderAt draws the frame for a point in time, a running drop animation takes over the board
+func renderAt(now float64) {
+	if canvasContext.IsUndefined() || canvasContext.IsNull() {
+		return
+	}
+
+	if drop != nil {
+		progress := (now - drop.startTime) / dropAnimationDuration
+		if progress < 0 {
+			progress = 0
+		}
+		if progress < 1 {
+			eased := EaseDrop(settings.GetDropEasing(), progress)
+			currentY := dropStartY + (drop.endY-dropStartY)*eased
+			drawFrameFalling(drop.column, drop.row, drop.centerX, currentY, drop.owner)
+			Draw()
+			return
+		}
+		drop = nil
+	}
+
+	render()
+}
+
+// render draws the complete game board
+func render() {
 	if canvasContext.IsNull() {
 		return
 	}
@@ -91,9 +178,21 @@ func Draw() {
 	// Draw board overlay (with holes)
 	canvasContext.Call("drawImage", boardOverlayCanvas, 0, 0)
 
+	// Coaching hint: point at the only column stopping the opponent's next move win
+	if showBlockHint() {
+		if column := ForcedBlockColumn(board, state.GetPlayerIdx()+1); column >= 0 {
+			drawBlockArrow(column)
+		}
+	}
+
+	// Connect the tokens of the line that won the round
+	if cells := state.GetWinningCells(); len(cells) >= 2 {
+		drawWinningLine(cells)
+	}
+
 	// Draw highlight on last move
 	if lastMove != nil {
-		centerX := lastMove.Col*CellSize + CellSize/2
+		centerX := ColumnCenterX(lastMove.Col, isMirrored())
 		centerY := lastMove.Row*CellSize + CellSize/2
 		drawHighlight(centerX, centerY)
 	}
@@ -101,13 +200,19 @@ func Draw() {
 
 // drawPlacedTokens renders all tokens currently on the board
 func drawPlacedTokens(board [Rows][Cols]int) {
+	mirrored := isMirrored()
+	showNumbers := GetSettings().GetMoveNumbers()
+	numbers := Get().GetMoveNumbers()
 	for row := 0; row < Rows; row++ {
 		for col := 0; col < Cols; col++ {
 			owner := board[row][col]
 			if owner > 0 {
-				centerX := col*CellSize + CellSize/2
+				centerX := ColumnCenterX(col, mirrored)
 				centerY := row*CellSize + CellSize/2
 				drawToken(centerX, centerY, owner, 1.0)
+				if showNumbers {
+					drawMoveNumber(centerX, centerY, owner, numbers[row][col])
+				}
 			}
 		}
 	}
@@ -119,54 +224,156 @@ func drawHoverPreview(column int, board [Rows][Cols]int) {
 	targetRow := findLowestEmptyRow(column, board)
 
 	if targetRow >= 0 {
-		centerX := column*CellSize + CellSize/2
+		centerX := ColumnCenterX(column, isMirrored())
 		centerY := targetRow*CellSize + CellSize/2
 		playerToken := Get().GetPlayerIdx() + 1
+
+		// Drawn before the overlay so the trail only shows through the holes
+		if GetSettings().GetGravityTrail() {
+			drawGravityTrail(column, targetRow, playerToken)
+		}
+
 		drawToken(centerX, centerY, playerToken, PreviewAlpha)
+
+		// Coaching hint: emphasize a ghost token that would complete a line
+		if showWinningPreview() && WouldWin(board, column, playerToken) {
+			drawWinningGlow(centerX, centerY)
+		}
 	}
 }
 
-// findLowestEmptyRow returns the lowest empty row in a column, or -1 if full
-func findLowestEmptyRow(column int, board [Rows][Cols]int) int {
-	for row := Rows - 1; row >= 0; row-- {
-		if board[row][column] == 0 {
-			return row
-		}
+// drawGravityTrail draws a faint column from the top of the board down to the landing cell
+func drawGravityTrail(column, targetRow, owner int) {
+	canvasContext.Set("fillStyle", tokenColorAlpha(owner-1)+formatAlpha(TrailAlpha)+")")
+	canvasContext.Call("fillRect", DisplayColumn(column, isMirrored())*CellSize, 0, CellSize, targetRow*CellSize+CellSize/2)
+}
+
+// showWinningPreview checks if the winning move hint is enabled for this game
+func showWinningPreview() bool {
+	return GetSettings().GetWinningPreview() && !Get().GetRanked()
+}
+
+// showBlockHint checks if the forced block hint applies, never in ranked games or puzzles
+func showBlockHint() bool {
+	state := Get()
+	return GetSettings().GetBlockHints() && !state.GetRanked() && !state.InPuzzle() &&
+		state.IsMyTurn() && !state.GetGameFinished() && !state.IsPaused()
+}
+
+// drawBlockArrow draws a downward arrow at the top of a column
+func drawBlockArrow(column int) {
+	centerX := float64(ColumnCenterX(column, isMirrored()))
+	halfWidth := float64(CellSize) * 0.2
+
+	canvasContext.Call("beginPath")
+	canvasContext.Call("moveTo", centerX-halfWidth, 4)
+	canvasContext.Call("lineTo", centerX+halfWidth, 4)
+	canvasContext.Call("lineTo", centerX, 4+halfWidth*1.5)
+	canvasContext.Call("closePath")
+	canvasContext.Set("fillStyle", ColorWinningAlpha+"0.9)")
+	canvasContext.Call("fill")
+	canvasContext.Set("strokeStyle", borderColor())
+	canvasContext.Set("lineWidth", 2)
+	canvasContext.Call("stroke")
+}
+
+// drawWinningGlow draws a pulsing gold disc over a winning ghost token
+func drawWinningGlow(centerX, centerY int) {
+	now := js.Global().Get("performance").Call("now").Float()
+	pulse := 0.5 + 0.5*math.Sin(2*math.Pi*now/winningPulsePeriod)
+
+	canvasContext.Call("beginPath")
+	canvasContext.Call("arc", centerX, centerY, TokenRadius, 0, 2*3.14159)
+	canvasContext.Set("fillStyle", ColorWinningAlpha+formatAlpha(0.25+0.5*pulse)+")")
+	canvasContext.Call("fill")
+
+	// Keep redrawing while the pulse is visible
+	Draw()
+}
+
+// isMirrored checks if columns are drawn right to left for the second player
+func isMirrored() bool {
+	return GetSettings().GetMirrorBoard() && Get().GetPlayerIdx() == 1
+}
+
+// CardSlot returns the player card showing a seat, 0 being the top or left card
+// Seats map to cards directly unless the local player asked to be shown first
+func CardSlot(seat int) int {
+	if GetSettings().GetMeFirst() && Get().GetPlayerIdx() == 1 {
+		return 1 - seat
+	}
+	return seat
+}
+
+// PlayerColor returns the token color of a seat
+func PlayerColor(seat int) string {
+	return tokenColor(seat)
+}
+
+// PlayerColorName returns a readable name for the token color of a seat
+func PlayerColorName(seat int) string {
+	switch {
+	case seat == 1 && GetSettings().GetStreamPalette():
+		return "Blue"
+	case seat == 1:
+		return "Yellow"
 	}
-	return -1
+	return "Red"
 }
 
 // drawToken draws a single game token
 func drawToken(centerX, centerY, owner int, alpha float64) {
+	if image, ok := discImage(owner); ok {
+		canvasContext.Set("globalAlpha", alpha)
+		canvasContext.Call("drawImage", image, centerX-TokenRadius, centerY-TokenRadius, 2*TokenRadius, 2*TokenRadius)
+		canvasContext.Set("globalAlpha", 1)
+		return
+	}
+
 	canvasContext.Call("beginPath")
 	canvasContext.Call("arc", centerX, centerY, TokenRadius, 0, 2*3.14159)
 
 	// Set token color based on owner
-	switch owner {
-	case 0:
+	switch {
+	case owner == 0:
 		canvasContext.Set("fillStyle", ColorEmpty)
-	case 1:
-		if alpha < 1.0 {
-			canvasContext.Set("fillStyle", ColorPlayer0Alpha+formatAlpha(alpha)+")")
-		} else {
-			canvasContext.Set("fillStyle", ColorPlayer0)
-		}
-	case 2:
-		if alpha < 1.0 {
-			canvasContext.Set("fillStyle", ColorPlayer1Alpha+formatAlpha(alpha)+")")
-		} else {
-			canvasContext.Set("fillStyle", ColorPlayer1)
-		}
+	case alpha < 1.0:
+		canvasContext.Set("fillStyle", tokenColorAlpha(owner-1)+formatAlpha(alpha)+")")
+	default:
+		canvasContext.Set("fillStyle", tokenColor(owner-1))
 	}
 
 	canvasContext.Call("fill")
 
 	// Add shine effect for tokens
-	if owner > 0 {
+	if owner > 0 && !GetSettings().IsFlat() {
 		drawShineEffect(centerX, centerY, alpha)
 	}
 }
 
+// drawMoveNumber writes the order of play on a token, outlined so it reads on both colors
+// Tokens placed before the client joined have no known number and are left blank
+func drawMoveNumber(centerX, centerY, owner, number int) {
+	if number <= 0 {
+		return
+	}
+
+	text, outline := "#ffffff", ColorEmpty
+	if owner == 2 {
+		text, outline = ColorEmpty, "#ffffff"
+	}
+
+	label := strconv.Itoa(number)
+	canvasContext.Set("font", MoveNumberFont)
+	canvasContext.Set("textAlign", "center")
+	canvasContext.Set("textBaseline", "middle")
+	canvasContext.Set("lineWidth", 3)
+	canvasContext.Set("strokeStyle", outline)
+	canvasContext.Call("strokeText", label, centerX, centerY)
+	canvasContext.Set("fillStyle", text)
+	canvasContext.Call("fillText", label, centerX, centerY)
+}
+
 // drawShineEffect adds a shine highlight to tokens
 func drawShineEffect(centerX, centerY int, tokenAlpha float64) {
 	canvasContext.Call("beginPath")
@@ -178,11 +385,49 @@ func drawShineEffect(centerX, centerY int, tokenAlpha float64) {
 
 // drawHighlight draws a ring around the last played token
 func drawHighlight(centerX, centerY int) {
+	width := highlightWidth()
+	if GetSettings().GetHighlightPulse() && !Get().GetGameFinished() {
+		now := js.Global().Get("performance").Call("now").Float()
+		pulse := 0.5 + 0.5*math.Sin(2*math.Pi*now/highlightPulsePeriod)
+		width *= 0.5 + 0.5*pulse
+		Draw()
+	}
+
 	canvasContext.Call("beginPath")
 	canvasContext.Call("arc", centerX, centerY, TokenRadius, 0, 2*3.14159)
-	canvasContext.Set("strokeStyle", ColorHighlight)
+	canvasContext.Set("strokeStyle", highlightColor())
+	canvasContext.Set("lineWidth", width)
+	canvasContext.Call("stroke")
+
+	// Outline the inner edge so the ring stands out on red and yellow tokens alike
+	canvasContext.Call("beginPath")
+	canvasContext.Call("arc", centerX, centerY, TokenRadius-width/2, 0, 2*3.14159)
+	canvasContext.Set("strokeStyle", ColorEmpty)
+	canvasContext.Set("lineWidth", 2)
+	canvasContext.Call("stroke")
+}
+
+// drawWinningLine rings each token of the winning line and strokes a bar through their centers
+func drawWinningLine(cells [][2]int) {
+	mirrored := isMirrored()
+	centerX := func(cell [2]int) int { return ColumnCenterX(cell[1], mirrored) }
+	centerY := func(cell [2]int) int { return cell[0]*CellSize + CellSize/2 }
+
+	canvasContext.Set("strokeStyle", ColorWinningAlpha+"0.9)")
 	canvasContext.Set("lineWidth", HighlightWidth)
+	for _, cell := range cells {
+		canvasContext.Call("beginPath")
+		canvasContext.Call("arc", centerX(cell), centerY(cell), TokenRadius, 0, 2*math.Pi)
+		canvasContext.Call("stroke")
+	}
+
+	first, last := cells[0], cells[len(cells)-1]
+	canvasContext.Call("beginPath")
+	canvasContext.Call("moveTo", centerX(first), centerY(first))
+	canvasContext.Call("lineTo", centerX(last), centerY(last))
+	canvasContext.Set("lineCap", "round")
 	canvasContext.Call("stroke")
+	canvasContext.Set("lineCap", "butt")
 }
 
 // drawFrameFalling renders a single animation frame during token drop
@@ -197,6 +442,9 @@ func drawFrameFalling(excludeCol, excludeRow int, fallingX, fallingY float64, fa
 	canvasContext.Call("clearRect", 0, 0, canvasWidth, canvasHeight)
 
 	// Draw all placed tokens, skipping the one being animated
+	mirrored := isMirrored()
+	showNumbers := GetSettings().GetMoveNumbers()
+	numbers := state.GetMoveNumbers()
 	for row := 0; row < Rows; row++ {
 		for col := 0; col < Cols; col++ {
 			if col == excludeCol && row == excludeRow {
@@ -204,9 +452,12 @@ func drawFrameFalling(excludeCol, excludeRow int, fallingX, fallingY float64, fa
 			}
 			owner := board[row][col]
 			if owner > 0 {
-				centerX := col*CellSize + CellSize/2
+				centerX := ColumnCenterX(col, mirrored)
 				centerY := row*CellSize + CellSize/2
 				drawToken(centerX, centerY, owner, 1.0)
+				if showNumbers {
+					drawMoveNumber(centerX, centerY, owner, numbers[row][col])
+				}
 			}
 		}
 	}
@@ -225,7 +476,7 @@ func buildBoardOverlay() {
 
 	// Fill board background
 	boardOverlayCtx.Call("clearRect", 0, 0, overlayWidth, overlayHeight)
-	boardOverlayCtx.Set("fillStyle", ColorBoardBg)
+	boardOverlayCtx.Set("fillStyle", boardColor())
 	boardOverlayCtx.Call("fillRect", 0, 0, overlayWidth, overlayHeight)
 
 	// Punch out holes using destination-out compositing
@@ -246,13 +497,15 @@ func buildBoardOverlay() {
 	drawGridLines()
 
 	// Draw hole shadows for depth effect
-	drawHoleShadows()
+	if !GetSettings().IsFlat() {
+		drawHoleShadows()
+	}
 }
 
 // drawGridLines draws the board grid
 func drawGridLines() {
-	boardOverlayCtx.Set("strokeStyle", ColorBoardBorder)
-	boardOverlayCtx.Set("lineWidth", 2)
+	boardOverlayCtx.Set("strokeStyle", borderColor())
+	boardOverlayCtx.Set("lineWidth", borderWidth())
 	for row := 0; row < Rows; row++ {
 		for col := 0; col < Cols; col++ {
 			x := col * CellSize
@@ -278,57 +531,28 @@ func drawHoleShadows() {
 }
 
 // AnimateDrop creates a drop animation for a token falling into position
-// Uses requestAnimationFrame to create smooth 60fps animation with quadratic easing
 // Animation flow :
 //  1. Token starts above the board (dropStartY)
 //  2. Falls to final position (row) over dropAnimationDuration ms
-//  3. Uses quadratic easing (progress²) to simulate gravity acceleration
-//  4. Calls Draw() when complete to render final state with highlight
+//  3. Follows the easing picked in the settings: gravity (progress²), bounce or linear
+//  4. The render loop draws the final state with highlight once it lands, on the exact cell
+//
+// Frames go through the same scheduler as Draw, so state changes during the fall never render twice
 func AnimateDrop(column, row, playerIdx int) {
 	if canvasContext.IsNull() || canvas.IsNull() {
 		Draw()
 		return
 	}
 
-	// Step 1
-	centerX := float64(column*CellSize + CellSize/2)
-	endY := float64(row*CellSize + CellSize/2)
-	startTime := js.Global().Get("performance").Call("now").Float()
-	owner := playerIdx + 1
-
-	var animate js.Func
-	animate = js.FuncOf(func(this js.Value, args []js.Value) any {
-		// Step 2
-		currentTime := args[0].Float()
-		progress := (currentTime - startTime) / dropAnimationDuration
-
-		// Clamp progress to [0, 1]
-		if progress < 0 {
-			progress = 0
-		}
-		if progress > 1 {
-			progress = 1
-		}
-
-		// Step 3 (Quadratic easing progress² gives gravity-like acceleration)
-		eased := progress * progress
-		currentY := dropStartY + (endY-dropStartY)*eased
-
-		// Draw the falling token in canvas
-		drawFrameFalling(column, row, centerX, currentY, owner)
-
-		// Continue animation or finish
-		if progress < 1 {
-			js.Global().Call("requestAnimationFrame", animate)
-		} else {
-			// Step 4 final
-			animate.Release()
-			Draw()
-		}
-		return nil
-	})
-
-	js.Global().Call("requestAnimationFrame", animate)
+	drop = &dropAnimation{
+		column:    column,
+		row:       row,
+		centerX:   float64(ColumnCenterX(column, isMirrored())),
+		endY:      float64(row*CellSize + CellSize/2),
+		startTime: js.Global().Get("performance").Call("now").Float(),
+		owner:     playerIdx + 1,
+	}
+	Draw()
 }
 
 // formatAlpha formats alpha value for CSS rgba
@@ -346,14 +570,52 @@ func formatAlpha(alpha float64) string {
 func HandleClick(event js.Value) {
 	state := Get()
 
-	// Ignore clicks when game is finished or not player's turn
-	if state.GetGameFinished() || !state.IsMyTurn() {
+	// The editor edits the clicked cell instead of dropping a token
+	if editor := state.GetEditor(); editor != nil {
+		editor.CycleCell(getRowFromEvent(event), getColumnFromEvent(event))
+		state.SetBoard(editor.Board)
+		Draw()
+		UpdateEditorStatus()
+		return
+	}
+
+	// Ignore clicks when game is finished, paused or not player's turn
+	if state.GetGameFinished() || state.IsPaused() || !state.IsMyTurn() {
 		return
 	}
 
 	column := getColumnFromEvent(event)
-	if column >= 0 && column < Cols {
-		js.Global().Call("playColumn", column)
+	if column < 0 || column >= Cols {
+		return
+	}
+
+	// First click arms the column, a second click on it confirms the move
+	if GetSettings().GetConfirmMoves() && (!state.IsMoveArmed() || state.GetHoverCol() != column) {
+		state.ArmMove(column)
+		SetText("game-status", "Tap again to confirm")
+		Draw()
+		return
+	}
+
+	state.ClearHover()
+
+	// Drop the token right away, the server echo only confirms it
+	row := findLowestEmptyRow(column, state.GetBoard())
+	if row < 0 {
+		return
+	}
+	state.PredictMove(column, row)
+	AnimateDrop(column, row, state.GetPlayerIdx())
+
+	js.Global().Call("playColumn", column)
+}
+
+// DisarmMove drops a column waiting for confirmation
+func DisarmMove() {
+	state := Get()
+	if state.IsMoveArmed() {
+		state.ClearHover()
+		Draw()
 	}
 }
 
@@ -361,8 +623,13 @@ func HandleClick(event js.Value) {
 func HandleHover(event js.Value) {
 	state := Get()
 
-	// Hide hover when game is finished or not player's turn
-	if state.GetGameFinished() || !state.IsMyTurn() {
+	// Hide hover when game is finished, paused or not player's turn
+	if state.GetGameFinished() || state.IsPaused() || !state.IsMyTurn() {
+		return
+	}
+
+	// Keep the armed column visible until it is confirmed or another one is clicked
+	if state.IsMoveArmed() {
 		return
 	}
 
@@ -377,13 +644,13 @@ func HandleHover(event js.Value) {
 // HandleLeave clears hover preview when mouse leaves board
 func HandleLeave(event js.Value) {
 	state := Get()
-	if state.GetHoverCol() != -1 {
+	if state.GetHoverCol() != -1 && !state.IsMoveArmed() {
 		state.ClearHover()
 		Draw()
 	}
 }
 
-// getColumnFromEvent extracts the column number from a mouse event
+// getColumnFromEvent extracts the board column from a mouse event, undoing any mirroring
 func getColumnFromEvent(event js.Value) int {
 	rect := canvas.Call("getBoundingClientRect")
 	clientX := event.Get("clientX").Float()
@@ -391,6 +658,17 @@ func getColumnFromEvent(event js.Value) int {
 	rectWidth := rect.Get("width").Float()
 
 	// Normalize x to canvas coordinates (0-560)
-	x := (clientX - rectLeft) / rectWidth * 560.0
-	return int(x / CellSize)
+	x := (clientX - rectLeft) / rectWidth * float64(Cols*CellSize)
+	return ColumnFromX(x, isMirrored())
+}
+
+// getRowFromEvent calculates row from mouse event
+func getRowFromEvent(event js.Value) int {
+	rect := canvas.Call("getBoundingClientRect")
+	clientY := event.Get("clientY").Float()
+	rectTop := rect.Get("top").Float()
+	rectHeight := rect.Get("height").Float()
+
+	y := (clientY - rectTop) / rectHeight * float64(Rows*CellSize)
+	return RowFromY(y)
 }
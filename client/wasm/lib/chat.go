@@ -0,0 +1,39 @@
+// Copyright (c) 2025 Haute école d'ingénierie et d'architecture de Fribourg
+// SPDX-License-Identifier: Apache-2.0
+// Author: Astrit Aslani astrit.aslani@gmail.com
+// Created: 16.10.2026
+//go:build js && wasm
+
+package lib
+
+import "syscall/js"
+
+const maxLobbyChatEntries = 50
+
+// AppendLobbyChat adds a message to the lobby chat and drops the oldest ones
+func AppendLobbyChat(tag, username, text string) {
+	list := GetElement("lobby-chat-messages")
+	if list.IsNull() {
+		return
+	}
+
+	document := js.Global().Get("document")
+	entry := document.Call("createElement", "li")
+
+	author := document.Call("createElement", "span")
+	author.Set("className", "lobby-chat-author")
+	author.Set("textContent", username+" "+tag+": ")
+
+	// textContent keeps user input from being interpreted as HTML
+	body := document.Call("createElement", "span")
+	body.Set("textContent", text)
+
+	entry.Call("appendChild", author)
+	entry.Call("appendChild", body)
+	list.Call("appendChild", entry)
+
+	for list.Get("childElementCount").Int() > maxLobbyChatEntries {
+		list.Call("removeChild", list.Get("firstElementChild"))
+	}
+	list.Set("scrollTop", list.Get("scrollHeight"))
+}
@@ -11,21 +11,48 @@ import (
 	"syscall/js"
 )
 
+// ProtocolVersion is the protocol version spoken by this client
+const ProtocolVersion = 2
+
+// DevBuild is the build hash of bundles built without one, never reported as stale
+const DevBuild = "dev"
+
+// BuildHash identifies this client bundle, main sets it from the hash injected at build time
+var BuildHash = DevBuild
+
 // Message represents a WebSocket message
 type Message struct {
-	Type string      `json:"type"`
-	Data interface{} `json:"data"`
+	Type    string      `json:"type"`
+	Version int         `json:"version,omitempty"`
+	Data    interface{} `json:"data"`
 }
 
 // WelcomeData contains welcome message data
 type WelcomeData struct {
-	PlayerID string `json:"player_id"`
-	Username string `json:"username"`
+	PlayerID     string              `json:"player_id"`
+	Username     string              `json:"username"`
+	ResumeToken  string              `json:"resume_token"`
+	Prefs        map[string]string   `json:"prefs"`
+	Wins         int                 `json:"wins"`
+	Unlocks      []string            `json:"unlocks"`
+	BuildHash    string              `json:"build_hash,omitempty"`
+	Capabilities *ServerCapabilities `json:"capabilities,omitempty"`
+}
+
+// ServerCapabilities lists the optional features enabled on the server
+type ServerCapabilities struct {
+	FriendGames bool `json:"friend_games"`
+	Matchmaking bool `json:"matchmaking"`
+	Chat        bool `json:"chat"`
+	Series      bool `json:"series"`
+	Bot         bool `json:"bot"`
+	Puzzles     bool `json:"puzzles"`
 }
 
 // GameCreatedData contains game created data
 type GameCreatedData struct {
-	Code string `json:"code"`
+	Code   string `json:"code"`
+	Invite string `json:"invite,omitempty"`
 }
 
 // GameStartData contains game start data
@@ -34,19 +61,50 @@ type GameStartData struct {
 	CurrentTurn   int       `json:"current_turn"`
 	Players       [2]Player `json:"players"`
 	TimeRemaining [2]int64  `json:"time_remaining"`
+	AllowReplay   bool      `json:"allow_replay"`
+	Ranked        bool      `json:"ranked,omitempty"`
+	BestOf        int       `json:"best_of,omitempty"`
+	Score         [2]int    `json:"score"`
 }
 
 // GameStateData contains full game state
 type GameStateData struct {
-	Code           string    `json:"code"`
-	Status         int       `json:"status"`
-	Result         int       `json:"result"`
-	CurrentTurn    int       `json:"current_turn"`
-	Board          [6][7]int `json:"board"`
-	Players        [2]Player `json:"players"`
-	TimeRemaining  [2]int64  `json:"time_remaining"`
-	ReplayRequests [2]bool   `json:"replay_requests"`
-	LastMove       *LastMove `json:"last_move,omitempty"`
+	Code           string     `json:"code"`
+	Status         int        `json:"status"`
+	Result         int        `json:"result"`
+	DrawReason     string     `json:"draw_reason,omitempty"`
+	CurrentTurn    int        `json:"current_turn"`
+	MoveCount      int        `json:"move_count"`
+	Board          [6][7]int  `json:"board"`
+	Players        [2]Player  `json:"players"`
+	TimeRemaining  [2]int64   `json:"time_remaining"`
+	ReplayRequests [2]bool    `json:"replay_requests"`
+	AllowReplay    bool       `json:"allow_replay"`
+	Ranked         bool       `json:"ranked,omitempty"`
+	Paused         bool       `json:"paused"`
+	GraceRemaining int64      `json:"grace_remaining_ms,omitempty"`
+	LastMove       *LastMove  `json:"last_move,omitempty"`
+	Moves          []LastMove `json:"moves,omitempty"`
+	BestOf         int        `json:"best_of,omitempty"`
+	Score          [2]int     `json:"score"`
+	SeriesOver     bool       `json:"series_over,omitempty"`
+	SeriesResult   int        `json:"series_result,omitempty"`
+}
+
+// HistoryEntry is a finished game of the player, Seat is the side they played
+type HistoryEntry struct {
+	Code      string    `json:"code"`
+	Players   [2]string `json:"players"`
+	Result    int       `json:"result"`
+	MoveCount int       `json:"move_count"`
+	Board     [6][7]int `json:"board"`
+	VsBot     bool      `json:"vs_bot,omitempty"`
+	Seat      int       `json:"seat"`
+}
+
+// HistoryResponseData lists the player's last finished games, newest first
+type HistoryResponseData struct {
+	Games []HistoryEntry `json:"games"`
 }
 
 // MoveData contains move information
@@ -61,18 +119,119 @@ type MoveData struct {
 
 // GameOverData contains game over information
 type GameOverData struct {
-	Result int       `json:"result"`
-	Board  [6][7]int `json:"board"`
+	Result       int       `json:"result"`
+	DrawReason   string    `json:"draw_reason,omitempty"`
+	Board        [6][7]int `json:"board"`
+	WinningCells [][2]int  `json:"winning_cells,omitempty"` // Row and column of each cell of the winning line
+	Score        [2]int    `json:"score"`
+	SeriesOver   bool      `json:"series_over"`
+	SeriesResult int       `json:"series_result,omitempty"` // 3 for a shared victory
 }
 
 // ReplayRequestData contains replay request information
 type ReplayRequestData struct {
-	PlayerIdx int `json:"player_idx"`
+	PlayerIdx int  `json:"player_idx"`
+	KeepSeats bool `json:"keep_seats,omitempty"`
+}
+
+// VersionMismatchData contains the protocol versions of both sides
+type VersionMismatchData struct {
+	ClientVersion int `json:"client_version"`
+	ServerVersion int `json:"server_version"`
+}
+
+// CooldownData contains the remaining matchmaking penalty
+type CooldownData struct {
+	SecondsRemaining int `json:"seconds_remaining"`
+}
+
+// ReadyCheckData asks the player to confirm a found match
+type ReadyCheckData struct {
+	TimeoutSeconds int `json:"timeout_seconds"`
+}
+
+// ReadyCheckFailedData tells whether the player is back in the queue
+type ReadyCheckFailedData struct {
+	Requeued bool `json:"requeued"`
+}
+
+// LobbyChatMessageData contains a lobby chat message and its author
+type LobbyChatMessageData struct {
+	Tag      string `json:"tag"`
+	Username string `json:"username"`
+	Text     string `json:"text"`
+}
+
+// ThinkingData tells whether a bot side is preparing its reply
+type ThinkingData struct {
+	PlayerIdx int  `json:"player_idx"`
+	Thinking  bool `json:"thinking"`
+}
+
+// TimeSyncData carries the server clocks of the game in progress
+type TimeSyncData struct {
+	TimeRemaining [2]int64 `json:"time_remaining"` // milliseconds
+	CurrentTurn   int      `json:"current_turn"`
+}
+
+// AnnouncementData is an operator notice shown on every screen
+type AnnouncementData struct {
+	Text  string `json:"text"`
+	Level string `json:"level"`
+}
+
+// PresenceData tells that a side dropped or came back during a game
+type PresenceData struct {
+	PlayerIdx int  `json:"player_idx"`
+	Paused    bool `json:"paused"`
+}
+
+// StatsData contains the session wins, win streaks and unlocked cosmetics
+type StatsData struct {
+	Wins       int      `json:"wins"`
+	Unlocks    []string `json:"unlocks"`
+	NewUnlocks []string `json:"new_unlocks,omitempty"`
+	Streak     int      `json:"streak"`
+	BestStreak int      `json:"best_streak"`
+	Rating     int      `json:"rating"`
+	RatingGain int      `json:"rating_gain,omitempty"`
+}
+
+// PuzzleData is the next position of a puzzle rush
+type PuzzleData struct {
+	Number    int       `json:"number"`
+	Board     [6][7]int `json:"board"`
+	PlayerIdx int       `json:"player_idx"`
+	Depth     int       `json:"depth"`
+	TimeLimit int64     `json:"time_limit"`
+	Score     int       `json:"score"`
+	HighScore int       `json:"high_score"`
+}
+
+// PuzzleResultData tells how a puzzle answer was judged
+type PuzzleResultData struct {
+	Correct      bool  `json:"correct"`
+	TimedOut     bool  `json:"timed_out,omitempty"`
+	Column       int   `json:"column"`
+	Solutions    []int `json:"solutions"`
+	Points       int   `json:"points"`
+	Score        int   `json:"score"`
+	HighScore    int   `json:"high_score"`
+	NewHighScore bool  `json:"new_high_score,omitempty"`
+	Over         bool  `json:"over"`
+}
+
+// FeaturedGameData names the game in progress picked for the lobby to watch
+type FeaturedGameData struct {
+	Code      string    `json:"code"`
+	Players   [2]Player `json:"players"`
+	MoveCount int       `json:"move_count"`
 }
 
 // ErrorData contains error information
 type ErrorData struct {
 	Message string `json:"message"`
+	Code    string `json:"code,omitempty"`
 }
 
 var (
@@ -81,7 +240,7 @@ var (
 )
 
 // Connect establishes WebSocket connection
-func Connect(username, playerID string, onMessage func(Message)) {
+func Connect(username, resumeToken string, onMessage func(Message)) {
 	messageHandler = onMessage
 
 	protocol := "ws:"
@@ -99,10 +258,11 @@ func Connect(username, playerID string, onMessage func(Message)) {
 
 		// Send login message
 		loginData := map[string]interface{}{
-			"username": username,
+			"username":   username,
+			"build_hash": BuildHash,
 		}
-		if playerID != "" {
-			loginData["player_id"] = playerID
+		if resumeToken != "" {
+			loginData["resume_token"] = resumeToken
 		}
 
 		SendMessage("login", loginData)
@@ -156,8 +316,9 @@ func SendMessage(msgType string, data interface{}) {
 	}
 
 	msg := Message{
-		Type: msgType,
-		Data: data,
+		Type:    msgType,
+		Version: ProtocolVersion,
+		Data:    data,
 	}
 
 	bytes, err := json.Marshal(msg)
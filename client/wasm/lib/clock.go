@@ -0,0 +1,32 @@
+// Copyright (c) 2025 Haute école d'ingénierie et d'architecture de Fribourg
+// SPDX-License-Identifier: Apache-2.0
+// Author: Astrit Aslani astrit.aslani@gmail.com
+// Created: 16.10.2026
+
+package lib
+
+import "fmt"
+
+// UntimedClock is the remaining time of a side that plays without a clock
+// 2^53 is the largest integer a JavaScript number holds exactly, so it survives the JSON round trip
+const UntimedClock int64 = 1 << 53
+
+// formatTime converts milliseconds to M:SS, or H:MM:SS from one hour on
+func formatTime(ms int64) string {
+	if ms >= UntimedClock {
+		return "∞"
+	}
+	if ms < 0 {
+		ms = 0
+	}
+
+	totalSeconds := ms / 1000
+	hours := totalSeconds / 3600
+	minutes := totalSeconds / 60 % 60
+	seconds := totalSeconds % 60
+
+	if hours > 0 {
+		return fmt.Sprintf("%d:%02d:%02d", hours, minutes, seconds)
+	}
+	return fmt.Sprintf("%d:%02d", minutes, seconds)
+}
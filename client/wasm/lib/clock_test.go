@@ -0,0 +1,34 @@
+// Copyright (c) 2025 Haute école d'ingénierie et d'architecture de Fribourg
+// SPDX-License-Identifier: Apache-2.0
+// Author: Astrit Aslani astrit.aslani@gmail.com
+// Created: 16.10.2026
+
+package lib
+
+import "testing"
+
+// TestFormatTime tests clock rendering across magnitudes
+func TestFormatTime(t *testing.T) {
+	cases := []struct {
+		ms   int64
+		want string
+	}{
+		{0, "0:00"},
+		{-500, "0:00"},
+		{999, "0:00"},
+		{45_000, "0:45"},
+		{5*60_000 + 7_000, "5:07"},
+		{59*60_000 + 59_999, "59:59"},
+		{60 * 60_000, "1:00:00"},
+		{150 * 60_000, "2:30:00"},
+		{26*3_600_000 + 3*60_000 + 4_000, "26:03:04"},
+		{UntimedClock, "∞"},
+		{UntimedClock + 1, "∞"},
+	}
+
+	for _, tc := range cases {
+		if got := formatTime(tc.ms); got != tc.want {
+			t.Errorf("formatTime(%d): expected %q, got %q", tc.ms, tc.want, got)
+		}
+	}
+}
@@ -6,7 +6,9 @@
 
 package lib
 
-import "syscall/js"
+import (
+	"syscall/js"
+)
 
 // GetElement returns a DOM element by ID
 func GetElement(id string) js.Value {
@@ -46,6 +48,31 @@ func SetValue(id, value string) {
 	}
 }
 
+// GetChecked gets the checked state of a checkbox
+func GetChecked(id string) bool {
+	el := GetElement(id)
+	if el.IsNull() {
+		return false
+	}
+	return el.Get("checked").Bool()
+}
+
+// SetChecked sets the checked state of a checkbox
+func SetChecked(id string, checked bool) {
+	el := GetElement(id)
+	if !el.IsNull() {
+		el.Set("checked", checked)
+	}
+}
+
+// SetDisabled enables or disables a form control
+func SetDisabled(id string, disabled bool) {
+	el := GetElement(id)
+	if !el.IsNull() {
+		el.Set("disabled", disabled)
+	}
+}
+
 // AddClass adds a CSS class to an element
 func AddClass(id, className string) {
 	el := GetElement(id)
@@ -70,6 +97,24 @@ func ToggleClass(id, className string, force bool) {
 	}
 }
 
+// ToggleBodyClass toggles a CSS class on the document body
+func ToggleBodyClass(className string, force bool) {
+	body := js.Global().Get("document").Get("body")
+	if !body.IsNull() {
+		body.Get("classList").Call("toggle", className, force)
+	}
+}
+
+// GetQueryParam returns a parameter of the page URL, empty when absent
+func GetQueryParam(name string) string {
+	search := js.Global().Get("location").Get("search")
+	value := js.Global().Get("URLSearchParams").New(search).Call("get", name)
+	if value.IsNull() {
+		return ""
+	}
+	return value.String()
+}
+
 // SetDisplay sets display style property (deprecated, use utility classes instead)
 func SetDisplay(id, value string) {
 	SetStyle(id, "display", value)
@@ -94,15 +139,50 @@ func Hide(id string) {
 	AddClass(id, "d-none")
 }
 
-// ShowScreen shows a specific screen
+// ShowScreen shows a specific screen and records it in the browser history
 func ShowScreen(name string) {
-	screens := []string{"login-screen", "lobby-screen", "game-screen"}
+	switchScreen(name)
+	recordScreen(name)
+}
+
+// Elements receiving focus when a screen is shown, the first visible one wins
+var screenFocus = map[string][]string{
+	"login": {"username-input"},
+	"lobby": {"matchmaking-btn", "create-game-btn"},
+	"game":  {"replay-btn", "game-board"},
+}
+
+// switchScreen makes a screen visible without touching the browser history
+// Hidden screens are made inert so keyboard focus cannot land on them
+func switchScreen(name string) {
+	screens := []string{"loading", "login", "lobby", "game"}
+
+	target := GetElement(name + "-screen")
+	changed := !target.IsNull() && !target.Get("classList").Call("contains", "active").Bool()
 
 	for _, screen := range screens {
-		RemoveClass(screen, "active")
+		RemoveClass(screen+"-screen", "active")
+		if el := GetElement(screen + "-screen"); !el.IsNull() {
+			el.Set("inert", screen != name)
+		}
 	}
 
 	AddClass(name+"-screen", "active")
+
+	if changed {
+		FocusFirst(screenFocus[name]...)
+	}
+}
+
+// FocusFirst moves keyboard focus to the first visible element among ids
+func FocusFirst(ids ...string) {
+	for _, id := range ids {
+		el := GetElement(id)
+		if !el.IsNull() && !el.Get("offsetParent").IsNull() {
+			el.Call("focus")
+			return
+		}
+	}
 }
 
 // ShowMessage displays a message
@@ -116,13 +196,50 @@ func ShowMessage(elementID, text, msgType string) {
 	el.Set("className", "message "+msgType)
 }
 
+const storageProbeKey = "gonnect4-storage-probe"
+
+var (
+	// Private browsing and sandboxed iframes may throw on localStorage access
+	// Values are then only kept in memory for the current session
+	useMemoryStorage bool
+	memoryStorage    = map[string]string{}
+)
+
+// storageProbe touches localStorage inside a JS try/catch and evaluates to whether it worked
+// Reading window.localStorage itself throws in sandboxed frames, syscall/js does not turn that into a Go panic
+const storageProbe = `(function () {
+	try {
+		localStorage.setItem("` + storageProbeKey + `", "1");
+		localStorage.removeItem("` + storageProbeKey + `");
+		return true;
+	} catch (e) {
+		return false;
+	}
+})()`
+
+// DetectLocalStorage checks once whether localStorage can be used and falls back to memory otherwise
+func DetectLocalStorage() {
+	if js.Global().Call("eval", storageProbe).Bool() {
+		return
+	}
+	useMemoryStorage = true
+	js.Global().Get("console").Call("warn", "localStorage unavailable, settings will not be saved")
+}
+
 // SetLocalStorage sets an item in localStorage
 func SetLocalStorage(key, value string) {
+	if useMemoryStorage {
+		memoryStorage[key] = value
+		return
+	}
 	js.Global().Get("localStorage").Call("setItem", key, value)
 }
 
 // GetLocalStorage gets an item from localStorage
 func GetLocalStorage(key string) string {
+	if useMemoryStorage {
+		return memoryStorage[key]
+	}
 	val := js.Global().Get("localStorage").Call("getItem", key)
 	if val.IsNull() {
 		return ""
@@ -132,6 +249,10 @@ func GetLocalStorage(key string) string {
 
 // RemoveLocalStorage removes an item from localStorage
 func RemoveLocalStorage(key string) {
+	if useMemoryStorage {
+		delete(memoryStorage, key)
+		return
+	}
 	js.Global().Get("localStorage").Call("removeItem", key)
 }
 
@@ -140,6 +261,12 @@ func Confirm(message string) bool {
 	return js.Global().Call("confirm", message).Bool()
 }
 
+// SignalReady tells the host page that the client finished its setup
+func SignalReady() {
+	js.Global().Set("wasmReady", true)
+	js.Global().Call("dispatchEvent", js.Global().Get("Event").New("wasmready"))
+}
+
 // Console logs to browser console
 func Console(message string) {
 	js.Global().Get("console").Call("log", message)
@@ -0,0 +1,52 @@
+// Copyright (c) 2025 Haute école d'ingénierie et d'architecture de Fribourg
+// SPDX-License-Identifier: Apache-2.0
+// Author: Astrit Aslani astrit.aslani@gmail.com
+// Created: 16.10.2026
+
+package lib
+
+import "math"
+
+// Drop animation easings
+const (
+	EasingGravity = "gravity"
+	EasingBounce  = "bounce"
+	EasingLinear  = "linear"
+)
+
+const (
+	bounceLanding   = 0.75 // Share of the animation spent falling before the first contact
+	bounceOvershoot = 0.04 // Share of the drop distance the disc sinks past its cell
+)
+
+// IsDropEasing checks if a name is a known drop easing
+func IsDropEasing(name string) bool {
+	return name == EasingGravity || name == EasingBounce || name == EasingLinear
+}
+
+// EaseDrop maps the elapsed share of a drop animation to the share of the distance covered
+// Every easing starts at 0 and ends exactly at 1, so the disc always lands on its cell
+func EaseDrop(easing string, progress float64) float64 {
+	if progress <= 0 {
+		return 0
+	}
+	if progress >= 1 {
+		return 1
+	}
+
+	switch easing {
+	case EasingLinear:
+		return progress
+	case EasingBounce:
+		if progress < bounceLanding {
+			fall := progress / bounceLanding
+			return fall * fall
+		}
+		// One damped swing past the cell, back to rest when the animation ends
+		settle := (progress - bounceLanding) / (1 - bounceLanding)
+		return 1 + bounceOvershoot*math.Sin(math.Pi*settle)*(1-settle)
+	default:
+		// Quadratic easing progress² gives gravity-like acceleration
+		return progress * progress
+	}
+}
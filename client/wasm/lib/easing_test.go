@@ -0,0 +1,52 @@
+// Copyright (c) 2025 Haute école d'ingénierie et d'architecture de Fribourg
+// SPDX-License-Identifier: Apache-2.0
+// Author: Astrit Aslani astrit.aslani@gmail.com
+// Created: 16.10.2026
+
+package lib
+
+import "testing"
+
+// TestEaseDrop_Endpoints tests that every easing starts above the board and ends on the cell
+func TestEaseDrop_Endpoints(t *testing.T) {
+	for _, easing := range []string{EasingGravity, EasingBounce, EasingLinear, "unknown"} {
+		if got := EaseDrop(easing, 0); got != 0 {
+			t.Errorf("%s: expected 0 at the start, got %v", easing, got)
+		}
+		if got := EaseDrop(easing, 1); got != 1 {
+			t.Errorf("%s: expected 1 at the end, got %v", easing, got)
+		}
+		if got := EaseDrop(easing, 1.3); got != 1 {
+			t.Errorf("%s: expected late frames to stay on the cell, got %v", easing, got)
+		}
+	}
+}
+
+// TestEaseDrop_Gravity tests the quadratic fall
+func TestEaseDrop_Gravity(t *testing.T) {
+	if got := EaseDrop(EasingGravity, 0.5); got != 0.25 {
+		t.Errorf("Expected 0.25 halfway, got %v", got)
+	}
+	if got := EaseDrop(EasingLinear, 0.5); got != 0.5 {
+		t.Errorf("Expected linear easing to be halfway, got %v", got)
+	}
+}
+
+// TestEaseDrop_Bounce tests that the bounce overshoots a little and settles back on the cell
+func TestEaseDrop_Bounce(t *testing.T) {
+	if got := EaseDrop(EasingBounce, bounceLanding); got != 1 {
+		t.Errorf("Expected the first contact at %v, got %v", bounceLanding, got)
+	}
+
+	peak := 0.0
+	for i := 0; i <= 100; i++ {
+		peak = max(peak, EaseDrop(EasingBounce, float64(i)/100))
+	}
+	if peak <= 1 || peak > 1+bounceOvershoot {
+		t.Errorf("Expected a small overshoot, peaked at %v", peak)
+	}
+
+	if got := EaseDrop(EasingBounce, 0.999); got < 1 || got > 1.001 {
+		t.Errorf("Expected the disc to have settled just before the end, got %v", got)
+	}
+}
@@ -0,0 +1,31 @@
+// Copyright (c) 2025 Haute école d'ingénierie et d'architecture de Fribourg
+// SPDX-License-Identifier: Apache-2.0
+// Author: Astrit Aslani astrit.aslani@gmail.com
+// Created: 16.10.2026
+//go:build js && wasm
+
+package lib
+
+// UpdateEditorStatus shows whether the edited position can be exported
+// Copying is only allowed once the position validates
+func UpdateEditorStatus() {
+	editor := Get().GetEditor()
+	if editor == nil {
+		return
+	}
+
+	if err := editor.Validate(); err != nil {
+		SetText("editor-status", "Invalid position: "+err.Error())
+		SetStyle("editor-status", "color", "var(--danger)")
+		SetDisabled("editor-copy-btn", true)
+		return
+	}
+
+	side := "Red"
+	if editor.ToMove == 1 {
+		side = "Yellow"
+	}
+	SetText("editor-status", side+" to move - "+editor.String())
+	SetStyle("editor-status", "color", "var(--success)")
+	SetDisabled("editor-copy-btn", false)
+}
@@ -0,0 +1,65 @@
+// Copyright (c) 2025 Haute école d'ingénierie et d'architecture de Fribourg
+// SPDX-License-Identifier: Apache-2.0
+// Author: Astrit Aslani astrit.aslani@gmail.com
+// Created: 16.10.2026
+//go:build js && wasm
+
+package lib
+
+import (
+	"encoding/json"
+	"net/url"
+	"syscall/js"
+)
+
+// watchedEvents are the event names sent on a game's event stream
+var watchedEvents = []string{"game_state", "move", "game_over"}
+
+var (
+	eventSource js.Value
+	eventFuncs  []js.Func
+)
+
+// Watch follows a game through its read-only event stream
+// Each event is handed to onMessage like a WebSocket message, onEnd runs once the stream is gone
+func Watch(code string, onMessage func(Message), onEnd func()) {
+	StopWatching()
+
+	eventSource = js.Global().Get("EventSource").New("/game/" + url.PathEscape(code) + "/events")
+	for _, name := range watchedEvents {
+		name := name
+		fn := js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+			var data interface{}
+			if err := json.Unmarshal([]byte(args[0].Get("data").String()), &data); err != nil {
+				Console("Error parsing event: " + err.Error())
+				return nil
+			}
+			onMessage(Message{Type: name, Data: data})
+			return nil
+		})
+		eventSource.Call("addEventListener", name, fn)
+		eventFuncs = append(eventFuncs, fn)
+	}
+
+	// The browser would reconnect forever to a game that is gone
+	fn := js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		Console("Event stream closed")
+		StopWatching()
+		onEnd()
+		return nil
+	})
+	eventSource.Call("addEventListener", "error", fn)
+	eventFuncs = append(eventFuncs, fn)
+}
+
+// StopWatching closes the event stream opened by Watch, if any
+func StopWatching() {
+	if !eventSource.IsUndefined() && !eventSource.IsNull() {
+		eventSource.Call("close")
+	}
+	eventSource = js.Undefined()
+	for _, fn := range eventFuncs {
+		fn.Release()
+	}
+	eventFuncs = nil
+}
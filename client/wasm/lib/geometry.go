@@ -0,0 +1,47 @@
+// Copyright (c) 2025 Haute école d'ingénierie et d'architecture de Fribourg
+// SPDX-License-Identifier: Apache-2.0
+// Author: Astrit Aslani astrit.aslani@gmail.com
+// Created: 16.10.2026
+
+package lib
+
+// Board geometry, kept free of browser APIs so it can be tested natively
+const (
+	Rows      = 6
+	Cols      = 7
+	WinLength = 4
+	CellSize  = 80
+)
+
+// winDirections lists the four line orientations as (row, col) steps
+var winDirections = [4][2]int{{0, 1}, {1, 0}, {1, 1}, {1, -1}}
+
+// DisplayColumn maps a board column to the column drawn on screen
+// Mirroring is its own inverse, so it also maps screen columns back to board columns
+func DisplayColumn(col int, mirrored bool) int {
+	if mirrored {
+		return Cols - 1 - col
+	}
+	return col
+}
+
+// ColumnFromX maps a horizontal canvas position to the authoritative board column
+func ColumnFromX(x float64, mirrored bool) int {
+	if x < 0 || x >= Cols*CellSize {
+		return -1
+	}
+	return DisplayColumn(int(x/CellSize), mirrored)
+}
+
+// RowFromY maps a vertical canvas position to the board row, rows are never mirrored
+func RowFromY(y float64) int {
+	if y < 0 || y >= Rows*CellSize {
+		return -1
+	}
+	return int(y / CellSize)
+}
+
+// ColumnCenterX returns the horizontal canvas center of a board column
+func ColumnCenterX(col int, mirrored bool) int {
+	return DisplayColumn(col, mirrored)*CellSize + CellSize/2
+}
@@ -0,0 +1,59 @@
+// Copyright (c) 2025 Haute école d'ingénierie et d'architecture de Fribourg
+// SPDX-License-Identifier: Apache-2.0
+// Author: Astrit Aslani astrit.aslani@gmail.com
+// Created: 16.10.2026
+
+package lib
+
+import "testing"
+
+// TestColumnFromX_Normal tests click mapping without mirroring
+func TestColumnFromX_Normal(t *testing.T) {
+	cases := map[float64]int{0: 0, 79: 0, 80: 1, 300: 3, 559: 6}
+	for x, want := range cases {
+		if got := ColumnFromX(x, false); got != want {
+			t.Errorf("x=%v: expected column %d, got %d", x, want, got)
+		}
+	}
+}
+
+// TestColumnFromX_Mirrored tests that clicks map back through the mirror
+func TestColumnFromX_Mirrored(t *testing.T) {
+	cases := map[float64]int{0: 6, 79: 6, 80: 5, 300: 3, 559: 0}
+	for x, want := range cases {
+		if got := ColumnFromX(x, true); got != want {
+			t.Errorf("x=%v: expected column %d, got %d", x, want, got)
+		}
+	}
+}
+
+// TestColumnFromX_OutOfBoard tests positions outside the canvas
+func TestColumnFromX_OutOfBoard(t *testing.T) {
+	for _, mirrored := range []bool{false, true} {
+		if ColumnFromX(-1, mirrored) != -1 || ColumnFromX(Cols*CellSize, mirrored) != -1 {
+			t.Errorf("Positions outside the board should not map to a column (mirrored=%v)", mirrored)
+		}
+	}
+}
+
+// TestColumnCenterX_RoundTrip tests that a drawn column maps back to itself when clicked
+func TestColumnCenterX_RoundTrip(t *testing.T) {
+	for _, mirrored := range []bool{false, true} {
+		for col := 0; col < Cols; col++ {
+			x := float64(ColumnCenterX(col, mirrored))
+			if got := ColumnFromX(x, mirrored); got != col {
+				t.Errorf("Column %d drawn at x=%v maps back to %d (mirrored=%v)", col, x, got, mirrored)
+			}
+		}
+	}
+}
+
+// TestRowFromY tests vertical click mapping, including positions outside the board
+func TestRowFromY(t *testing.T) {
+	cases := map[float64]int{-1: -1, 0: 0, 79: 0, 80: 1, 479: 5, Rows * CellSize: -1}
+	for y, want := range cases {
+		if got := RowFromY(y); got != want {
+			t.Errorf("y=%v: expected row %d, got %d", y, want, got)
+		}
+	}
+}
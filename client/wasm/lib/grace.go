@@ -0,0 +1,82 @@
+// Copyright (c) 2025 Haute école d'ingénierie et d'architecture de Fribourg
+// SPDX-License-Identifier: Apache-2.0
+// Author: Astrit Aslani astrit.aslani@gmail.com
+// Created: 16.10.2026
+//go:build js && wasm
+
+package lib
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+const graceUpdateInterval = time.Second
+
+var (
+	graceMutex     sync.Mutex
+	graceStopChan  chan bool
+	graceElementID string
+)
+
+// StartGraceCountdown shows how long an idle game is kept before the server closes it
+func StartGraceCountdown(elementID string, remainingMs int64) {
+	StopGraceCountdown()
+	if remainingMs <= 0 {
+		return
+	}
+
+	graceMutex.Lock()
+	defer graceMutex.Unlock()
+
+	deadline := time.Now().Add(time.Duration(remainingMs) * time.Millisecond)
+	stop := make(chan bool)
+	graceStopChan = stop
+	graceElementID = elementID
+
+	updateGraceText(elementID, deadline)
+	Show(elementID)
+
+	go func() {
+		ticker := time.NewTicker(graceUpdateInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				if !updateGraceText(elementID, deadline) {
+					Hide(elementID)
+					return
+				}
+			case <-stop:
+				return
+			}
+		}
+	}()
+}
+
+// StopGraceCountdown hides the countdown of the previous idle game
+func StopGraceCountdown() {
+	graceMutex.Lock()
+	defer graceMutex.Unlock()
+
+	if graceStopChan != nil {
+		close(graceStopChan)
+		graceStopChan = nil
+	}
+	if graceElementID != "" {
+		Hide(graceElementID)
+		graceElementID = ""
+	}
+}
+
+// updateGraceText refreshes the countdown text and reports whether time is left
+func updateGraceText(elementID string, deadline time.Time) bool {
+	seconds := int(time.Until(deadline).Seconds())
+	if seconds <= 0 {
+		return false
+	}
+	SetText(elementID, fmt.Sprintf("This game will close in %ds.", seconds))
+	return true
+}
@@ -0,0 +1,58 @@
+// Copyright (c) 2025 Haute école d'ingénierie et d'architecture de Fribourg
+// SPDX-License-Identifier: Apache-2.0
+// Author: Astrit Aslani astrit.aslani@gmail.com
+// Created: 16.10.2026
+//go:build js && wasm
+
+package lib
+
+import "syscall/js"
+
+// Screen currently recorded in the browser history, empty before the first screen is shown
+var currentScreen string
+
+// recordScreen stores a screen change in the browser history
+// Only entering a game adds an entry so back leaves it, other screens replace the current entry
+// The URL is left untouched so query parameters survive
+func recordScreen(name string) {
+	if name == currentScreen {
+		return
+	}
+
+	method := "replaceState"
+	if name == "game" && currentScreen != "" {
+		method = "pushState"
+	}
+	js.Global().Get("history").Call(method, map[string]interface{}{"screen": name}, "")
+	currentScreen = name
+}
+
+// SetupHistory handles browser back and forward
+// allow decides whether leaving from for to is fine, refused moves keep the current screen
+func SetupHistory(allow func(from, to string) bool) {
+	js.Global().Call("addEventListener", "popstate", js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		target := "login"
+		if entry := args[0].Get("state"); entry.Type() == js.TypeObject {
+			if screen := entry.Get("screen"); screen.Type() == js.TypeString {
+				target = screen.String()
+			}
+		}
+
+		from := currentScreen
+		if target == from {
+			return nil
+		}
+
+		if !allow(from, target) {
+			js.Global().Get("history").Call("pushState", map[string]interface{}{"screen": from}, "")
+			return nil
+		}
+
+		// allow may already have switched screens itself
+		if currentScreen == from {
+			switchScreen(target)
+			currentScreen = target
+		}
+		return nil
+	}))
+}
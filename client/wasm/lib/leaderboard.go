@@ -0,0 +1,109 @@
+// Copyright (c) 2025 Haute école d'ingénierie et d'architecture de Fribourg
+// SPDX-License-Identifier: Apache-2.0
+// Author: Astrit Aslani astrit.aslani@gmail.com
+// Created: 16.10.2026
+//go:build js && wasm
+
+package lib
+
+import (
+	"encoding/json"
+	"fmt"
+	"syscall/js"
+)
+
+// leaderboardURL lists the top players online, the server caps the page at 50
+const leaderboardURL = "/api/leaderboard?limit=10"
+
+// LeaderboardEntry is one ranked player served on /api/leaderboard
+type LeaderboardEntry struct {
+	Rank     int    `json:"rank"`
+	Username string `json:"username"`
+	Tag      string `json:"tag"`
+	Wins     int    `json:"wins"`
+}
+
+// LeaderboardData is the page served on /api/leaderboard
+type LeaderboardData struct {
+	Players []LeaderboardEntry `json:"players"`
+	Offset  int                `json:"offset"`
+	Total   int                `json:"total"`
+}
+
+// LoadLeaderboard fetches the leaderboard and shows it in the lobby panel
+func LoadLeaderboard() {
+	var onText, onResponse, onError js.Func
+	release := func() {
+		onText.Release()
+		onResponse.Release()
+		onError.Release()
+	}
+
+	onText = js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		defer release()
+
+		var board LeaderboardData
+		if err := json.Unmarshal([]byte(args[0].String()), &board); err != nil {
+			Console("Error parsing leaderboard: " + err.Error())
+			SetText("leaderboard-status", "Leaderboard unavailable")
+			return nil
+		}
+		showLeaderboard(board)
+		return nil
+	})
+
+	onResponse = js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		response := args[0]
+		if !response.Get("ok").Bool() {
+			defer release()
+			SetText("leaderboard-status", "Leaderboard unavailable")
+			return nil
+		}
+		response.Call("text").Call("then", onText)
+		return nil
+	})
+
+	onError = js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		defer release()
+		SetText("leaderboard-status", "Leaderboard unavailable")
+		return nil
+	})
+
+	SetText("leaderboard-status", "Loading...")
+	js.Global().Call("fetch", leaderboardURL).Call("then", onResponse).Call("catch", onError)
+}
+
+// showLeaderboard replaces the rows of the lobby panel
+func showLeaderboard(board LeaderboardData) {
+	list := GetElement("leaderboard-list")
+	if list.IsNull() {
+		return
+	}
+	list.Set("textContent", "")
+
+	document := js.Global().Get("document")
+	for _, entry := range board.Players {
+		row := document.Call("createElement", "li")
+
+		// textContent keeps usernames from being interpreted as HTML
+		name := document.Call("createElement", "span")
+		name.Set("textContent", fmt.Sprintf("%d. %s %s", entry.Rank, entry.Username, entry.Tag))
+
+		wins := document.Call("createElement", "span")
+		wins.Set("className", "leaderboard-wins")
+		wins.Set("textContent", fmt.Sprintf("%d", entry.Wins))
+
+		row.Call("appendChild", name)
+		row.Call("appendChild", wins)
+		list.Call("appendChild", row)
+	}
+
+	switch board.Total {
+	case 0:
+		SetText("leaderboard-status", "Nobody is online")
+	case 1:
+		SetText("leaderboard-status", "1 player online")
+	default:
+		SetText("leaderboard-status", fmt.Sprintf("%d players online", board.Total))
+	}
+}
@@ -0,0 +1,48 @@
+// Copyright (c) 2025 Haute école d'ingénierie et d'architecture de Fribourg
+// SPDX-License-Identifier: Apache-2.0
+// Author: Astrit Aslani astrit.aslani@gmail.com
+// Created: 16.10.2026
+//go:build js && wasm
+
+package lib
+
+import "syscall/js"
+
+// MiniCellSize is the cell size of board previews in pixels
+const MiniCellSize = 10
+
+// DrawMiniBoard renders a small non-interactive preview of a board into a canvas
+// Holes and tokens are flat circles drawn in one pass, no overlay or effects, so long lists stay cheap
+func DrawMiniBoard(target js.Value, board [Rows][Cols]int) {
+	if target.IsNull() || target.IsUndefined() {
+		return
+	}
+
+	width := Cols * MiniCellSize
+	height := Rows * MiniCellSize
+	if target.Get("width").Int() != width || target.Get("height").Int() != height {
+		target.Set("width", width)
+		target.Set("height", height)
+	}
+
+	ctx := target.Call("getContext", "2d")
+	ctx.Set("fillStyle", boardColor())
+	ctx.Call("fillRect", 0, 0, width, height)
+
+	radius := float64(MiniCellSize)/2 - 1
+	for row := 0; row < Rows; row++ {
+		for col := 0; col < Cols; col++ {
+			switch board[row][col] {
+			case 1:
+				ctx.Set("fillStyle", tokenColor(0))
+			case 2:
+				ctx.Set("fillStyle", tokenColor(1))
+			default:
+				ctx.Set("fillStyle", ColorEmpty)
+			}
+			ctx.Call("beginPath")
+			ctx.Call("arc", col*MiniCellSize+MiniCellSize/2, row*MiniCellSize+MiniCellSize/2, radius, 0, 2*3.14159)
+			ctx.Call("fill")
+		}
+	}
+}
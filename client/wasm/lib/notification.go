@@ -0,0 +1,112 @@
+// Copyright (c) 2025 Haute école d'ingénierie et d'architecture de Fribourg
+// SPDX-License-Identifier: Apache-2.0
+// Author: Astrit Aslani astrit.aslani@gmail.com
+// Created: 16.10.2026
+//go:build js && wasm
+
+package lib
+
+import "syscall/js"
+
+const (
+	turnTitle     = "(Your turn) GOnnect4"
+	turnBeepFreq  = 660 // Hz
+	turnBeepSecs  = 0.15
+	turnBeepLevel = 0.1
+)
+
+var (
+	originalTitle    string
+	turnNotification js.Value
+)
+
+// SetupTurnAlerts clears pending turn alerts when the tab becomes visible again
+func SetupTurnAlerts() {
+	originalTitle = js.Global().Get("document").Get("title").String()
+
+	clearAlert := js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		if !js.Global().Get("document").Get("hidden").Bool() {
+			ClearTurnAlert()
+		}
+		return nil
+	})
+	js.Global().Get("document").Call("addEventListener", "visibilitychange", clearAlert)
+	js.Global().Call("addEventListener", "focus", clearAlert)
+}
+
+// RequestNotificationPermission asks for permission to show browser notifications
+func RequestNotificationPermission() {
+	notification := js.Global().Get("Notification")
+	if notification.IsUndefined() {
+		return
+	}
+	if notification.Get("permission").String() == "default" {
+		notification.Call("requestPermission")
+	}
+}
+
+// NotifyTurn alerts the player that it is their turn while the tab is in the background
+func NotifyTurn() {
+	if !GetSettings().GetTurnAlerts() || !js.Global().Get("document").Get("hidden").Bool() {
+		return
+	}
+
+	js.Global().Get("document").Set("title", turnTitle)
+	playTurnBeep()
+
+	// Notifications are optional, the title and sound still work if denied
+	notification := js.Global().Get("Notification")
+	if notification.IsUndefined() || notification.Get("permission").String() != "granted" {
+		return
+	}
+
+	closeTurnNotification()
+	turnNotification = notification.New("GOnnect4", map[string]interface{}{
+		"body": "It's your turn to play",
+		"tag":  "gonnect4-turn",
+	})
+}
+
+// ClearTurnAlert restores the tab title and closes the turn notification
+func ClearTurnAlert() {
+	if originalTitle != "" {
+		js.Global().Get("document").Set("title", originalTitle)
+	}
+	closeTurnNotification()
+}
+
+// closeTurnNotification closes the notification currently shown, if any
+func closeTurnNotification() {
+	if !turnNotification.IsUndefined() && !turnNotification.IsNull() {
+		turnNotification.Call("close")
+		turnNotification = js.Undefined()
+	}
+}
+
+// playTurnBeep plays a short tone using the Web Audio API
+func playTurnBeep() {
+	audioContextClass := js.Global().Get("AudioContext")
+	if audioContextClass.IsUndefined() {
+		return
+	}
+
+	audio := audioContextClass.New()
+	oscillator := audio.Call("createOscillator")
+	gain := audio.Call("createGain")
+
+	oscillator.Get("frequency").Set("value", turnBeepFreq)
+	gain.Get("gain").Set("value", turnBeepLevel)
+	oscillator.Call("connect", gain)
+	gain.Call("connect", audio.Get("destination"))
+
+	now := audio.Get("currentTime").Float()
+	oscillator.Call("start", now)
+	oscillator.Call("stop", now+turnBeepSecs)
+	var onEnded js.Func
+	onEnded = js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		onEnded.Release()
+		audio.Call("close")
+		return nil
+	})
+	oscillator.Set("onended", onEnded)
+}
@@ -0,0 +1,155 @@
+// Copyright (c) 2025 Haute école d'ingénierie et d'architecture de Fribourg
+// SPDX-License-Identifier: Apache-2.0
+// Author: Astrit Aslani astrit.aslani@gmail.com
+// Created: 16.10.2026
+
+package lib
+
+import (
+	"errors"
+	"strings"
+)
+
+// Position errors reported by the board editor
+var (
+	ErrFloatingToken   = errors.New("a token is floating above an empty cell")
+	ErrTokenCount      = errors.New("red and yellow token counts do not match the side to move")
+	ErrPositionWon     = errors.New("the position already contains four in a row")
+	ErrBoardFull       = errors.New("the board is full, there is no move left to play")
+	ErrInvalidPosition = errors.New("invalid position string")
+)
+
+// positionCells are the position string letters of an empty cell, a red token and a yellow token
+const positionCells = ".ry"
+
+// Position is a board with the side to move, as built in the board editor
+// Cells hold 0 when empty, 1 for a red token and 2 for a yellow token
+type Position struct {
+	Board  [Rows][Cols]int
+	ToMove int // Seat to move, 0 for red
+}
+
+// CycleCell turns an empty cell red, a red one yellow and a yellow one empty again
+func (p *Position) CycleCell(row, col int) {
+	if row < 0 || row >= Rows || col < 0 || col >= Cols {
+		return
+	}
+	p.Board[row][col] = (p.Board[row][col] + 1) % len(positionCells)
+}
+
+// Validate checks that the position could come from a real game and still has a move to play
+func (p *Position) Validate() error {
+	var counts [2]int
+	for col := 0; col < Cols; col++ {
+		for row := Rows - 1; row >= 0; row-- {
+			if owner := p.Board[row][col]; owner != 0 {
+				counts[owner-1]++
+				if row < Rows-1 && p.Board[row+1][col] == 0 {
+					return ErrFloatingToken
+				}
+			}
+		}
+	}
+
+	// Either side may have opened the game, so the side to move is the one not ahead
+	switch counts[0] - counts[1] {
+	case 0:
+	case 1:
+		if p.ToMove != 1 {
+			return ErrTokenCount
+		}
+	case -1:
+		if p.ToMove != 0 {
+			return ErrTokenCount
+		}
+	default:
+		return ErrTokenCount
+	}
+
+	if p.hasFourInRow() {
+		return ErrPositionWon
+	}
+	if counts[0]+counts[1] == Rows*Cols {
+		return ErrBoardFull
+	}
+	return nil
+}
+
+// hasFourInRow reports whether either side already connected WinLength tokens
+func (p *Position) hasFourInRow() bool {
+	for row := 0; row < Rows; row++ {
+		for col := 0; col < Cols; col++ {
+			owner := p.Board[row][col]
+			if owner == 0 {
+				continue
+			}
+			for _, dir := range winDirections {
+				length := 1
+				for length < WinLength {
+					r, c := row+dir[0]*length, col+dir[1]*length
+					if r < 0 || r >= Rows || c < 0 || c >= Cols || p.Board[r][c] != owner {
+						break
+					}
+					length++
+				}
+				if length == WinLength {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// String encodes the position as rows from top to bottom separated by "/", then the side to move
+// An empty board with red to move reads "......./......./......./......./......./....... r"
+func (p *Position) String() string {
+	var sb strings.Builder
+	for row := 0; row < Rows; row++ {
+		if row > 0 {
+			sb.WriteByte('/')
+		}
+		for col := 0; col < Cols; col++ {
+			sb.WriteByte(positionCells[p.Board[row][col]])
+		}
+	}
+	sb.WriteByte(' ')
+	sb.WriteByte(positionCells[p.ToMove+1])
+	return sb.String()
+}
+
+// ParsePosition decodes a position string written by String
+func ParsePosition(s string) (Position, error) {
+	var p Position
+
+	rows, toMove, ok := strings.Cut(strings.TrimSpace(s), " ")
+	if !ok || len(toMove) != 1 {
+		return p, ErrInvalidPosition
+	}
+	switch toMove {
+	case "r":
+		p.ToMove = 0
+	case "y":
+		p.ToMove = 1
+	default:
+		return p, ErrInvalidPosition
+	}
+
+	lines := strings.Split(rows, "/")
+	if len(lines) != Rows {
+		return p, ErrInvalidPosition
+	}
+	for row, line := range lines {
+		if len(line) != Cols {
+			return p, ErrInvalidPosition
+		}
+		for col := 0; col < Cols; col++ {
+			cell := strings.IndexByte(positionCells, line[col])
+			if cell < 0 {
+				return p, ErrInvalidPosition
+			}
+			p.Board[row][col] = cell
+		}
+	}
+	return p, nil
+}
@@ -0,0 +1,72 @@
+// Copyright (c) 2025 Haute école d'ingénierie et d'architecture de Fribourg
+// SPDX-License-Identifier: Apache-2.0
+// Author: Astrit Aslani astrit.aslani@gmail.com
+// Created: 16.10.2026
+
+package lib
+
+import "testing"
+
+// TestPosition_CycleCell tests that clicks cycle a cell through empty, red and yellow
+func TestPosition_CycleCell(t *testing.T) {
+	var p Position
+	for _, want := range []int{1, 2, 0} {
+		p.CycleCell(Rows-1, 3)
+		if got := p.Board[Rows-1][3]; got != want {
+			t.Fatalf("Expected cell %d, got %d", want, got)
+		}
+	}
+}
+
+// TestPosition_Validate tests the positions the editor refuses to export
+func TestPosition_Validate(t *testing.T) {
+	cases := []struct {
+		name     string
+		position string
+		want     error
+	}{
+		{"empty", "......./......./......./......./......./....... r", nil},
+		{"red ahead", "......./......./......./......./......./...r... y", nil},
+		{"stacked", "......./......./......./......./...r.../...y... r", nil},
+		{"gap below", "......./......./......./......./...r.../....... y", ErrFloatingToken},
+		{"wrong side", "......./......./......./......./......./...r... r", ErrTokenCount},
+		{"too many", "......./......./......./......./......./..rr... y", ErrTokenCount},
+		{"won", "......./......./......./......./yyy..../rrrr... y", ErrPositionWon},
+		{"full", "rryyrry/yyrryyr/rryyrry/yyrryyr/rryyrry/yyrryyr r", ErrBoardFull},
+	}
+
+	for _, tc := range cases {
+		p, err := ParsePosition(tc.position)
+		if err != nil {
+			t.Fatalf("%s: parse failed: %v", tc.name, err)
+		}
+		if got := p.Validate(); got != tc.want {
+			t.Errorf("%s: expected %v, got %v", tc.name, tc.want, got)
+		}
+	}
+}
+
+// TestPosition_RoundTrip tests that an exported position parses back to itself
+func TestPosition_RoundTrip(t *testing.T) {
+	p := Position{ToMove: 1}
+	p.Board[Rows-1][0] = 1
+	p.Board[Rows-1][1] = 2
+	p.Board[Rows-2][0] = 1
+
+	parsed, err := ParsePosition(p.String())
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if parsed != p {
+		t.Errorf("Expected %v, got %v", p.String(), parsed.String())
+	}
+}
+
+// TestParsePosition_Invalid tests malformed position strings
+func TestParsePosition_Invalid(t *testing.T) {
+	for _, s := range []string{"", "......./....... r", "......./......./......./......./......./....... x", "......./......./......./......./......./...z... r"} {
+		if _, err := ParsePosition(s); err != ErrInvalidPosition {
+			t.Errorf("%q: expected ErrInvalidPosition, got %v", s, err)
+		}
+	}
+}
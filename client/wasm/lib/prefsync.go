@@ -0,0 +1,50 @@
+// Copyright (c) 2025 Haute école d'ingénierie et d'architecture de Fribourg
+// SPDX-License-Identifier: Apache-2.0
+// Author: Astrit Aslani astrit.aslani@gmail.com
+// Created: 16.10.2026
+
+package lib
+
+// Preferences saved to the server when sync is enabled, the server accepts the same keys
+var syncedPrefKeys = []string{
+	"renderStyle", "discSkin", "highlightColor", "highlightPulse", "winningPreview",
+	"gravityTrail", "mirrorBoard", "confirmMoves", "turnAlerts", "autoRematch",
+	"highContrast", "moveNumbers", "meFirst", "blockHints", "dropEasing",
+	"streamPalette",
+}
+
+// prefFlags are the synced preferences stored as "on" or not at all, the others hold a value
+var prefFlags = map[string]bool{
+	"highlightPulse": true, "winningPreview": true, "gravityTrail": true, "mirrorBoard": true,
+	"confirmMoves": true, "turnAlerts": true, "autoRematch": true, "highContrast": true,
+	"moveNumbers": true, "meFirst": true, "blockHints": true, "streamPalette": true,
+}
+
+// snapshotPrefs returns every synced preference read through get with an explicit value
+// Flags are sent as "on" or "off" and other keys as empty for their default, so turning one off reaches other devices
+func snapshotPrefs(get func(key string) string) map[string]string {
+	prefs := make(map[string]string, len(syncedPrefKeys))
+	for _, key := range syncedPrefKeys {
+		value := get(key)
+		if prefFlags[key] && value != "on" {
+			value = "off"
+		}
+		prefs[key] = value
+	}
+	return prefs
+}
+
+// applyPrefs writes the synced preferences of a snapshot through set, an empty value clears the key
+// Keys missing from the snapshot, as in one saved by an older client, are left alone
+func applyPrefs(prefs map[string]string, set func(key, value string)) {
+	for _, key := range syncedPrefKeys {
+		value, ok := prefs[key]
+		if !ok {
+			continue
+		}
+		if prefFlags[key] && value != "on" {
+			value = ""
+		}
+		set(key, value)
+	}
+}
@@ -0,0 +1,57 @@
+// Copyright (c) 2025 Haute école d'ingénierie et d'architecture de Fribourg
+// SPDX-License-Identifier: Apache-2.0
+// Author: Astrit Aslani astrit.aslani@gmail.com
+// Created: 16.10.2026
+
+package lib
+
+import "testing"
+
+// memoryDevice stands in for the localStorage of one device, absent keys are unset
+type memoryDevice map[string]string
+
+func (d memoryDevice) get(key string) string { return d[key] }
+
+func (d memoryDevice) set(key, value string) {
+	if value == "" {
+		delete(d, key)
+	} else {
+		d[key] = value
+	}
+}
+
+// TestSnapshotPrefs_EveryKeyExplicit tests that unset preferences are still sent with a value
+func TestSnapshotPrefs_EveryKeyExplicit(t *testing.T) {
+	prefs := snapshotPrefs(memoryDevice{"discSkin": "gems"}.get)
+
+	if len(prefs) != len(syncedPrefKeys) {
+		t.Fatalf("Expected all %d keys, got %d", len(syncedPrefKeys), len(prefs))
+	}
+	if prefs["discSkin"] != "gems" || prefs["moveNumbers"] != "off" || prefs["highlightColor"] != "" {
+		t.Errorf("Unexpected snapshot %v", prefs)
+	}
+}
+
+// TestApplyPrefs_SyncsTurningOff tests that a flag turned off on one device turns off on another
+func TestApplyPrefs_SyncsTurningOff(t *testing.T) {
+	laptop := memoryDevice{"moveNumbers": "on", "highlightColor": "gold"}
+	phone := memoryDevice{"moveNumbers": "on", "highlightColor": "gold"}
+
+	laptop.set("moveNumbers", "")
+	laptop.set("highlightColor", "")
+	applyPrefs(snapshotPrefs(laptop.get), phone.set)
+
+	if _, ok := phone["moveNumbers"]; ok {
+		t.Errorf("Expected moveNumbers off on the other device, got %q", phone["moveNumbers"])
+	}
+	if _, ok := phone["highlightColor"]; ok {
+		t.Errorf("Expected highlightColor back to its default, got %q", phone["highlightColor"])
+	}
+
+	// Keys an older client never sent are left alone
+	phone.set("blockHints", "on")
+	applyPrefs(map[string]string{"moveNumbers": "on"}, phone.set)
+	if phone["moveNumbers"] != "on" || phone["blockHints"] != "on" {
+		t.Errorf("Expected only the sent key to change, got %v", phone)
+	}
+}
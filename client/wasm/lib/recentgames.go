@@ -0,0 +1,63 @@
+// Copyright (c) 2025 Haute école d'ingénierie et d'architecture de Fribourg
+// SPDX-License-Identifier: Apache-2.0
+// Author: Astrit Aslani astrit.aslani@gmail.com
+// Created: 16.10.2026
+//go:build js && wasm
+
+package lib
+
+import (
+	"fmt"
+	"syscall/js"
+)
+
+// RecentGamesLimit is how many finished games the lobby panel asks for
+const RecentGamesLimit = 10
+
+// ShowRecentGames replaces the rows of the recent games panel, each with a preview of the final board
+func ShowRecentGames(data HistoryResponseData) {
+	list := GetElement("recent-games-list")
+	if list.IsNull() {
+		return
+	}
+	list.Set("textContent", "")
+
+	document := js.Global().Get("document")
+	for _, entry := range data.Games {
+		row := document.Call("createElement", "li")
+
+		preview := document.Call("createElement", "canvas")
+		preview.Set("className", "mini-board")
+		preview.Call("setAttribute", "aria-hidden", "true")
+		DrawMiniBoard(preview, entry.Board)
+
+		// textContent keeps usernames from being interpreted as HTML
+		summary := document.Call("createElement", "span")
+		summary.Set("textContent", fmt.Sprintf("vs %s - %s in %d moves",
+			entry.Players[1-entry.Seat], recentOutcome(entry), entry.MoveCount))
+
+		row.Call("appendChild", preview)
+		row.Call("appendChild", summary)
+		list.Call("appendChild", row)
+	}
+
+	if len(data.Games) == 0 {
+		SetText("recent-games-status", "No finished games yet")
+	} else {
+		SetText("recent-games-status", "")
+	}
+}
+
+// recentOutcome names the result of a finished game from the player's seat
+func recentOutcome(entry HistoryEntry) string {
+	switch entry.Result {
+	case 1, 2:
+		if entry.Result-1 == entry.Seat {
+			return "won"
+		}
+		return "lost"
+	case 3:
+		return "draw"
+	}
+	return "unfinished"
+}
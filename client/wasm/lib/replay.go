@@ -0,0 +1,162 @@
+// Copyright (c) 2025 Haute école d'ingénierie et d'architecture de Fribourg
+// SPDX-License-Identifier: Apache-2.0
+// Author: Astrit Aslani astrit.aslani@gmail.com
+// Created: 15.10.2026
+
+package lib
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// Transcript represents a recorded game as downloaded for offline review
+type Transcript struct {
+	Players     [2]string `json:"players"`
+	FirstPlayer int       `json:"first_player"`
+	Moves       []int     `json:"moves"`            // columns in order of play
+	Result      int       `json:"result,omitempty"` // 1 or 2 for the winning seat, 3 for a draw, 0 if unknown
+}
+
+// ReplayStep represents the board after a single move of a transcript
+type ReplayStep struct {
+	Board     [Rows][Cols]int
+	Col       int
+	Row       int
+	PlayerIdx int
+}
+
+// Replay steps through a validated transcript
+type Replay struct {
+	Transcript *Transcript
+	steps      []ReplayStep
+	cursor     int // number of moves currently shown
+}
+
+// ParseTranscript decodes a transcript and validates every move by replaying it
+// A stated result must agree with the board: a completed line names the winner and a full board is a draw
+// Without either the game may still have ended by time, forfeit or agreement, so any result is accepted
+func ParseTranscript(data []byte) (*Replay, error) {
+	var transcript Transcript
+	if err := json.Unmarshal(data, &transcript); err != nil {
+		return nil, errors.New("invalid replay file: not a valid transcript")
+	}
+
+	if len(transcript.Moves) == 0 {
+		return nil, errors.New("invalid replay file: no moves recorded")
+	}
+
+	if len(transcript.Moves) > Rows*Cols {
+		return nil, errors.New("invalid replay file: too many moves")
+	}
+
+	if transcript.FirstPlayer != 0 && transcript.FirstPlayer != 1 {
+		return nil, errors.New("invalid replay file: unknown first player")
+	}
+
+	// Replay columns into an empty board to check legality
+	if transcript.Result < 0 || transcript.Result > 3 {
+		return nil, errors.New("invalid replay file: unknown result")
+	}
+
+	var board [Rows][Cols]int
+	steps := make([]ReplayStep, 0, len(transcript.Moves))
+	playerIdx := transcript.FirstPlayer
+	winner := 0
+
+	for i, col := range transcript.Moves {
+		if winner != 0 {
+			return nil, fmt.Errorf("invalid replay file: move %d is played after the game was won", i+1)
+		}
+		if col < 0 || col >= Cols {
+			return nil, fmt.Errorf("invalid replay file: move %d uses column %d", i+1, col)
+		}
+
+		row := findLowestEmptyRow(col, board)
+		if row < 0 {
+			return nil, fmt.Errorf("invalid replay file: move %d plays in full column %d", i+1, col)
+		}
+
+		board[row][col] = playerIdx + 1
+		if IsWinningCell(board, row, col) {
+			winner = playerIdx + 1
+		}
+		steps = append(steps, ReplayStep{
+			Board:     board,
+			Col:       col,
+			Row:       row,
+			PlayerIdx: playerIdx,
+		})
+		playerIdx = 1 - playerIdx
+	}
+
+	switch {
+	case transcript.Result == 0:
+	case winner != 0 && transcript.Result != winner:
+		return nil, errors.New("invalid replay file: result does not match the winning line")
+	case winner == 0 && len(steps) == Rows*Cols && transcript.Result != 3:
+		return nil, errors.New("invalid replay file: a full board without a line is a draw")
+	}
+
+	return &Replay{
+		Transcript: &transcript,
+		steps:      steps,
+	}, nil
+}
+
+// Len returns the number of moves in the replay
+func (r *Replay) Len() int {
+	return len(r.steps)
+}
+
+// Cursor returns the number of moves currently shown
+func (r *Replay) Cursor() int {
+	return r.cursor
+}
+
+// Current returns the step currently shown, or nil at the initial position
+func (r *Replay) Current() *ReplayStep {
+	if r.cursor == 0 {
+		return nil
+	}
+	return &r.steps[r.cursor-1]
+}
+
+// Next advances one move and returns the new step
+func (r *Replay) Next() (*ReplayStep, bool) {
+	if r.cursor >= len(r.steps) {
+		return nil, false
+	}
+	r.cursor++
+	return r.Current(), true
+}
+
+// Prev goes back one move
+func (r *Replay) Prev() bool {
+	if r.cursor == 0 {
+		return false
+	}
+	r.cursor--
+	return true
+}
+
+// Seek jumps to the given number of moves
+func (r *Replay) Seek(cursor int) {
+	if cursor < 0 {
+		cursor = 0
+	}
+	if cursor > len(r.steps) {
+		cursor = len(r.steps)
+	}
+	r.cursor = cursor
+}
+
+// MoveNumbers returns the order of play of the tokens shown at the cursor
+func (r *Replay) MoveNumbers() [Rows][Cols]int {
+	var numbers [Rows][Cols]int
+	for i, step := range r.steps[:r.cursor] {
+		numbers[step.Row][step.Col] = i + 1
+	}
+	return numbers
+}
@@ -0,0 +1,36 @@
+// Copyright (c) 2025 Haute école d'ingénierie et d'architecture de Fribourg
+// SPDX-License-Identifier: Apache-2.0
+// Author: Astrit Aslani astrit.aslani@gmail.com
+// Created: 16.10.2026
+
+package lib
+
+import "testing"
+
+// TestParseTranscript tests that transcripts are replayed and checked against their stated result
+func TestParseTranscript(t *testing.T) {
+	cases := []struct {
+		name  string
+		data  string
+		valid bool
+	}{
+		{"valid win", `{"first_player":0,"moves":[0,1,0,1,0,1,0],"result":1}`, true},
+		{"valid unfinished", `{"first_player":1,"moves":[3,3,4],"result":2}`, true},
+		{"valid without result", `{"first_player":0,"moves":[3]}`, true},
+		{"overfull column", `{"first_player":0,"moves":[2,2,2,2,2,2,2]}`, false},
+		{"move after win", `{"first_player":0,"moves":[0,1,0,1,0,1,0,1],"result":1}`, false},
+		{"wrong winner", `{"first_player":0,"moves":[0,1,0,1,0,1,0],"result":2}`, false},
+		{"draw despite line", `{"first_player":0,"moves":[0,1,0,1,0,1,0],"result":3}`, false},
+		{"unknown result", `{"first_player":0,"moves":[3],"result":4}`, false},
+	}
+
+	for _, tc := range cases {
+		_, err := ParseTranscript([]byte(tc.data))
+		if tc.valid && err != nil {
+			t.Errorf("%s: unexpected error: %v", tc.name, err)
+		}
+		if !tc.valid && err == nil {
+			t.Errorf("%s: expected the transcript to be rejected", tc.name)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+// Copyright (c) 2025 Haute école d'ingénierie et d'architecture de Fribourg
+// SPDX-License-Identifier: Apache-2.0
+// Author: Astrit Aslani astrit.aslani@gmail.com
+// Created: 16.10.2026
+
+package lib
+
+// Four-in-a-row checks on hypothetical boards, kept free of browser APIs so they can be tested natively
+
+// WouldWin checks if dropping a token for owner in column completes a line
+// The board is passed by value so the hypothetical move never leaks into the state
+func WouldWin(board [Rows][Cols]int, column, owner int) bool {
+	if column < 0 || column >= Cols {
+		return false
+	}
+
+	row := findLowestEmptyRow(column, board)
+	if row < 0 {
+		return false
+	}
+
+	board[row][column] = owner
+	return IsWinningCell(board, row, column)
+}
+
+// IsWinningCell checks if the token at the given cell is part of a winning line
+func IsWinningCell(board [Rows][Cols]int, row, col int) bool {
+	owner := board[row][col]
+	if owner == 0 {
+		return false
+	}
+
+	for _, dir := range winDirections {
+		count := 1 + countDirection(board, row, col, dir[0], dir[1]) + countDirection(board, row, col, -dir[0], -dir[1])
+		if count >= WinLength {
+			return true
+		}
+	}
+	return false
+}
+
+// countDirection counts consecutive tokens of the same owner from a cell in one direction
+func countDirection(board [Rows][Cols]int, row, col, rowStep, colStep int) int {
+	owner := board[row][col]
+	count := 0
+	for r, c := row+rowStep, col+colStep; r >= 0 && r < Rows && c >= 0 && c < Cols; r, c = r+rowStep, c+colStep {
+		if board[r][c] != owner {
+			break
+		}
+		count++
+	}
+	return count
+}
+
+// findLowestEmptyRow returns the lowest empty row in a column, or -1 if full
+func findLowestEmptyRow(column int, board [Rows][Cols]int) int {
+	for row := Rows - 1; row >= 0; row-- {
+		if board[row][column] == 0 {
+			return row
+		}
+	}
+	return -1
+}
+
+// ForcedBlockColumn returns the column the owner must play to stop an immediate opponent win, or -1
+// Only a single threat is reported: with none there is nothing to block, with several the game is lost anyway,
+// and an owner who can win right away has no need to block
+func ForcedBlockColumn(board [Rows][Cols]int, owner int) int {
+	opponent := 3 - owner
+	block := -1
+	for col := 0; col < Cols; col++ {
+		if WouldWin(board, col, owner) {
+			return -1
+		}
+		if WouldWin(board, col, opponent) {
+			if block >= 0 {
+				return -1
+			}
+			block = col
+		}
+	}
+	return block
+}
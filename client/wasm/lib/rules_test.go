@@ -0,0 +1,54 @@
+// Copyright (c) 2025 Haute école d'ingénierie et d'architecture de Fribourg
+// SPDX-License-Identifier: Apache-2.0
+// Author: Astrit Aslani astrit.aslani@gmail.com
+// Created: 16.10.2026
+
+package lib
+
+import (
+	"strings"
+	"testing"
+)
+
+// boardFromRows builds a board from its bottom rows drawn top to bottom, "r" for owner 1 and "y" for owner 2
+func boardFromRows(t *testing.T, rows ...string) [Rows][Cols]int {
+	t.Helper()
+	empty := strings.Repeat(".", Cols) + "/"
+	position, err := ParsePosition(strings.Repeat(empty, Rows-len(rows)) + strings.Join(rows, "/") + " r")
+	if err != nil {
+		t.Fatalf("Bad test board: %v", err)
+	}
+	return position.Board
+}
+
+// TestForcedBlockColumn tests that only a single immediate threat is reported
+func TestForcedBlockColumn(t *testing.T) {
+	cases := []struct {
+		name  string
+		board [Rows][Cols]int
+		want  int
+	}{
+		{"no threat", boardFromRows(t, "...y...", "...r..."), -1},
+		{"horizontal", boardFromRows(t, "yyy.rr."), 3},
+		{"vertical", boardFromRows(t, "y......", "y......", "yrr...."), 0},
+		{"two threats", boardFromRows(t, ".yyy.r.", "rrryr.."), -1},
+		{"own win first", boardFromRows(t, "yyy....", "rrr...."), -1},
+	}
+
+	for _, tc := range cases {
+		if got := ForcedBlockColumn(tc.board, 1); got != tc.want {
+			t.Errorf("%s: expected column %d, got %d", tc.name, tc.want, got)
+		}
+	}
+}
+
+// TestWouldWin_LeavesBoard tests that the hypothetical move does not change the caller's board
+func TestWouldWin_LeavesBoard(t *testing.T) {
+	board := boardFromRows(t, "rrr....")
+	if !WouldWin(board, 3, 1) {
+		t.Fatal("Expected column 3 to complete the line")
+	}
+	if board[Rows-1][3] != 0 {
+		t.Error("WouldWin should not modify the board")
+	}
+}
@@ -0,0 +1,431 @@
+// Copyright (c) 2025 Haute école d'ingénierie et d'architecture de Fribourg
+// SPDX-License-Identifier: Apache-2.0
+// Author: Astrit Aslani astrit.aslani@gmail.com
+// Created: 16.10.2026
+//go:build js && wasm
+
+package lib
+
+import "sync"
+
+// Token rendering styles
+const (
+	RenderGlossy = "glossy"
+	RenderFlat   = "flat"
+)
+
+// Settings holds the local display preferences persisted in localStorage
+type Settings struct {
+	mutex sync.RWMutex
+
+	RenderStyle    string
+	DiscSkin       string
+	HighlightColor string // Empty to follow the disc skin
+	HighlightPulse bool
+	SyncPrefs      bool // Save preferences to the server, off by default
+	WinningPreview bool
+	TurnAlerts     bool
+	AutoRematch    bool
+	GravityTrail   bool
+	ConfirmMoves   bool
+	MirrorBoard    bool
+	HighContrast   bool
+	StreamPalette  bool // Capture friendly token colors, combines with high contrast
+	MoveNumbers    bool
+	MeFirst        bool // Show the local player on the first card whatever their seat
+	BlockHints     bool
+	DropEasing     string
+}
+
+var settings = &Settings{
+	RenderStyle: RenderGlossy,
+	DiscSkin:    SkinClassic,
+	DropEasing:  EasingGravity,
+}
+
+// GetSettings returns the settings singleton
+func GetSettings() *Settings {
+	return settings
+}
+
+// LoadSettings restores preferences from localStorage
+func LoadSettings() {
+	settings.mutex.Lock()
+	defer settings.mutex.Unlock()
+
+	if GetLocalStorage("renderStyle") == RenderFlat {
+		settings.RenderStyle = RenderFlat
+	}
+	if skin := GetLocalStorage("discSkin"); IsSkin(skin) {
+		settings.DiscSkin = skin
+	}
+	if color := GetLocalStorage("highlightColor"); IsHighlightColor(color) {
+		settings.HighlightColor = color
+	}
+	settings.DropEasing = EasingGravity
+	if easing := GetLocalStorage("dropEasing"); IsDropEasing(easing) {
+		settings.DropEasing = easing
+	}
+	settings.HighlightPulse = GetLocalStorage("highlightPulse") == "on"
+	settings.WinningPreview = GetLocalStorage("winningPreview") == "on"
+	settings.TurnAlerts = GetLocalStorage("turnAlerts") == "on"
+	settings.AutoRematch = GetLocalStorage("autoRematch") == "on"
+	settings.GravityTrail = GetLocalStorage("gravityTrail") == "on"
+	settings.ConfirmMoves = GetLocalStorage("confirmMoves") == "on"
+	settings.MirrorBoard = GetLocalStorage("mirrorBoard") == "on"
+	settings.HighContrast = GetLocalStorage("highContrast") == "on"
+	settings.StreamPalette = GetLocalStorage("streamPalette") == "on"
+	settings.MoveNumbers = GetLocalStorage("moveNumbers") == "on"
+	settings.MeFirst = GetLocalStorage("meFirst") == "on"
+	settings.BlockHints = GetLocalStorage("blockHints") == "on"
+	settings.SyncPrefs = GetLocalStorage("syncPrefs") == "on"
+}
+
+// GetRenderStyle returns the token rendering style
+func (s *Settings) GetRenderStyle() string {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.RenderStyle
+}
+
+// SetRenderStyle updates and persists the token rendering style
+func (s *Settings) SetRenderStyle(style string) {
+	if style != RenderFlat {
+		style = RenderGlossy
+	}
+
+	s.mutex.Lock()
+	s.RenderStyle = style
+	s.mutex.Unlock()
+
+	savePreference("renderStyle", style)
+}
+
+// IsFlat checks if tokens are drawn without shine and shadows
+func (s *Settings) IsFlat() bool {
+	return s.GetRenderStyle() == RenderFlat
+}
+
+// GetDiscSkin returns the selected disc skin
+func (s *Settings) GetDiscSkin() string {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.DiscSkin
+}
+
+// SetDiscSkin updates and persists the disc skin, unknown skins fall back to classic discs
+func (s *Settings) SetDiscSkin(skin string) {
+	if !IsSkin(skin) {
+		skin = SkinClassic
+	}
+
+	s.mutex.Lock()
+	s.DiscSkin = skin
+	s.mutex.Unlock()
+
+	savePreference("discSkin", skin)
+}
+
+// GetDropEasing returns the easing used by falling discs
+func (s *Settings) GetDropEasing() string {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.DropEasing
+}
+
+// SetDropEasing updates and persists the easing of falling discs, unknown names fall back to gravity
+func (s *Settings) SetDropEasing(easing string) {
+	if !IsDropEasing(easing) {
+		easing = EasingGravity
+	}
+
+	s.mutex.Lock()
+	s.DropEasing = easing
+	s.mutex.Unlock()
+
+	// Gravity is the default, so it is not stored
+	if easing == EasingGravity {
+		easing = ""
+	}
+	savePreference("dropEasing", easing)
+}
+
+// GetHighlightColor returns the chosen last move ring color, empty to follow the skin
+func (s *Settings) GetHighlightColor() string {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.HighlightColor
+}
+
+// SetHighlightColor updates and persists the last move ring color
+func (s *Settings) SetHighlightColor(color string) {
+	if !IsHighlightColor(color) {
+		color = ""
+	}
+
+	s.mutex.Lock()
+	s.HighlightColor = color
+	s.mutex.Unlock()
+
+	savePreference("highlightColor", color)
+}
+
+// GetHighlightPulse returns whether the last move ring pulses
+func (s *Settings) GetHighlightPulse() bool {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.HighlightPulse
+}
+
+// SetHighlightPulse updates and persists the pulsing last move ring
+func (s *Settings) SetHighlightPulse(enabled bool) {
+	s.mutex.Lock()
+	s.HighlightPulse = enabled
+	s.mutex.Unlock()
+
+	setLocalStorageFlag("highlightPulse", enabled)
+}
+
+// GetWinningPreview returns whether winning hover columns are emphasized
+func (s *Settings) GetWinningPreview() bool {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.WinningPreview
+}
+
+// SetWinningPreview updates and persists the winning move coaching hint
+func (s *Settings) SetWinningPreview(enabled bool) {
+	s.mutex.Lock()
+	s.WinningPreview = enabled
+	s.mutex.Unlock()
+
+	setLocalStorageFlag("winningPreview", enabled)
+}
+
+// GetGravityTrail returns whether the hover preview shows its drop path
+func (s *Settings) GetGravityTrail() bool {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.GravityTrail
+}
+
+// SetGravityTrail updates and persists the hover drop path
+func (s *Settings) SetGravityTrail(enabled bool) {
+	s.mutex.Lock()
+	s.GravityTrail = enabled
+	s.mutex.Unlock()
+
+	setLocalStorageFlag("gravityTrail", enabled)
+}
+
+// GetConfirmMoves returns whether moves need a second click to be played
+func (s *Settings) GetConfirmMoves() bool {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.ConfirmMoves
+}
+
+// SetConfirmMoves updates and persists the move confirmation step
+func (s *Settings) SetConfirmMoves(enabled bool) {
+	s.mutex.Lock()
+	s.ConfirmMoves = enabled
+	s.mutex.Unlock()
+
+	setLocalStorageFlag("confirmMoves", enabled)
+}
+
+// GetMirrorBoard returns whether the board is mirrored when playing second
+func (s *Settings) GetMirrorBoard() bool {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.MirrorBoard
+}
+
+// SetMirrorBoard updates and persists the mirrored board for the second player
+func (s *Settings) SetMirrorBoard(enabled bool) {
+	s.mutex.Lock()
+	s.MirrorBoard = enabled
+	s.mutex.Unlock()
+
+	setLocalStorageFlag("mirrorBoard", enabled)
+}
+
+// GetHighContrast returns whether the board and game info use the high contrast palette
+func (s *Settings) GetHighContrast() bool {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.HighContrast
+}
+
+// SetHighContrast updates and persists the high contrast palette
+func (s *Settings) SetHighContrast(enabled bool) {
+	s.mutex.Lock()
+	s.HighContrast = enabled
+	s.mutex.Unlock()
+
+	setLocalStorageFlag("highContrast", enabled)
+}
+
+// GetStreamPalette returns whether tokens use the deep red and blue stream palette
+func (s *Settings) GetStreamPalette() bool {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.StreamPalette
+}
+
+// SetStreamPalette updates and persists the stream palette
+func (s *Settings) SetStreamPalette(enabled bool) {
+	s.mutex.Lock()
+	s.StreamPalette = enabled
+	s.mutex.Unlock()
+
+	setLocalStorageFlag("streamPalette", enabled)
+}
+
+// GetMoveNumbers returns whether tokens show their order of play
+func (s *Settings) GetMoveNumbers() bool {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.MoveNumbers
+}
+
+// SetMoveNumbers updates and persists the move numbers drawn on tokens
+func (s *Settings) SetMoveNumbers(enabled bool) {
+	s.mutex.Lock()
+	s.MoveNumbers = enabled
+	s.mutex.Unlock()
+
+	setLocalStorageFlag("moveNumbers", enabled)
+}
+
+// GetMeFirst returns whether the local player is always shown on the first card
+func (s *Settings) GetMeFirst() bool {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.MeFirst
+}
+
+// SetMeFirst updates and persists the player card order
+func (s *Settings) SetMeFirst(enabled bool) {
+	s.mutex.Lock()
+	s.MeFirst = enabled
+	s.mutex.Unlock()
+
+	setLocalStorageFlag("meFirst", enabled)
+}
+
+// GetBlockHints returns whether the column blocking an immediate threat is pointed out
+func (s *Settings) GetBlockHints() bool {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.BlockHints
+}
+
+// SetBlockHints updates and persists the forced block coaching hint
+func (s *Settings) SetBlockHints(enabled bool) {
+	s.mutex.Lock()
+	s.BlockHints = enabled
+	s.mutex.Unlock()
+
+	setLocalStorageFlag("blockHints", enabled)
+}
+
+// GetTurnAlerts returns whether background turn alerts are enabled
+func (s *Settings) GetTurnAlerts() bool {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.TurnAlerts
+}
+
+// SetTurnAlerts updates and persists the background turn alerts
+func (s *Settings) SetTurnAlerts(enabled bool) {
+	s.mutex.Lock()
+	s.TurnAlerts = enabled
+	s.mutex.Unlock()
+
+	setLocalStorageFlag("turnAlerts", enabled)
+}
+
+// GetAutoRematch returns whether replays are requested automatically
+func (s *Settings) GetAutoRematch() bool {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.AutoRematch
+}
+
+// SetAutoRematch updates and persists the automatic replay request
+func (s *Settings) SetAutoRematch(enabled bool) {
+	s.mutex.Lock()
+	s.AutoRematch = enabled
+	s.mutex.Unlock()
+
+	setLocalStorageFlag("autoRematch", enabled)
+}
+
+// setLocalStorageFlag persists a boolean preference
+func setLocalStorageFlag(key string, enabled bool) {
+	if enabled {
+		savePreference(key, "on")
+	} else {
+		savePreference(key, "")
+	}
+}
+
+// savePreference persists a preference, empty values are removed, and syncs it if enabled
+func savePreference(key, value string) {
+	if value == "" {
+		RemoveLocalStorage(key)
+	} else {
+		SetLocalStorage(key, value)
+	}
+	PushPrefs()
+}
+
+// GetSyncPrefs returns whether preferences are saved to the server
+func (s *Settings) GetSyncPrefs() bool {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.SyncPrefs
+}
+
+// SetSyncPrefs updates and persists whether preferences are saved to the server
+// The choice itself stays local so a device can opt out
+func (s *Settings) SetSyncPrefs(enabled bool) {
+	s.mutex.Lock()
+	s.SyncPrefs = enabled
+	s.mutex.Unlock()
+
+	if enabled {
+		SetLocalStorage("syncPrefs", "on")
+	} else {
+		RemoveLocalStorage("syncPrefs")
+	}
+	PushPrefs()
+}
+
+// localPrefs returns every synced preference currently stored on this device
+func localPrefs() map[string]string {
+	return snapshotPrefs(GetLocalStorage)
+}
+
+// PushPrefs sends the local preferences to the server when sync is enabled
+func PushPrefs() {
+	if !settings.GetSyncPrefs() {
+		return
+	}
+	SendMessage("save_prefs", map[string]interface{}{"prefs": localPrefs()})
+}
+
+// MergePrefs applies preferences from the server over the local ones and reloads the settings
+// Keys only known locally are kept, so the merged set is pushed back afterwards
+func MergePrefs(prefs map[string]string) {
+	applyPrefs(prefs, func(key, value string) {
+		if value == "" {
+			RemoveLocalStorage(key)
+		} else {
+			SetLocalStorage(key, value)
+		}
+	})
+	LoadSettings()
+	PushPrefs()
+}
@@ -0,0 +1,260 @@
+// Copyright (c) 2025 Haute école d'ingénierie et d'architecture de Fribourg
+// SPDX-License-Identifier: Apache-2.0
+// Author: Astrit Aslani astrit.aslani@gmail.com
+// Created: 16.10.2026
+//go:build js && wasm
+
+package lib
+
+import "syscall/js"
+
+// Disc skins
+const (
+	SkinClassic = "classic"
+	SkinTokens  = "tokens"
+	SkinEmoji   = "emoji"
+	SkinFruit   = "fruit"
+	SkinHearts  = "hearts"
+)
+
+// skin describes how discs and the board frame are drawn
+type skin struct {
+	images    [2]string // Disc image sources per seat, empty for colored discs
+	emoji     [2]string // Emoji rendered into disc images per seat
+	board     string    // Board frame color
+	highlight string    // Last move ring color, chosen to contrast with the board
+	locked    bool      // Only usable once the server reports it unlocked
+}
+
+var skins = map[string]skin{
+	SkinClassic: {board: ColorBoardBg, highlight: "#ffffff"},
+	SkinTokens:  {images: [2]string{"assets/token/red.png", "assets/token/yellow.png"}, board: ColorBoardBg, highlight: "#ffffff"},
+	SkinEmoji:   {emoji: [2]string{"🔴", "🟡"}, board: "#1e3a8a", highlight: ColorHighlight},
+	SkinFruit:   {emoji: [2]string{"🍓", "🍋"}, board: "#14532d", highlight: "#ffffff", locked: true},
+	SkinHearts:  {emoji: [2]string{"❤️", "💛"}, board: "#4c1d95", highlight: "#ffffff", locked: true},
+}
+
+// High contrast palette, pure token colors and a white frame that stand out on any skin
+const (
+	contrastPlayer0      = "#ff0000"
+	contrastPlayer1      = "#ffff00"
+	contrastPlayer0Alpha = "rgba(255, 0, 0, "
+	contrastPlayer1Alpha = "rgba(255, 255, 0, "
+	contrastBorder       = "#ffffff"
+	contrastBorderWidth  = 4
+	contrastHighlight    = 14
+)
+
+// Stream palette, saturated deep colors that survive video capture where yellow blows out
+const (
+	streamPlayer0      = "#c1121f"
+	streamPlayer1      = "#1d4ed8"
+	streamPlayer0Alpha = "rgba(193, 18, 31, "
+	streamPlayer1Alpha = "rgba(29, 78, 216, "
+	streamHighlight    = "#ffffff"
+)
+
+// Skins unlocked by session wins, as last reported by the server
+var unlockedSkins = map[string]bool{}
+
+// Last move ring colors selectable in the settings, the empty choice follows the skin
+var highlightColors = map[string]bool{
+	"":        true,
+	"#ffffff": true,
+	"#5dc9e2": true,
+	"#f97316": true,
+	"#22c55e": true,
+}
+
+// Disc images per skin, loaded once and reused by every frame
+var skinImages = map[string][2]js.Value{}
+
+// loadSkins starts loading the disc images of every skin
+func loadSkins() {
+	for name, s := range skins {
+		var images [2]js.Value
+		hasImages := false
+		for seat := range images {
+			src := s.images[seat]
+			if src == "" && s.emoji[seat] != "" {
+				src = emojiDiscSource(s.emoji[seat])
+			}
+			if src == "" {
+				continue
+			}
+			images[seat] = loadImage(src)
+			hasImages = true
+		}
+		if hasImages {
+			skinImages[name] = images
+		}
+	}
+}
+
+// loadImage creates an image that redraws the board once it is ready
+func loadImage(src string) js.Value {
+	image := js.Global().Get("Image").New()
+	var onLoad js.Func
+	onLoad = js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		onLoad.Release()
+		Draw()
+		return nil
+	})
+	image.Set("onload", onLoad)
+	image.Set("src", src)
+	return image
+}
+
+// emojiDiscSource builds an SVG data URL showing a single emoji
+func emojiDiscSource(emoji string) string {
+	svg := `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 64 64">` +
+		`<text x="32" y="34" font-size="56" text-anchor="middle" dominant-baseline="middle">` + emoji + `</text></svg>`
+	return "data:image/svg+xml;charset=utf-8," + js.Global().Call("encodeURIComponent", svg).String()
+}
+
+// discImage returns the loaded disc image of the selected skin for a board owner
+// Images still loading or that failed to load fall back to colored discs, as does the stream palette
+func discImage(owner int) (js.Value, bool) {
+	if owner < 1 || owner > 2 || GetSettings().GetStreamPalette() {
+		return js.Value{}, false
+	}
+
+	images, ok := skinImages[activeSkin()]
+	if !ok {
+		return js.Value{}, false
+	}
+
+	image := images[owner-1]
+	if image.IsUndefined() || !image.Get("complete").Bool() || image.Get("naturalWidth").Int() == 0 {
+		return js.Value{}, false
+	}
+	return image, true
+}
+
+// boardColor returns the board frame color of the selected skin
+func boardColor() string {
+	if s, ok := skins[activeSkin()]; ok && s.board != "" {
+		return s.board
+	}
+	return ColorBoardBg
+}
+
+// tokenColor returns the solid color of a seat's token
+func tokenColor(seat int) string {
+	switch {
+	case seat == 1 && GetSettings().GetStreamPalette():
+		return streamPlayer1
+	case GetSettings().GetStreamPalette():
+		return streamPlayer0
+	case seat == 1 && GetSettings().GetHighContrast():
+		return contrastPlayer1
+	case seat == 1:
+		return ColorPlayer1
+	case GetSettings().GetHighContrast():
+		return contrastPlayer0
+	}
+	return ColorPlayer0
+}
+
+// tokenColorAlpha returns the rgba prefix of a seat's token, to be completed with an alpha and ")"
+func tokenColorAlpha(seat int) string {
+	switch {
+	case seat == 1 && GetSettings().GetStreamPalette():
+		return streamPlayer1Alpha
+	case GetSettings().GetStreamPalette():
+		return streamPlayer0Alpha
+	case seat == 1 && GetSettings().GetHighContrast():
+		return contrastPlayer1Alpha
+	case seat == 1:
+		return ColorPlayer1Alpha
+	case GetSettings().GetHighContrast():
+		return contrastPlayer0Alpha
+	}
+	return ColorPlayer0Alpha
+}
+
+// borderColor returns the color of the grid lines between cells
+func borderColor() string {
+	if GetSettings().GetHighContrast() {
+		return contrastBorder
+	}
+	return ColorBoardBorder
+}
+
+// borderWidth returns the width of the grid lines between cells
+func borderWidth() int {
+	if GetSettings().GetHighContrast() {
+		return contrastBorderWidth
+	}
+	return 2
+}
+
+// highlightWidth returns the width of the last move ring
+func highlightWidth() float64 {
+	if GetSettings().GetHighContrast() {
+		return contrastHighlight
+	}
+	return HighlightWidth
+}
+
+// highlightColor returns the last move ring color, the chosen one or the skin's
+func highlightColor() string {
+	if color := GetSettings().GetHighlightColor(); color != "" {
+		return color
+	}
+	if GetSettings().GetHighContrast() {
+		return contrastBorder
+	}
+	if GetSettings().GetStreamPalette() {
+		return streamHighlight
+	}
+	if s, ok := skins[activeSkin()]; ok && s.highlight != "" {
+		return s.highlight
+	}
+	return ColorHighlight
+}
+
+// IsHighlightColor checks if a color can be picked for the last move ring
+func IsHighlightColor(color string) bool {
+	return highlightColors[color]
+}
+
+// activeSkin returns the selected skin, or classic while the selection is still locked
+func activeSkin() string {
+	name := GetSettings().GetDiscSkin()
+	if !IsSkinUnlocked(name) {
+		return SkinClassic
+	}
+	return name
+}
+
+// SetUnlocks replaces the skins unlocked by the server
+func SetUnlocks(unlocks []string) {
+	unlockedSkins = map[string]bool{}
+	for _, name := range unlocks {
+		unlockedSkins[name] = true
+	}
+}
+
+// IsSkinUnlocked checks if a skin can be used right now
+func IsSkinUnlocked(name string) bool {
+	s, ok := skins[name]
+	return ok && (!s.locked || unlockedSkins[name])
+}
+
+// LockedSkins returns the skins that need to be unlocked by winning
+func LockedSkins() []string {
+	var names []string
+	for name, s := range skins {
+		if s.locked {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// IsSkin checks if a name refers to a built-in skin
+func IsSkin(name string) bool {
+	_, ok := skins[name]
+	return ok
+}
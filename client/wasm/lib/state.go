@@ -8,16 +8,11 @@ package lib
 
 import "sync"
 
-// Constants
-const (
-	Rows = 6
-	Cols = 7
-)
-
 // Player represents player information
 type Player struct {
 	ID       string `json:"id"`
 	Username string `json:"username"`
+	IsBot    bool   `json:"is_bot"` // Seat played by the server
 }
 
 // LastMove represents the last move played
@@ -26,6 +21,22 @@ type LastMove struct {
 	Row int
 }
 
+// Series tracks the rounds of a best-of friend game
+type Series struct {
+	BestOf int    // 0 or 1 for a single game
+	Score  [2]int // Rounds won per side
+	Over   bool
+	Result int // Winner once over, 1 or 2 for a side and 3 for a shared victory
+}
+
+// PendingMove is a move shown locally before the server confirms it
+type PendingMove struct {
+	Col          int
+	Row          int
+	PrevBoard    [Rows][Cols]int
+	PrevLastMove *LastMove
+}
+
 // State holds all game state (singleton pattern)
 type State struct {
 	mutex sync.RWMutex
@@ -37,16 +48,35 @@ type State struct {
 	IsGameFinished          bool
 	Board                   [Rows][Cols]int
 	HoverCol                int
+	MoveArmed               bool // HoverCol waits for a confirmation click
 	Players                 [2]Player
 	ReplayRequested         bool
 	OpponentRequestedReplay bool
 	TimeRemaining           [2]int64 // milliseconds
 	LastMove                *LastMove
+	WinningCells            [][2]int        // Row and column of each cell of the winning line, nil until a round is won
+	MoveNumbers             [Rows][Cols]int // Order of play of each token, 0 when unknown
+	MovesPlayed             int
+	PendingMove             *PendingMove // Optimistic move waiting for the server echo
+	Replay                  *Replay
+	Puzzle                  bool      // A puzzle rush is running instead of a game
+	Watching                bool      // Following someone else's game through its event stream
+	Editor                  *Position // Offline board editor, nil when not editing
+	IsRanked                bool
+	ReplayAllowed           bool
+	Paused                  bool
+	Offline                 [2]bool // Sides reported as disconnected
+	Series                  Series
+	Capabilities            ServerCapabilities // Features enabled on the server
+	RematchDeclined         bool               // Opponent declined a rematch, auto-rematch stays off for the session
 }
 
 var instance *State
 var once sync.Once
 
+// classicCapabilities is assumed for servers that do not advertise their features
+var classicCapabilities = ServerCapabilities{FriendGames: true, Matchmaking: true, Chat: true}
+
 // Get returns the singleton state instance
 func Get() *State {
 	once.Do(func() {
@@ -54,6 +84,7 @@ func Get() *State {
 			PlayerIdx:      -1,
 			HoverCol:       -1,
 			IsGameFinished: false,
+			Capabilities:   classicCapabilities,
 		}
 	})
 	return instance
@@ -87,6 +118,10 @@ func (state *State) ResetBoard() {
 	defer state.mutex.Unlock()
 	state.Board = [Rows][Cols]int{}
 	state.LastMove = nil
+	state.WinningCells = nil
+	state.PendingMove = nil
+	state.MoveNumbers = [Rows][Cols]int{}
+	state.MovesPlayed = 0
 }
 
 // ClearHover removes hover preview
@@ -94,6 +129,22 @@ func (state *State) ClearHover() {
 	state.mutex.Lock()
 	defer state.mutex.Unlock()
 	state.HoverCol = -1
+	state.MoveArmed = false
+}
+
+// IsMoveArmed returns whether the hover column waits for a confirmation click
+func (state *State) IsMoveArmed() bool {
+	state.mutex.RLock()
+	defer state.mutex.RUnlock()
+	return state.MoveArmed
+}
+
+// ArmMove previews a column until it is clicked again
+func (state *State) ArmMove(col int) {
+	state.mutex.Lock()
+	defer state.mutex.Unlock()
+	state.HoverCol = col
+	state.MoveArmed = true
 }
 
 // GetHoverCol returns current hover column
@@ -117,11 +168,13 @@ func (state *State) GetBoard() [Rows][Cols]int {
 	return state.Board
 }
 
-// SetBoard updates the entire board
+// SetBoard updates the entire board, dropping any optimistic move it supersedes
 func (state *State) SetBoard(board [Rows][Cols]int) {
 	state.mutex.Lock()
 	defer state.mutex.Unlock()
 	state.Board = board
+	state.PendingMove = nil
+	state.WinningCells = nil
 }
 
 // GetPlayerIdx returns player index
@@ -243,6 +296,55 @@ func (state *State) SetLastMove(col, row int) {
 	state.LastMove = &LastMove{Col: col, Row: row}
 }
 
+// RecordMove numbers a confirmed move in order of play
+func (state *State) RecordMove(col, row int) {
+	state.mutex.Lock()
+	defer state.mutex.Unlock()
+	state.MovesPlayed++
+	state.MoveNumbers[row][col] = state.MovesPlayed
+}
+
+// SetMoveNumbers replaces the order of play, used when it is known in full or not at all
+// Moves recorded afterwards continue from movesPlayed
+func (state *State) SetMoveNumbers(numbers [Rows][Cols]int, movesPlayed int) {
+	state.mutex.Lock()
+	defer state.mutex.Unlock()
+	state.MoveNumbers = numbers
+	state.MovesPlayed = movesPlayed
+}
+
+// SetMoveOrder numbers the tokens from the moves of the round in order of play
+func (state *State) SetMoveOrder(moves []LastMove) {
+	var numbers [Rows][Cols]int
+	for i, move := range moves {
+		if move.Row >= 0 && move.Row < Rows && move.Col >= 0 && move.Col < Cols {
+			numbers[move.Row][move.Col] = i + 1
+		}
+	}
+	state.SetMoveNumbers(numbers, len(moves))
+}
+
+// GetMoveNumbers returns the order of play of each token
+func (state *State) GetMoveNumbers() [Rows][Cols]int {
+	state.mutex.RLock()
+	defer state.mutex.RUnlock()
+	return state.MoveNumbers
+}
+
+// SetWinningCells records the line that won the round, set after the final board
+func (state *State) SetWinningCells(cells [][2]int) {
+	state.mutex.Lock()
+	defer state.mutex.Unlock()
+	state.WinningCells = cells
+}
+
+// GetWinningCells returns the cells of the winning line, nil when the round was not won on the board
+func (state *State) GetWinningCells() [][2]int {
+	state.mutex.RLock()
+	defer state.mutex.RUnlock()
+	return state.WinningCells
+}
+
 // GetLastMove returns the last move played
 func (state *State) GetLastMove() *LastMove {
 	state.mutex.RLock()
@@ -263,3 +365,224 @@ func (state *State) SetGameFinished(finished bool) {
 	defer state.mutex.Unlock()
 	state.IsGameFinished = finished
 }
+
+// GetReplay returns the transcript being reviewed, or nil
+func (state *State) GetReplay() *Replay {
+	state.mutex.RLock()
+	defer state.mutex.RUnlock()
+	return state.Replay
+}
+
+// SetReplay updates the transcript being reviewed
+func (state *State) SetReplay(replay *Replay) {
+	state.mutex.Lock()
+	defer state.mutex.Unlock()
+	state.Replay = replay
+}
+
+// IsReplaying checks if a transcript is being reviewed
+func (state *State) IsReplaying() bool {
+	state.mutex.RLock()
+	defer state.mutex.RUnlock()
+	return state.Replay != nil
+}
+
+// GetEditor returns the position being edited, or nil
+func (state *State) GetEditor() *Position {
+	state.mutex.RLock()
+	defer state.mutex.RUnlock()
+	return state.Editor
+}
+
+// SetEditor updates the position being edited
+func (state *State) SetEditor(editor *Position) {
+	state.mutex.Lock()
+	defer state.mutex.Unlock()
+	state.Editor = editor
+}
+
+// IsEditing checks if the board editor is open
+func (state *State) IsEditing() bool {
+	state.mutex.RLock()
+	defer state.mutex.RUnlock()
+	return state.Editor != nil
+}
+
+// SetPuzzle marks whether a puzzle rush is running
+func (state *State) SetPuzzle(puzzle bool) {
+	state.mutex.Lock()
+	defer state.mutex.Unlock()
+	state.Puzzle = puzzle
+}
+
+// InPuzzle checks if a puzzle rush is running
+func (state *State) InPuzzle() bool {
+	state.mutex.RLock()
+	defer state.mutex.RUnlock()
+	return state.Puzzle
+}
+
+// SetWatching marks whether another game is being watched
+func (state *State) SetWatching(watching bool) {
+	state.mutex.Lock()
+	defer state.mutex.Unlock()
+	state.Watching = watching
+}
+
+// IsWatching checks if another game is being watched
+func (state *State) IsWatching() bool {
+	state.mutex.RLock()
+	defer state.mutex.RUnlock()
+	return state.Watching
+}
+
+// GetRanked returns whether the current game comes from matchmaking
+func (state *State) GetRanked() bool {
+	state.mutex.RLock()
+	defer state.mutex.RUnlock()
+	return state.IsRanked
+}
+
+// SetRanked updates whether the current game comes from matchmaking
+func (state *State) SetRanked(ranked bool) {
+	state.mutex.Lock()
+	defer state.mutex.Unlock()
+	state.IsRanked = ranked
+}
+
+// IsReplayAllowed returns whether the current game can be replayed
+func (state *State) IsReplayAllowed() bool {
+	state.mutex.RLock()
+	defer state.mutex.RUnlock()
+	return state.ReplayAllowed
+}
+
+// SetReplayAllowed updates whether the current game can be replayed
+func (state *State) SetReplayAllowed(allowed bool) {
+	state.mutex.Lock()
+	defer state.mutex.Unlock()
+	state.ReplayAllowed = allowed
+}
+
+// IsPaused returns whether the game waits for a disconnected player
+func (state *State) IsPaused() bool {
+	state.mutex.RLock()
+	defer state.mutex.RUnlock()
+	return state.Paused
+}
+
+// SetPaused updates whether the game waits for a disconnected player
+func (state *State) SetPaused(paused bool) {
+	state.mutex.Lock()
+	defer state.mutex.Unlock()
+	state.Paused = paused
+}
+
+// IsPlayerOffline returns whether a side is reported as disconnected
+func (state *State) IsPlayerOffline(idx int) bool {
+	state.mutex.RLock()
+	defer state.mutex.RUnlock()
+	return idx >= 0 && idx < 2 && state.Offline[idx]
+}
+
+// SetPlayerOffline updates whether a side is reported as disconnected
+func (state *State) SetPlayerOffline(idx int, offline bool) {
+	state.mutex.Lock()
+	defer state.mutex.Unlock()
+	if idx >= 0 && idx < 2 {
+		state.Offline[idx] = offline
+	}
+}
+
+// IsRematchDeclined returns whether an opponent declined a rematch this session
+func (state *State) IsRematchDeclined() bool {
+	state.mutex.RLock()
+	defer state.mutex.RUnlock()
+	return state.RematchDeclined
+}
+
+// SetRematchDeclined records that an opponent declined a rematch
+func (state *State) SetRematchDeclined(declined bool) {
+	state.mutex.Lock()
+	defer state.mutex.Unlock()
+	state.RematchDeclined = declined
+}
+
+// GetSeries returns the best-of series of the current game
+func (state *State) GetSeries() Series {
+	state.mutex.RLock()
+	defer state.mutex.RUnlock()
+	return state.Series
+}
+
+// SetSeries updates the best-of series of the current game
+func (state *State) SetSeries(series Series) {
+	state.mutex.Lock()
+	defer state.mutex.Unlock()
+	state.Series = series
+}
+
+// InSeries checks if the current game is a best-of series with rounds left
+func (state *State) InSeries() bool {
+	state.mutex.RLock()
+	defer state.mutex.RUnlock()
+	return state.Series.BestOf > 1 && !state.Series.Over
+}
+
+// GetCapabilities returns the features enabled on the server
+func (state *State) GetCapabilities() ServerCapabilities {
+	state.mutex.RLock()
+	defer state.mutex.RUnlock()
+	return state.Capabilities
+}
+
+// SetCapabilities stores the features advertised on welcome, nil for the classic feature set
+func (state *State) SetCapabilities(capabilities *ServerCapabilities) {
+	state.mutex.Lock()
+	defer state.mutex.Unlock()
+	if capabilities == nil {
+		state.Capabilities = classicCapabilities
+		return
+	}
+	state.Capabilities = *capabilities
+}
+
+// PredictMove places our token locally and passes the turn until the server answers
+func (state *State) PredictMove(col, row int) {
+	state.mutex.Lock()
+	defer state.mutex.Unlock()
+
+	state.PendingMove = &PendingMove{
+		Col:          col,
+		Row:          row,
+		PrevBoard:    state.Board,
+		PrevLastMove: state.LastMove,
+	}
+	state.Board[row][col] = state.PlayerIdx + 1
+	state.LastMove = &LastMove{Col: col, Row: row}
+	state.CurrentTurn = 1 - state.PlayerIdx
+}
+
+// TakePendingMove returns and clears the optimistic move, or nil
+func (state *State) TakePendingMove() *PendingMove {
+	state.mutex.Lock()
+	defer state.mutex.Unlock()
+	pending := state.PendingMove
+	state.PendingMove = nil
+	return pending
+}
+
+// RollbackPendingMove restores the board from before a rejected optimistic move
+func (state *State) RollbackPendingMove() bool {
+	state.mutex.Lock()
+	defer state.mutex.Unlock()
+
+	if state.PendingMove == nil {
+		return false
+	}
+	state.Board = state.PendingMove.PrevBoard
+	state.LastMove = state.PendingMove.PrevLastMove
+	state.CurrentTurn = state.PlayerIdx
+	state.PendingMove = nil
+	return true
+}
@@ -59,7 +59,7 @@ func UpdateDisplay() {
 	times := s.GetTimeRemaining()
 
 	for i := 0; i < 2; i++ {
-		timerID := fmt.Sprintf("timer-%d", i)
+		timerID := fmt.Sprintf("timer-%d", CardSlot(i))
 		ms := times[i]
 
 		// Format time
@@ -69,6 +69,11 @@ func UpdateDisplay() {
 		// Remove all state classes
 		RemoveClass(timerID, "warning")
 		RemoveClass(timerID, "danger")
+		RemoveClass(timerID, "frozen")
+
+		if s.IsPaused() && s.IsPlayerOffline(i) {
+			AddClass(timerID, "frozen")
+		}
 
 		// Add warning/danger classes
 		if ms <= DangerThreshold {
@@ -79,6 +84,17 @@ func UpdateDisplay() {
 	}
 }
 
+// Reconcile replaces the locally counted clocks with the server's, dropping a sync sent before the turn changed
+func Reconcile(times [2]int64, currentTurn int) {
+	s := Get()
+	if s.GetGameFinished() || s.GetCurrentTurn() != currentTurn {
+		return
+	}
+
+	s.SetTimeRemaining(times)
+	UpdateDisplay()
+}
+
 // Stop stops the timer countdown
 func Stop() {
 	timerMutex.Lock()
@@ -113,7 +129,15 @@ func decrementTime() {
 		return
 	}
 
+	// The server froze the clocks while a player is away
+	if s.IsPaused() {
+		return
+	}
+
 	times := s.GetTimeRemaining()
+	if times[currentTurn] >= UntimedClock {
+		return
+	}
 	times[currentTurn] -= int64(UpdateInterval / time.Millisecond)
 
 	if times[currentTurn] < 0 {
@@ -122,16 +146,3 @@ func decrementTime() {
 
 	s.SetTimeRemaining(times)
 }
-
-// formatTime converts milliseconds to MM:SS
-func formatTime(ms int64) string {
-	if ms < 0 {
-		ms = 0
-	}
-
-	totalSeconds := ms / 1000
-	minutes := totalSeconds / 60
-	seconds := totalSeconds % 60
-
-	return fmt.Sprintf("%d:%02d", minutes, seconds)
-}
@@ -7,20 +7,34 @@
 package main
 
 import (
+	"fmt"
+	"strings"
 	"syscall/js"
 
 	"github.com/marvinEgger/GOnnect4/client/wasm/lib"
 )
 
+// buildHash identifies this bundle, set with -ldflags "-X main.buildHash=<hash>"
+var buildHash = lib.DevBuild
+
 // main entry point for the WASM client
 func main() {
 	lib.Console("GOnnect4 WASM client starting...")
+	lib.BuildHash = buildHash
 
 	lib.Initialize()
+	lib.SetupTurnAlerts()
+	lib.SetupHistory(allowHistoryNavigation)
 	setupEventListeners()
 	setupGlobalFunctions()
+	lib.SignalReady()
 
-	attemptAutoConnect()
+	// ?watch=CODE is a chrome-free view of one game, meant as a browser source for streams
+	if code := strings.ToUpper(strings.TrimSpace(lib.GetQueryParam("watch"))); code != "" {
+		startOverlay(code)
+	} else {
+		attemptAutoConnect()
+	}
 
 	// Keep the program running
 	select {}
@@ -30,7 +44,14 @@ func main() {
 func setupGlobalFunctions() {
 	js.Global().Set("playColumn", js.FuncOf(func(this js.Value, args []js.Value) interface{} {
 		if len(args) > 0 {
+			lib.ClearTurnAlert()
 			column := args[0].Int()
+			if lib.Get().InPuzzle() {
+				lib.SendMessage("puzzle_answer", map[string]interface{}{
+					"column": column,
+				})
+				return nil
+			}
 			lib.SendMessage("play", map[string]interface{}{
 				"column": column,
 			})
@@ -39,14 +60,44 @@ func setupGlobalFunctions() {
 	}))
 }
 
+// allowHistoryNavigation decides whether browser back/forward may switch screens
+// Screens are driven by the server, so history only lets the player leave a game
+func allowHistoryNavigation(from, to string) bool {
+	if from != "game" || to != "lobby" {
+		return false
+	}
+
+	state := lib.Get()
+	switch {
+	case state.IsReplaying():
+		stopReplay()
+	case state.InPuzzle():
+		stopPuzzle()
+	case state.IsEditing():
+		stopEditor()
+	case state.IsWatching():
+		stopWatching()
+	case state.GetGameFinished():
+		lib.SendMessage("leave_lobby", map[string]interface{}{})
+	default:
+		if !lib.Confirm("Leave the game? You will forfeit it.") {
+			return false
+		}
+		lib.SendMessage("leave_lobby", map[string]interface{}{})
+	}
+	return true
+}
+
 // attemptAutoConnect tries to reconnect with saved credentials
 func attemptAutoConnect() {
-	savedPlayerID := lib.GetLocalStorage("playerID")
+	savedToken := lib.GetLocalStorage("resumeToken")
 	savedUsername := lib.GetLocalStorage("username")
 
-	if savedPlayerID != "" && savedUsername != "" {
+	if savedToken != "" && savedUsername != "" {
+		// The loading screen stays up until the welcome arrives
 		lib.Console("Auto-connecting...")
-		autoConnect(savedUsername, savedPlayerID)
+		lib.SetText("loading-message", "Reconnecting...")
+		autoConnect(savedUsername, savedToken)
 	} else {
 		lib.Console("No saved credentials, showing login screen")
 		lib.ShowScreen("login")
@@ -64,13 +115,20 @@ func updatePlayers() {
 	playerIdx := state.GetPlayerIdx()
 
 	for i := 0; i < 2; i++ {
-		cardID := "player-" + string(rune('0'+i))
+		cardID := fmt.Sprintf("player-%d", lib.CardSlot(i))
+
+		// The card style follows the seat shown, which may differ from its position
+		lib.ToggleClass(cardID, "player-0", i == 0)
+		lib.ToggleClass(cardID, "player-1", i == 1)
 
 		// Update player name
 		name := players[i].Username
 		if name == "" {
 			name = "Waiting..."
 		}
+		if players[i].IsBot {
+			name += " 🤖"
+		}
 
 		nameElement := lib.GetElement(cardID)
 		if !nameElement.IsNull() {
@@ -79,17 +137,35 @@ func updatePlayers() {
 				nameDiv.Set("textContent", name)
 			}
 
+			token := nameElement.Call("querySelector", ".player-token")
+			if !token.IsNull() {
+				token.Set("src", playerTokenImage(i))
+				token.Set("alt", lib.PlayerColorName(i)+" token player")
+			}
+
+			// Update color dot matching the seat's token
+			colorDot := nameElement.Call("querySelector", ".player-color")
+			if !colorDot.IsNull() {
+				colorDot.Get("style").Set("backgroundColor", lib.PlayerColor(i))
+				colorDot.Set("title", lib.PlayerColorName(i))
+				colorDot.Call("setAttribute", "aria-label", lib.PlayerColorName(i)+" tokens")
+			}
+
 			// Update badge (You/Opponent)
 			badgeDiv := nameElement.Call("querySelector", ".player-badge")
 			if !badgeDiv.IsNull() {
 				badge := "Opponent"
 				if i == playerIdx {
 					badge = "You"
+				} else if players[i].IsBot {
+					badge = "Bot"
 				}
 				badgeDiv.Set("textContent", badge)
 			}
 		}
 
+		showPresence(i)
+
 		// Update active state (shows which player is YOU, not the current turn)
 		if playerIdx == i {
 			lib.AddClass(cardID, "active")
@@ -99,21 +175,40 @@ func updatePlayers() {
 	}
 }
 
+// playerTokenImage returns the token picture shown on the card of a seat
+func playerTokenImage(seat int) string {
+	if seat == 1 {
+		return "assets/token/yellow.png"
+	}
+	return "assets/token/red.png"
+}
+
 // updateGameStatus updates the game status message
 func updateGameStatus() {
 	state := lib.Get()
+	lib.DisarmMove()
+	setBoardLocked(state.IsPaused() || !state.IsMyTurn())
 
-	if state.IsMyTurn() {
+	if state.IsPaused() {
+		lib.SetText("game-status", "Paused — opponent offline")
+		lib.SetStyle("game-status", "color", "var(--warning)")
+	} else if state.IsMyTurn() {
 		lib.SetText("game-status", "Your turn - Click a column to play")
 		lib.SetStyle("game-status", "color", "var(--success)")
+		lib.NotifyTurn()
 	} else {
 		lib.SetText("game-status", "Opponent's turn")
 		lib.SetStyle("game-status", "color", "var(--text-secondary)")
 	}
 }
 
+// setBoardLocked dims the board while it does not accept our moves
+func setBoardLocked(locked bool) {
+	lib.ToggleClass("board-container", "board-locked", locked)
+}
+
 // showGameOver displays game over message
-func showGameOver(result int) {
+func showGameOver(result int, drawReason string) {
 	state := lib.Get()
 	playerIdx := state.GetPlayerIdx()
 
@@ -140,12 +235,13 @@ func showGameOver(result int) {
 		}
 	case 3:
 		// Draw
-		message = "Draw"
+		message = formatDraw(drawReason)
 		color = "var(--text-secondary)"
 	}
 
 	lib.SetText("game-status", message)
 	lib.SetStyle("game-status", "color", color)
+	setBoardLocked(false)
 
 	hideGameActions()
 	showReplayArea()
@@ -162,6 +258,9 @@ func updateReplayButton() {
 		return
 	}
 
+	// The seat choice is locked in while our request waits for the opponent
+	lib.SetDisabled("keep-seats", replayRequested)
+
 	switch {
 	case replayRequested && opponentRequested:
 		button.Set("textContent", "Restarting...")
@@ -177,6 +276,12 @@ func updateReplayButton() {
 		lib.AddClass("replay-btn", "btn-success")
 		lib.RemoveClass("replay-btn", "btn-primary")
 
+	case lib.Get().InSeries():
+		button.Set("textContent", "Next round")
+		button.Set("disabled", false)
+		lib.AddClass("replay-btn", "btn-primary")
+		lib.RemoveClass("replay-btn", "btn-success")
+
 	default:
 		button.Set("textContent", "Request Replay")
 		button.Set("disabled", false)
@@ -225,9 +330,37 @@ func resetLobby() {
 	}
 
 	lib.SetValue("join-code-input", "")
+	lib.SetText("waiting-title", "Waiting for opponent...")
 	lib.Get().SetGameCode("")
 
 	lib.Hide("matchmaking-searching")
+	lib.Hide("ready-check")
+	lib.StopGraceCountdown()
+}
+
+// updateSeries shows the score of a best-of series and offers to resign a single round
+func updateSeries() {
+	series := lib.Get().GetSeries()
+	if series.BestOf <= 1 {
+		lib.Hide("series-score")
+		lib.Hide("resign-round-btn")
+		lib.SetText("forfeit-btn", "Forfeit")
+		return
+	}
+
+	// Scores are listed in the order of the player cards
+	first := lib.CardSlot(0)
+	text := fmt.Sprintf("Best of %d: %d - %d", series.BestOf, series.Score[first], series.Score[1-first])
+	switch series.Result {
+	case 1, 2:
+		text += " - " + lib.Get().GetPlayers()[series.Result-1].Username + " wins the match"
+	case 3:
+		text += " - Shared victory"
+	}
+	lib.SetText("series-score", text)
+	lib.Show("series-score")
+	lib.Show("resign-round-btn")
+	lib.SetText("forfeit-btn", "Resign match")
 }
 
 // hideGameCode hides the game code display
@@ -236,9 +369,39 @@ func hideGameCode() {
 }
 
 // showReplayArea shows replay request area
+// Games that cannot be replayed offer a new matchmaking search instead
 func showReplayArea() {
 	lib.Show("replay-area")
-	updateReplayButton()
+	if lib.Get().IsReplayAllowed() {
+		lib.Show("replay-btn")
+		lib.Show("keep-seats-option")
+		lib.Hide("find-game-btn")
+		updateReplayButton()
+	} else {
+		lib.Hide("replay-btn")
+		lib.Hide("keep-seats-option")
+		lib.Show("find-game-btn")
+	}
+
+	if isAutoRematchActive() {
+		lib.Show("auto-rematch-indicator")
+	} else {
+		lib.Hide("auto-rematch-indicator")
+	}
+}
+
+// isAutoRematchActive checks if replays should be requested without clicking
+func isAutoRematchActive() bool {
+	state := lib.Get()
+	return lib.GetSettings().GetAutoRematch() && state.IsReplayAllowed() && !state.IsRematchDeclined()
+}
+
+// autoRequestReplay requests or accepts a replay on behalf of the player
+func autoRequestReplay() {
+	if !isAutoRematchActive() || lib.Get().IsReplayRequested() {
+		return
+	}
+	handleReplay(js.Null(), nil)
 }
 
 // hideReplayArea hides replay request area
@@ -266,6 +429,243 @@ func showWaitingActions() {
 	lib.Hide("game-actions")
 }
 
+// startReplay shows the game screen in review mode for a transcript
+func startReplay(replay *lib.Replay) {
+	lib.Stop()
+	setBoardLocked(false)
+
+	state := lib.Get()
+	state.SetReplay(replay)
+	state.SetGameFinished(true)
+	state.SetPlayerIdx(-1)
+	state.SetPlayers([2]lib.Player{
+		{Username: replay.Transcript.Players[0]},
+		{Username: replay.Transcript.Players[1]},
+	})
+	state.ResetBoard()
+	state.ClearHover()
+
+	updatePlayers()
+	hideReplayArea()
+	hideGameActions()
+	hideWaitingActions()
+	lib.AddClass("game-screen", "replay-mode")
+	lib.ShowFlex("replay-controls")
+	lib.ShowScreen("game")
+	showReplayStep(false)
+}
+
+// showReplayStep renders the current replay position and step counter
+func showReplayStep(animate bool) {
+	state := lib.Get()
+	replay := state.GetReplay()
+	if replay == nil {
+		return
+	}
+
+	step := replay.Current()
+	if step == nil {
+		state.ResetBoard()
+		lib.Draw()
+	} else {
+		state.SetBoard(step.Board)
+		state.SetLastMove(step.Col, step.Row)
+		state.SetMoveNumbers(replay.MoveNumbers(), replay.Cursor())
+		if animate {
+			lib.AnimateDrop(step.Col, step.Row, step.PlayerIdx)
+		} else {
+			lib.Draw()
+		}
+	}
+
+	lib.SetText("replay-step", fmt.Sprintf("%d / %d", replay.Cursor(), replay.Len()))
+	lib.SetStyle("game-status", "color", "var(--text-secondary)")
+
+	switch {
+	case replay.Cursor() == replay.Len() && replay.Transcript.Result == 3:
+		lib.SetText("game-status", "Replay - Draw")
+	case replay.Cursor() == replay.Len() && (replay.Transcript.Result == 1 || replay.Transcript.Result == 2):
+		winner := replay.Transcript.Players[replay.Transcript.Result-1]
+		lib.SetText("game-status", "Replay - "+winner+" won")
+	case step == nil:
+		lib.SetText("game-status", "Replay - Start position")
+	default:
+		name := replay.Transcript.Players[step.PlayerIdx]
+		lib.SetText("game-status", fmt.Sprintf("Replay - Move %d by %s", replay.Cursor(), name))
+	}
+}
+
+// stopReplay leaves review mode and returns to the lobby
+func stopReplay() {
+	state := lib.Get()
+	state.SetReplay(nil)
+	state.SetGameFinished(false)
+	state.ResetBoard()
+
+	lib.RemoveClass("game-screen", "replay-mode")
+	lib.Hide("replay-controls")
+	showGameActions()
+	lib.ShowScreen("lobby")
+}
+
+// startEditor opens the offline board editor on an empty board
+func startEditor() {
+	lib.Stop()
+	setBoardLocked(false)
+
+	state := lib.Get()
+	state.SetEditor(&lib.Position{})
+	state.SetGameFinished(true)
+	state.SetPlayerIdx(-1)
+	state.SetPlayers([2]lib.Player{{Username: "Red"}, {Username: "Yellow"}})
+	state.ResetBoard()
+	state.ClearHover()
+
+	updatePlayers()
+	hideReplayArea()
+	hideGameActions()
+	hideWaitingActions()
+	lib.AddClass("game-screen", "editor-mode")
+	lib.ShowFlex("editor-controls")
+	lib.ShowScreen("game")
+	lib.SetText("game-status", "Click a cell to cycle empty, red and yellow")
+	lib.SetStyle("game-status", "color", "var(--text-secondary)")
+	lib.SetText("editor-side-btn", "Red to move")
+	lib.Draw()
+	lib.UpdateEditorStatus()
+}
+
+// stopEditor closes the board editor and returns to the lobby
+func stopEditor() {
+	state := lib.Get()
+	state.SetEditor(nil)
+	state.SetGameFinished(false)
+	state.ResetBoard()
+
+	lib.RemoveClass("game-screen", "editor-mode")
+	lib.Hide("editor-controls")
+	showGameActions()
+	lib.ShowScreen("lobby")
+}
+
+// showPuzzle renders a puzzle rush position with the player to move
+func showPuzzle(puzzle lib.PuzzleData) {
+	setBoardLocked(false)
+
+	state := lib.Get()
+	state.SetPuzzle(true)
+	state.SetGameFinished(false)
+	state.SetPaused(false)
+	state.SetPlayerIdx(puzzle.PlayerIdx)
+	state.SetCurrentTurn(puzzle.PlayerIdx)
+
+	var players [2]lib.Player
+	players[puzzle.PlayerIdx] = lib.Player{ID: state.GetPlayerID(), Username: lib.GetLocalStorage("username")}
+	players[1-puzzle.PlayerIdx] = lib.Player{Username: "Puzzle"}
+	state.SetPlayers(players)
+	state.SetTimeRemaining([2]int64{puzzle.TimeLimit, puzzle.TimeLimit})
+
+	state.ResetBoard()
+	state.ClearHover()
+	state.SetBoard(puzzle.Board)
+
+	lib.SetText("puzzle-number", fmt.Sprintf("%d", puzzle.Number))
+	lib.SetText("puzzle-score", fmt.Sprintf("%d", puzzle.Score))
+	lib.SetText("puzzle-high-score", fmt.Sprintf("%d", puzzle.HighScore))
+	lib.Hide("puzzle-restart-btn")
+
+	updatePlayers()
+	hideReplayArea()
+	hideGameActions()
+	hideWaitingActions()
+	lib.AddClass("game-screen", "puzzle-mode")
+	lib.ShowFlex("puzzle-controls")
+	lib.ShowScreen("game")
+	lib.Draw()
+
+	moves := "moves"
+	if puzzle.Depth == 1 {
+		moves = "move"
+	}
+	lib.SetText("game-status", fmt.Sprintf("Win in %d %s - Click a column to play", puzzle.Depth, moves))
+	lib.SetStyle("game-status", "color", "var(--success)")
+	lib.Start()
+}
+
+// stopPuzzle leaves puzzle mode and returns to the lobby
+func stopPuzzle() {
+	lib.Stop()
+
+	state := lib.Get()
+	state.SetPuzzle(false)
+	state.SetGameFinished(false)
+	state.ResetBoard()
+
+	lib.RemoveClass("game-screen", "puzzle-mode")
+	lib.Hide("puzzle-controls")
+	showGameActions()
+	lib.ShowScreen("lobby")
+}
+
+// startWatching shows the game screen read-only and follows a game through its event stream
+// The players may be left empty, the first event carries the full game state
+func startWatching(code string, players [2]lib.Player) {
+	lib.Stop()
+	setBoardLocked(false)
+
+	state := lib.Get()
+	state.SetWatching(true)
+	state.SetGameFinished(false)
+	state.SetPaused(false)
+	state.SetPlayerIdx(-1)
+	state.SetPlayers(players)
+	state.ResetBoard()
+	state.ClearHover()
+
+	updatePlayers()
+	hideReplayArea()
+	hideGameActions()
+	hideWaitingActions()
+	lib.AddClass("game-screen", "watch-mode")
+	lib.ShowFlex("watch-controls")
+	lib.ShowScreen("game")
+	lib.SetText("game-status", "Connecting to the game...")
+	lib.SetStyle("game-status", "color", "var(--text-secondary)")
+	lib.Draw()
+
+	lib.Watch(code, handleWatchEvent, handleWatchEnd)
+}
+
+// showWatchStatus tells whose turn it is in the watched game
+func showWatchStatus() {
+	state := lib.Get()
+	name := state.GetPlayers()[state.GetCurrentTurn()].Username
+	lib.SetText("game-status", "Watching - "+name+" to play")
+	lib.SetStyle("game-status", "color", "var(--text-secondary)")
+}
+
+// startOverlay watches a game without logging in, only the board, names, clocks and result are shown
+func startOverlay(code string) {
+	lib.ToggleBodyClass("overlay-mode", true)
+	startWatching(code, [2]lib.Player{})
+}
+
+// stopWatching closes the event stream and returns to the lobby
+func stopWatching() {
+	lib.StopWatching()
+	lib.Stop()
+
+	state := lib.Get()
+	state.SetWatching(false)
+	state.SetGameFinished(false)
+	state.ResetBoard()
+
+	lib.RemoveClass("game-screen", "watch-mode")
+	lib.Hide("watch-controls")
+	showGameActions()
+	lib.ShowScreen("lobby")
+}
+
 // hideWaitingActions hides waiting screen action buttons
 func hideWaitingActions() {
 	lib.Hide("waiting-actions")
@@ -12,6 +12,12 @@ echo ""
 [[ -d "$ROOT_DIR/server" ]] || { echo "Error: $ROOT_DIR/server not found"; exit 1; }
 [[ -d "$ROOT_DIR/client/wasm" ]] || { echo "Error: $ROOT_DIR/client/wasm not found"; exit 1; }
 
+# Build hash shared by the server and the client so stale cached bundles can be detected
+BUILD_HASH="$(git -C "$ROOT_DIR" rev-parse --short HEAD 2>/dev/null || echo dev)"
+LDFLAGS="-X main.buildHash=$BUILD_HASH"
+echo "Build hash: $BUILD_HASH"
+echo ""
+
 # --------------------
 # Build server
 # --------------------
@@ -20,7 +26,7 @@ mkdir -p "$ROOT_DIR/server/dist"
 
 (
   cd "$ROOT_DIR/server"
-  go build -o "./dist/game" .
+  go build -ldflags "$LDFLAGS" -o "./dist/game" .
 )
 
 echo "Server build successful"
@@ -34,7 +40,7 @@ mkdir -p "$ROOT_DIR/client/dist"
 
 (
   cd "$ROOT_DIR/client/wasm"
-  GOOS=js GOARCH=wasm go build -o "../dist/game.wasm" .
+  GOOS=js GOARCH=wasm go build -ldflags "$LDFLAGS" -o "../dist/game.wasm" .
 )
 
 # Copy wasm_exec.js
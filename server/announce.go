@@ -0,0 +1,88 @@
+// Copyright (c) 2025 Haute école d'ingénierie et d'architecture de Fribourg
+// SPDX-License-Identifier: Apache-2.0
+// Author: Marvin Egger marvin.egger@hotmail.ch
+// Created: 16.10.2026
+
+package main
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/marvinEgger/GOnnect4/server/lib"
+)
+
+const (
+	maxAnnouncementLength = 200 // characters
+	announcementInterval  = 10 * time.Second
+	maxAnnouncementBody   = 4 << 10 // bytes
+)
+
+// handleAnnounce lets an operator broadcast a notice to every connected player
+// Requests must carry the admin token as a bearer token, the endpoint is off without one
+func (srv *Server) handleAnnounce(w http.ResponseWriter, r *http.Request) {
+	if srv.adminToken == "" {
+		http.NotFound(w, r)
+		return
+	}
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(srv.adminToken)) != 1 {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var data lib.AnnouncementData
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxAnnouncementBody)).Decode(&data); err != nil {
+		http.Error(w, "invalid announcement", http.StatusBadRequest)
+		return
+	}
+
+	data.Text = strings.TrimSpace(data.Text)
+	if data.Text == "" || utf8.RuneCountInString(data.Text) > maxAnnouncementLength {
+		http.Error(w, "invalid announcement text", http.StatusBadRequest)
+		return
+	}
+	if data.Level == "" {
+		data.Level = lib.AnnouncementInfo
+	}
+	if data.Level != lib.AnnouncementInfo && data.Level != lib.AnnouncementWarning {
+		http.Error(w, "invalid announcement level", http.StatusBadRequest)
+		return
+	}
+
+	if !srv.broadcastAnnouncement(data, time.Now()) {
+		http.Error(w, "too many announcements", http.StatusTooManyRequests)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// broadcastAnnouncement sends a notice to every connected player, in a game or not
+// It reports false when the previous announcement is too recent
+func (srv *Server) broadcastAnnouncement(data lib.AnnouncementData, now time.Time) bool {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+
+	if !srv.lastAnnouncement.IsZero() && now.Sub(srv.lastAnnouncement) < announcementInterval {
+		return false
+	}
+	srv.lastAnnouncement = now
+
+	msg := lib.Message{Type: lib.MsgAnnouncement, Data: data}
+	for _, player := range srv.lobby {
+		if player.IsConnected() {
+			player.Send(msg)
+		}
+	}
+	return true
+}
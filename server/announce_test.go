@@ -0,0 +1,77 @@
+// Copyright (c) 2025 Haute école d'ingénierie et d'architecture de Fribourg
+// SPDX-License-Identifier: Apache-2.0
+// Author: Marvin Egger marvin.egger@hotmail.ch
+// Created: 16.10.2026
+
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/marvinEgger/GOnnect4/server/lib"
+)
+
+// announce posts an announcement with the given bearer token and returns the status code
+func announce(srv *Server, token, body string) int {
+	req := httptest.NewRequest(http.MethodPost, "/admin/announce", strings.NewReader(body))
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	recorder := httptest.NewRecorder()
+	srv.handleAnnounce(recorder, req)
+	return recorder.Code
+}
+
+// TestHandleAnnounce_ReachesPlayersInGame tests that announcements reach lobby and in-game players alike
+func TestHandleAnnounce_ReachesPlayersInGame(t *testing.T) {
+	srv := NewServer()
+	defer srv.cancelFunc()
+	srv.adminToken = "secret"
+
+	mover, _ := startTestGame(srv)
+	carol := loginTestPlayer(srv, "Carol")
+
+	if code := announce(srv, "secret", `{"text":"Restarting in 5 minutes","level":"warning"}`); code != http.StatusNoContent {
+		t.Fatalf("Expected 204, got %d", code)
+	}
+
+	for _, client := range []*lib.Client{mover, carol} {
+		if !hasMessage(drainMessages(client), lib.MsgAnnouncement) {
+			t.Errorf("Expected %s to receive the announcement", client.PlayerID)
+		}
+	}
+}
+
+// TestHandleAnnounce_Guards tests authentication, validation and rate limiting
+func TestHandleAnnounce_Guards(t *testing.T) {
+	srv := NewServer()
+	defer srv.cancelFunc()
+
+	if code := announce(srv, "", `{"text":"hi"}`); code != http.StatusNotFound {
+		t.Errorf("Expected the endpoint to be off without a token, got %d", code)
+	}
+
+	srv.adminToken = "secret"
+	tests := []struct {
+		name  string
+		token string
+		body  string
+		code  int
+	}{
+		{"wrong token", "guess", `{"text":"hi"}`, http.StatusUnauthorized},
+		{"empty text", "secret", `{"text":"  "}`, http.StatusBadRequest},
+		{"too long", "secret", `{"text":"` + strings.Repeat("a", maxAnnouncementLength+1) + `"}`, http.StatusBadRequest},
+		{"unknown level", "secret", `{"text":"hi","level":"panic"}`, http.StatusBadRequest},
+		{"accepted", "secret", `{"text":"hi"}`, http.StatusNoContent},
+		{"too soon", "secret", `{"text":"again"}`, http.StatusTooManyRequests},
+	}
+
+	for _, tt := range tests {
+		if code := announce(srv, tt.token, tt.body); code != tt.code {
+			t.Errorf("%s: expected %d, got %d", tt.name, tt.code, code)
+		}
+	}
+}
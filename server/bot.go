@@ -0,0 +1,62 @@
+// Copyright (c) 2025 Haute école d'ingénierie et d'architecture de Fribourg
+// SPDX-License-Identifier: Apache-2.0
+// Author: Marvin Egger marvin.egger@hotmail.ch
+// Created: 16.10.2026
+
+package main
+
+import "github.com/marvinEgger/GOnnect4/server/lib"
+
+// scheduleBotTurn has the bot play when it is the side to move, games without a bot are left alone
+func (srv *Server) scheduleBotTurn(game *lib.Game) {
+	if game.GetStatus() != lib.StatusPlaying || game.IsPaused() {
+		return
+	}
+
+	botIdx := game.CurrentTurn
+	player := game.GetPlayers()[botIdx]
+	if player == nil || player.Bot == nil {
+		return
+	}
+
+	// Only the bot can move until it replies, so a copy taken now is the position it answers
+	bot := player.Bot
+	board := game.Board.Clone()
+	me := lib.Cell(int(lib.CellPlayer0) + botIdx)
+	srv.scheduleBotReply(game, botIdx, bot.Depth, func() int {
+		return bot.ChooseMove(board, me)
+	}, func(column int) {
+		if err := game.Play(botIdx, column); err != nil {
+			// The game was paused meanwhile, the reply is scheduled again once it resumes
+			return
+		}
+		srv.broadcastMove(game, botIdx, column)
+	})
+}
+
+// scheduleBotReply shows the bot side as thinking and plays the column found by search after a depth-scaled delay
+// The search runs before the server lock is taken so deep bots never stall other games
+// The reply is dropped if the game ends before the delay is over
+func (srv *Server) scheduleBotReply(game *lib.Game, botIdx, depth int, search func() int, reply func(column int)) {
+	srv.broadcastThinking(game, botIdx, true)
+
+	game.ScheduleMove(lib.BotThinkDelay(depth), func() {
+		column := search()
+
+		srv.mu.Lock()
+		defer srv.mu.Unlock()
+
+		srv.broadcastThinking(game, botIdx, false)
+		if game.GetStatus() == lib.StatusPlaying {
+			reply(column)
+		}
+	})
+}
+
+// broadcastThinking updates the thinking indicator on the bot's player card
+func (srv *Server) broadcastThinking(game *lib.Game, botIdx int, thinking bool) {
+	srv.broadcastToGame(game, lib.Message{
+		Type: lib.MsgOpponentThinking,
+		Data: lib.ThinkingData{PlayerIdx: botIdx, Thinking: thinking},
+	})
+}
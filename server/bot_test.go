@@ -0,0 +1,99 @@
+// Copyright (c) 2025 Haute école d'ingénierie et d'architecture de Fribourg
+// SPDX-License-Identifier: Apache-2.0
+// Author: Marvin Egger marvin.egger@hotmail.ch
+// Created: 16.10.2026
+
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/marvinEgger/GOnnect4/server/lib"
+)
+
+// waitForBotMove waits for the bot's next move to reach a client
+func waitForBotMove(t *testing.T, client *lib.Client) lib.MoveData {
+	t.Helper()
+	timeout := time.After(2 * time.Second)
+	for {
+		select {
+		case msg := <-client.SendChan:
+			if move, ok := msg.Data.(lib.MoveData); ok && msg.Type == lib.MsgMove && move.PlayerIdx == 1 {
+				return move
+			}
+		case <-timeout:
+			t.Fatal("The bot did not reply")
+		}
+	}
+}
+
+// TestHandlePlayBot_Replies tests that the bot answers every move of the human
+func TestHandlePlayBot_Replies(t *testing.T) {
+	srv := NewServer()
+	defer srv.cancelFunc()
+
+	alice := loginTestPlayer(srv, "Alice")
+	srv.handlePlayBot(alice, lib.PlayBotData{Depth: 1})
+	var start lib.GameStartData
+	found := false
+	for _, msg := range drainMessages(alice) {
+		if msg.Type == lib.MsgGameStart {
+			start, found = msg.Data.(lib.GameStartData), true
+		}
+	}
+	if !found {
+		t.Fatal("Expected the bot game to start right away")
+	}
+
+	game := srv.findGameForClient(alice)
+	defer game.Cleanup()
+	if game.GetPlayers()[1].Bot == nil {
+		t.Fatal("Expected the bot in the second seat")
+	}
+	if start.Players[0].IsBot || !start.Players[1].IsBot {
+		t.Errorf("Only the bot seat should be flagged, got %+v", start.Players)
+	}
+	if !game.Record(time.Now()).VsBot {
+		t.Error("The record of a bot game should say so")
+	}
+	if remaining := time.Duration(start.TimeRemaining[1]) * time.Millisecond; remaining < lib.BotClock-time.Second {
+		t.Errorf("Expected the bot clock to start near %v, got %v", lib.BotClock, remaining)
+	}
+
+	// The bot may have the first move
+	if start.CurrentTurn == 1 {
+		waitForBotMove(t, alice)
+	}
+
+	for i := 0; i < 2; i++ {
+		srv.handlePlay(alice, lib.PlayData{Column: 0})
+		waitForBotMove(t, alice)
+	}
+	if game.GetStatus() != lib.StatusPlaying || game.GetMoveCount()%2 != start.CurrentTurn {
+		t.Errorf("Expected the human to move next, got status %v after %d moves", game.GetStatus(), game.GetMoveCount())
+	}
+}
+
+// TestHandlePlayBot_NotJoinable tests that nobody else can take a seat in a bot game
+func TestHandlePlayBot_NotJoinable(t *testing.T) {
+	srv := NewServer()
+	defer srv.cancelFunc()
+
+	alice := loginTestPlayer(srv, "Alice")
+	srv.handlePlayBot(alice, lib.PlayBotData{})
+	game := srv.findGameForClient(alice)
+	defer game.Cleanup()
+
+	bob := loginTestPlayer(srv, "Bob")
+	srv.handleJoinGame(bob, lib.JoinGameData{Code: game.Code})
+	if !hasError(drainMessages(bob), lib.ErrPrivateGame) {
+		t.Error("A bot game should not be joinable by code")
+	}
+
+	// One active game at a time, bot games included
+	srv.handlePlayBot(alice, lib.PlayBotData{})
+	if !hasError(drainMessages(alice), lib.ErrPlayerAlreadyInGame) {
+		t.Error("A player already in a game should not start a bot game")
+	}
+}
@@ -0,0 +1,64 @@
+// Copyright (c) 2025 Haute école d'ingénierie et d'architecture de Fribourg
+// SPDX-License-Identifier: Apache-2.0
+// Author: Marvin Egger marvin.egger@hotmail.ch
+// Created: 16.10.2026
+
+package main
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/marvinEgger/GOnnect4/server/lib"
+)
+
+const maxChatLength = 200 // characters
+
+// handleLobbyChat broadcasts a chat message to every player not in an active game
+func (srv *Server) handleLobbyChat(client *lib.Client, data lib.LobbyChatData) {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+
+	if !srv.chatEnabled {
+		srv.sendError(client, lib.ErrChatDisabled)
+		return
+	}
+
+	player := srv.lobby[client.PlayerID]
+	if player == nil {
+		srv.sendError(client, lib.ErrPlayerNotFound)
+		return
+	}
+
+	// Players in a game use the game screen, not the lobby
+	if srv.activeGameFor(player.ID) != nil {
+		srv.sendError(client, lib.ErrPlayerAlreadyInGame)
+		return
+	}
+
+	text := strings.TrimSpace(data.Text)
+	if text == "" || utf8.RuneCountInString(text) > maxChatLength {
+		srv.sendError(client, lib.ErrInvalidChat)
+		return
+	}
+
+	if !client.AllowChat() {
+		srv.sendError(client, lib.ErrChatTooFast)
+		return
+	}
+
+	msg := lib.Message{
+		Type: lib.MsgLobbyChat,
+		Data: lib.LobbyChatMessageData{
+			Tag:      player.Tag(),
+			Username: player.Username,
+			Text:     text,
+		},
+	}
+
+	for id, p := range srv.lobby {
+		if p.IsConnected() && srv.activeGameFor(id) == nil {
+			p.Send(msg)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+// Copyright (c) 2025 Haute école d'ingénierie et d'architecture de Fribourg
+// SPDX-License-Identifier: Apache-2.0
+// Author: Marvin Egger marvin.egger@hotmail.ch
+// Created: 16.10.2026
+
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/marvinEgger/GOnnect4/server/lib"
+)
+
+// TestHandleLobbyChat_SkipsPlayersInGame tests that only lobby players receive lobby chat
+func TestHandleLobbyChat_SkipsPlayersInGame(t *testing.T) {
+	srv := NewServer()
+	defer srv.cancelFunc()
+
+	alice := loginTestPlayer(srv, "Alice")
+	bob := loginTestPlayer(srv, "Bob")
+	carol := loginTestPlayer(srv, "Carol")
+	srv.handleCreateGame(carol, lib.CreateGameData{})
+	drainMessages(carol)
+
+	srv.handleLobbyChat(alice, lib.LobbyChatData{Text: "  anyone up for a game?  "})
+
+	msgs := drainMessages(bob)
+	if len(msgs) != 1 || msgs[0].Type != lib.MsgLobbyChat {
+		t.Fatalf("Lobby player should receive the chat message, got %v", msgs)
+	}
+	data := msgs[0].Data.(lib.LobbyChatMessageData)
+	if data.Text != "anyone up for a game?" || data.Username != "Alice" || data.Tag == "" {
+		t.Errorf("Unexpected chat message %+v", data)
+	}
+
+	if hasMessage(drainMessages(carol), lib.MsgLobbyChat) {
+		t.Error("Player in a game should not receive lobby chat")
+	}
+}
+
+// TestHandleLobbyChat_Validation tests length and rate limits
+func TestHandleLobbyChat_Validation(t *testing.T) {
+	srv := NewServer()
+	defer srv.cancelFunc()
+
+	alice := loginTestPlayer(srv, "Alice")
+
+	srv.handleLobbyChat(alice, lib.LobbyChatData{Text: strings.Repeat("a", maxChatLength+1)})
+	if !hasError(drainMessages(alice), lib.ErrInvalidChat) {
+		t.Error("Too long message should be rejected")
+	}
+
+	srv.handleLobbyChat(alice, lib.LobbyChatData{Text: "hello"})
+	srv.handleLobbyChat(alice, lib.LobbyChatData{Text: "hello again"})
+	if !hasError(drainMessages(alice), lib.ErrChatTooFast) {
+		t.Error("Second message within the interval should be rate limited")
+	}
+}
@@ -0,0 +1,84 @@
+// Copyright (c) 2025 Haute école d'ingénierie et d'architecture de Fribourg
+// SPDX-License-Identifier: Apache-2.0
+// Author: Marvin Egger marvin.egger@hotmail.ch
+// Created: 16.10.2026
+
+package main
+
+import "github.com/marvinEgger/GOnnect4/server/lib"
+
+// handleDrawOffer proposes a draw to the opponent, crossing offers end the game at once
+func (srv *Server) handleDrawOffer(client *lib.Client) {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+
+	game := srv.findGameForClient(client)
+	if game == nil {
+		srv.sendError(client, lib.ErrGameNotFound)
+		return
+	}
+
+	playerIdx := game.GetPlayerIndex(client.PlayerID)
+	if playerIdx < 0 {
+		srv.sendError(client, lib.ErrPlayerNotInGame)
+		return
+	}
+
+	// A bot never answers
+	if opponent := game.GetPlayers()[1-playerIdx]; opponent != nil && opponent.Bot != nil {
+		srv.sendError(client, lib.ErrDrawNotAllowed)
+		return
+	}
+
+	agreed, err := game.OfferDraw(playerIdx)
+	if err != nil {
+		srv.sendError(client, err)
+		return
+	}
+	if agreed {
+		srv.announceGameOver(game)
+		return
+	}
+
+	srv.broadcastToGame(game, lib.Message{
+		Type: lib.MsgDrawOffer,
+		Data: lib.DrawOfferData{PlayerIdx: playerIdx},
+	})
+}
+
+// handleDrawResponse ends the game as a draw or drops the offer of the opponent
+func (srv *Server) handleDrawResponse(client *lib.Client, data lib.DrawResponseData) {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+
+	game := srv.findGameForClient(client)
+	if game == nil {
+		srv.sendError(client, lib.ErrGameNotFound)
+		return
+	}
+
+	playerIdx := game.GetPlayerIndex(client.PlayerID)
+	if playerIdx < 0 {
+		srv.sendError(client, lib.ErrPlayerNotInGame)
+		return
+	}
+
+	var err error
+	if data.Accept {
+		err = game.AcceptDraw(playerIdx)
+	} else {
+		err = game.DeclineDraw(playerIdx)
+	}
+	if err != nil {
+		srv.sendError(client, err)
+		return
+	}
+
+	srv.broadcastToGame(game, lib.Message{
+		Type: lib.MsgDrawResponse,
+		Data: lib.DrawResponseData{Accept: data.Accept, PlayerIdx: playerIdx},
+	})
+	if data.Accept {
+		srv.announceGameOver(game)
+	}
+}
@@ -0,0 +1,48 @@
+// Copyright (c) 2025 Haute école d'ingénierie et d'architecture de Fribourg
+// SPDX-License-Identifier: Apache-2.0
+// Author: Marvin Egger marvin.egger@hotmail.ch
+// Created: 16.10.2026
+
+package main
+
+import (
+	"testing"
+
+	"github.com/marvinEgger/GOnnect4/server/lib"
+)
+
+// TestHandleDraw_AgreedDraw tests that both players see the offer, the answer and the game over
+func TestHandleDraw_AgreedDraw(t *testing.T) {
+	srv := NewServer()
+	defer srv.cancelFunc()
+
+	alice := loginTestPlayer(srv, "Alice")
+	bob := loginTestPlayer(srv, "Bob")
+	srv.handleCreateGame(alice, lib.CreateGameData{})
+	srv.handleJoinGame(bob, lib.JoinGameData{Code: alice.GameCode})
+	game := srv.findGameForClient(alice)
+	defer game.Cleanup()
+	drainMessages(alice)
+	drainMessages(bob)
+
+	srv.handleDrawOffer(alice)
+	if !hasMessage(drainMessages(bob), lib.MsgDrawOffer) {
+		t.Fatal("The opponent should be offered the draw")
+	}
+
+	srv.handleDrawResponse(bob, lib.DrawResponseData{Accept: true})
+	var over *lib.GameOverData
+	for _, msg := range drainMessages(alice) {
+		if data, ok := msg.Data.(lib.GameOverData); ok {
+			over = &data
+		}
+	}
+	if over == nil || over.Result != lib.ResultDraw || over.DrawReason != lib.DrawAgreement {
+		t.Errorf("Expected a game over by agreement, got %+v", over)
+	}
+
+	srv.handleDrawResponse(bob, lib.DrawResponseData{Accept: true})
+	if !hasError(drainMessages(bob), lib.ErrGameNotPlaying) {
+		t.Error("Expected ErrGameNotPlaying once the game is over")
+	}
+}
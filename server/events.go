@@ -0,0 +1,175 @@
+// Copyright (c) 2025 Haute école d'ingénierie et d'architecture de Fribourg
+// SPDX-License-Identifier: Apache-2.0
+// Author: Marvin Egger marvin.egger@hotmail.ch
+// Created: 16.10.2026
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/marvinEgger/GOnnect4/server/lib"
+)
+
+const (
+	maxEventSubscribers = 20 // Event streams open on a single game
+	eventBufferSize     = 16 // Messages held for a slow stream before new ones are dropped
+)
+
+// eventSubscriber is a read-only stream of a game's moves and results
+type eventSubscriber struct {
+	feed     *lib.DelayedFeed // Holds messages back by the game's spectator delay
+	messages chan lib.Message
+	done     chan struct{} // Closed when the game is cleaned up
+	closing  sync.Once
+}
+
+// close ends the stream, safe to call more than once
+func (sub *eventSubscriber) close() {
+	sub.closing.Do(func() {
+		sub.feed.Stop()
+		close(sub.done)
+	})
+}
+
+// handleGameEvents streams the updates of a game as Server-Sent Events
+func (srv *Server) handleGameEvents(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	code := strings.ToUpper(r.PathValue("code"))
+	sub, err := srv.subscribeEvents(code)
+	switch err {
+	case nil:
+	case lib.ErrGameNotFound:
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	case lib.ErrPrivateSpectate:
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	default:
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+	defer srv.unsubscribeEvents(code, sub)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-sub.done:
+			return
+		case msg := <-sub.messages:
+			if err := writeEvent(w, msg); err != nil {
+				log.Printf("Failed to write event for game %s: %v", code, err)
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+// writeEvent writes a message as one Server-Sent Event named after its type
+func writeEvent(w http.ResponseWriter, msg lib.Message) error {
+	data, err := json.Marshal(msg.Data)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", msg.Type, data)
+	return err
+}
+
+// subscribeEvents registers a stream on a game and queues its current state as the first event
+func (srv *Server) subscribeEvents(code string) (*eventSubscriber, error) {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+
+	game := srv.gamesByCode[code]
+	if game == nil {
+		return nil, lib.ErrGameNotFound
+	}
+	if !game.Watchable() {
+		return nil, lib.ErrPrivateSpectate
+	}
+	if len(srv.eventSubs[code]) >= maxEventSubscribers {
+		return nil, lib.ErrTooManySubscribers
+	}
+
+	sub := &eventSubscriber{
+		messages: make(chan lib.Message, eventBufferSize),
+		done:     make(chan struct{}),
+	}
+	sub.feed = lib.NewDelayedFeed(game.SpectatorDelay, func(msg lib.Message) {
+		// A stream too slow to keep up misses updates rather than blocking the game
+		select {
+		case sub.messages <- msg:
+		default:
+		}
+	})
+
+	if srv.eventSubs[code] == nil {
+		srv.eventSubs[code] = make(map[*eventSubscriber]struct{})
+	}
+	srv.eventSubs[code][sub] = struct{}{}
+
+	sub.feed.Push(lib.Message{Type: lib.MsgGameState, Data: srv.buildObserverState(game)})
+	return sub, nil
+}
+
+// buildObserverState is the game state seen from outside the game, player IDs stay private
+func (srv *Server) buildObserverState(game *lib.Game) lib.GameStateData {
+	state := srv.buildGameState(game, "")
+	for i := range state.Players {
+		state.Players[i].ID = ""
+	}
+	return state
+}
+
+// unsubscribeEvents removes a stream whose client went away
+func (srv *Server) unsubscribeEvents(code string, sub *eventSubscriber) {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+
+	sub.close()
+	if subs := srv.eventSubs[code]; subs != nil {
+		delete(subs, sub)
+		if len(subs) == 0 {
+			delete(srv.eventSubs, code)
+		}
+	}
+}
+
+// publishGameEvent sends a message to the event streams of a game
+func (srv *Server) publishGameEvent(game *lib.Game, msg lib.Message) {
+	for sub := range srv.eventSubs[game.Code] {
+		sub.feed.Push(msg)
+	}
+}
+
+// closeGameEvents ends the event streams of a game being cleaned up
+func (srv *Server) closeGameEvents(code string) {
+	for sub := range srv.eventSubs[code] {
+		sub.close()
+	}
+	delete(srv.eventSubs, code)
+}
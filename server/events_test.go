@@ -0,0 +1,158 @@
+// Copyright (c) 2025 Haute école d'ingénierie et d'architecture de Fribourg
+// SPDX-License-Identifier: Apache-2.0
+// Author: Marvin Egger marvin.egger@hotmail.ch
+// Created: 16.10.2026
+
+package main
+
+import (
+	"bufio"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/marvinEgger/GOnnect4/server/lib"
+)
+
+// newEventServer serves the event stream route of srv
+func newEventServer(srv *Server) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/game/{code}/events", srv.handleGameEvents)
+	return httptest.NewServer(mux)
+}
+
+// nextEvent reads lines until the next event name, failing after a timeout
+func nextEvent(t *testing.T, lines <-chan string) string {
+	t.Helper()
+	for {
+		select {
+		case line, ok := <-lines:
+			if !ok {
+				return ""
+			}
+			if name, found := strings.CutPrefix(line, "event: "); found {
+				return name
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatal("Timed out waiting for an event")
+		}
+	}
+}
+
+// streamLines reads a response body line by line, the channel closes at the end of the stream
+func streamLines(body io.Reader) <-chan string {
+	lines := make(chan string)
+	go func() {
+		defer close(lines)
+		scanner := bufio.NewScanner(body)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+	}()
+	return lines
+}
+
+// TestGameEvents_StreamsMoves tests that a stream gets the state, then each move, and ends with the game
+func TestGameEvents_StreamsMoves(t *testing.T) {
+	srv := NewServer()
+	defer srv.cancelFunc()
+	httpSrv := newEventServer(srv)
+	defer httpSrv.Close()
+
+	mover, game := startTestGame(srv)
+	resp, err := http.Get(httpSrv.URL + "/game/" + strings.ToLower(game.Code) + "/events")
+	if err != nil {
+		t.Fatalf("Failed to open stream: %v", err)
+	}
+	defer resp.Body.Close()
+	if got := resp.Header.Get("Content-Type"); got != "text/event-stream" {
+		t.Fatalf("Expected an event stream, got %q", got)
+	}
+
+	lines := streamLines(resp.Body)
+	if name := nextEvent(t, lines); name != string(lib.MsgGameState) {
+		t.Fatalf("Expected the game state first, got %q", name)
+	}
+	if data := <-lines; strings.Contains(data, string(mover.PlayerID)) {
+		t.Error("The stream should not reveal player IDs")
+	}
+
+	srv.handlePlay(mover, lib.PlayData{Column: 3})
+	if name := nextEvent(t, lines); name != string(lib.MsgMove) {
+		t.Fatalf("Expected a move event, got %q", name)
+	}
+
+	srv.mu.Lock()
+	srv.closeGameEvents(game.Code)
+	srv.mu.Unlock()
+	if name := nextEvent(t, lines); name != "" {
+		t.Errorf("Expected the stream to end with the game, got %q", name)
+	}
+}
+
+// TestGameEvents_Rejects tests unknown games and the subscriber limit
+func TestGameEvents_Rejects(t *testing.T) {
+	srv := NewServer()
+	defer srv.cancelFunc()
+	httpSrv := newEventServer(srv)
+	defer httpSrv.Close()
+
+	resp, err := http.Get(httpSrv.URL + "/game/ZZZZZ/events")
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected 404 for an unknown game, got %d", resp.StatusCode)
+	}
+
+	_, game := startTestGame(srv)
+	for i := 0; i < maxEventSubscribers; i++ {
+		if _, err := srv.subscribeEvents(game.Code); err != nil {
+			t.Fatalf("Subscriber %d rejected: %v", i, err)
+		}
+	}
+
+	resp, err = http.Get(httpSrv.URL + "/game/" + game.Code + "/events")
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("Expected 503 past the subscriber limit, got %d", resp.StatusCode)
+	}
+}
+
+// TestGameEvents_RejectsPrivate tests that games closed to spectators cannot be followed either
+func TestGameEvents_RejectsPrivate(t *testing.T) {
+	srv := NewServer()
+	defer srv.cancelFunc()
+	httpSrv := newEventServer(srv)
+	defer httpSrv.Close()
+
+	alice := loginTestPlayer(srv, "Alice")
+	bob := loginTestPlayer(srv, "Bob")
+	matchTestPlayers(srv, alice, bob)
+	ranked := srv.findGameForClient(alice)
+	if ranked == nil {
+		t.Fatal("Players should have been matched")
+	}
+
+	resp, err := http.Get(httpSrv.URL + "/game/" + ranked.Code + "/events")
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusForbidden {
+		t.Errorf("Expected 403 for a matchmaking game, got %d", resp.StatusCode)
+	}
+
+	carol := loginTestPlayer(srv, "Carol")
+	srv.handleCreateGame(carol, lib.CreateGameData{Invite: "Dave"})
+	if _, err := srv.subscribeEvents(carol.GameCode); err != lib.ErrPrivateSpectate {
+		t.Errorf("Expected ErrPrivateSpectate for an invite-only game, got %v", err)
+	}
+}
@@ -0,0 +1,109 @@
+// Copyright (c) 2025 Haute école d'ingénierie et d'architecture de Fribourg
+// SPDX-License-Identifier: Apache-2.0
+// Author: Marvin Egger marvin.egger@hotmail.ch
+// Created: 16.10.2026
+
+package main
+
+import (
+	"log"
+	"os"
+
+	"github.com/marvinEgger/GOnnect4/server/lib"
+)
+
+// featuredCriterion decides which game in progress the lobby is pointed to
+type featuredCriterion string
+
+const (
+	featuredMostMoves featuredCriterion = "moves"  // The game with the most moves played this round
+	featuredOldest    featuredCriterion = "oldest" // The game created the longest time ago
+
+	featuredEnv = "GONNECT4_FEATURED"
+)
+
+// loadFeaturedCriterion reads how the featured game is picked, most moves by default
+func loadFeaturedCriterion() featuredCriterion {
+	value := featuredCriterion(os.Getenv(featuredEnv))
+	switch value {
+	case "":
+		return featuredMostMoves
+	case featuredMostMoves, featuredOldest:
+		return value
+	default:
+		log.Printf("Ignoring invalid %s %q", featuredEnv, value)
+		return featuredMostMoves
+	}
+}
+
+// isFeaturable checks if a game may be shown to the lobby
+// Only games anyone may spectate are picked, the lobby watches them over their event stream
+func isFeaturable(game *lib.Game) bool {
+	return game.GetStatus() == lib.StatusPlaying && game.Watchable()
+}
+
+// ranksAbove checks if a game is a better pick than the current best under a criterion
+// Ties go to the smaller code so the pick does not flip between refreshes
+func (c featuredCriterion) ranksAbove(game, best *lib.Game) bool {
+	if best == nil {
+		return true
+	}
+
+	switch c {
+	case featuredOldest:
+		if !game.CreatedAt.Equal(best.CreatedAt) {
+			return game.CreatedAt.Before(best.CreatedAt)
+		}
+	default:
+		if moves, bestMoves := game.GetMoveCount(), best.GetMoveCount(); moves != bestMoves {
+			return moves > bestMoves
+		}
+	}
+	return game.Code < best.Code
+}
+
+// refreshFeatured picks the featured game again, expects srv.mu to be held
+func (srv *Server) refreshFeatured() {
+	var best *lib.Game
+	for _, game := range srv.gamesByCode {
+		if isFeaturable(game) && srv.featuredCriterion.ranksAbove(game, best) {
+			best = game
+		}
+	}
+
+	srv.featuredCode = ""
+	if best != nil {
+		srv.featuredCode = best.Code
+	}
+}
+
+// handleSpectateFeatured tells a lobby player which game to watch
+// Watching goes through the read-only event stream, the players never notice it
+func (srv *Server) handleSpectateFeatured(client *lib.Client) {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+
+	// The pick may have ended since the last refresh
+	if game := srv.gamesByCode[srv.featuredCode]; game == nil || !isFeaturable(game) {
+		srv.refreshFeatured()
+	}
+
+	game := srv.gamesByCode[srv.featuredCode]
+	if game == nil {
+		srv.sendError(client, lib.ErrNoFeaturedGame)
+		return
+	}
+
+	players := srv.getPlayerInfos(game)
+	for i := range players {
+		players[i].ID = ""
+	}
+	client.Send(lib.Message{
+		Type: lib.MsgFeaturedGame,
+		Data: lib.FeaturedGameData{
+			Code:      game.Code,
+			Players:   players,
+			MoveCount: game.GetMoveCount(),
+		},
+	})
+}
@@ -0,0 +1,122 @@
+// Copyright (c) 2025 Haute école d'ingénierie et d'architecture de Fribourg
+// SPDX-License-Identifier: Apache-2.0
+// Author: Marvin Egger marvin.egger@hotmail.ch
+// Created: 16.10.2026
+
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/marvinEgger/GOnnect4/server/lib"
+)
+
+// featuredData returns the featured game sent to a client, failing if there is none
+func featuredData(t *testing.T, client *lib.Client) lib.FeaturedGameData {
+	t.Helper()
+	for _, msg := range drainMessages(client) {
+		if data, ok := msg.Data.(lib.FeaturedGameData); ok && msg.Type == lib.MsgFeaturedGame {
+			return data
+		}
+	}
+	t.Fatal("Expected a featured game")
+	return lib.FeaturedGameData{}
+}
+
+// TestHandleSpectateFeatured_NoGame tests that an empty server has nothing to feature
+func TestHandleSpectateFeatured_NoGame(t *testing.T) {
+	srv := NewServer()
+	defer srv.cancelFunc()
+
+	viewer := loginTestPlayer(srv, "Carol")
+	srv.handleSpectateFeatured(viewer)
+	if msgs := drainMessages(viewer); !hasError(msgs, lib.ErrNoFeaturedGame) {
+		t.Errorf("Expected ErrNoFeaturedGame, got %v", msgs)
+	}
+}
+
+// TestHandleSpectateFeatured_MostMoves tests that the busiest game is featured without revealing player IDs
+func TestHandleSpectateFeatured_MostMoves(t *testing.T) {
+	srv := NewServer()
+	defer srv.cancelFunc()
+
+	startTestGame(srv)
+	mover, busy := startTestGame(srv)
+	srv.handlePlay(mover, lib.PlayData{Column: 3})
+
+	viewer := loginTestPlayer(srv, "Carol")
+	srv.handleSpectateFeatured(viewer)
+	data := featuredData(t, viewer)
+	if data.Code != busy.Code {
+		t.Errorf("Expected game %s to be featured, got %s", busy.Code, data.Code)
+	}
+	if data.MoveCount != 1 {
+		t.Errorf("Expected 1 move, got %d", data.MoveCount)
+	}
+	for _, player := range data.Players {
+		if player.ID != "" {
+			t.Error("The featured game should not reveal player IDs")
+		}
+	}
+}
+
+// TestHandleSpectateFeatured_SkipsInvites tests that a game reserved for an invited opponent stays private
+func TestHandleSpectateFeatured_SkipsInvites(t *testing.T) {
+	srv := NewServer()
+	defer srv.cancelFunc()
+
+	_, game := startTestGame(srv)
+	game.Invite = "Bob"
+
+	viewer := loginTestPlayer(srv, "Carol")
+	srv.handleSpectateFeatured(viewer)
+	if msgs := drainMessages(viewer); !hasError(msgs, lib.ErrNoFeaturedGame) {
+		t.Errorf("Expected ErrNoFeaturedGame, got %v", msgs)
+	}
+}
+
+// TestHandleSpectateFeatured_SkipsPrivate tests that matchmaking games are never featured, however busy
+func TestHandleSpectateFeatured_SkipsPrivate(t *testing.T) {
+	srv := NewServer()
+	defer srv.cancelFunc()
+
+	alice := loginTestPlayer(srv, "Alice")
+	bob := loginTestPlayer(srv, "Bob")
+	matchTestPlayers(srv, alice, bob)
+	ranked := srv.findGameForClient(alice)
+	if ranked == nil {
+		t.Fatal("Players should have been matched")
+	}
+
+	viewer := loginTestPlayer(srv, "Carol")
+	srv.handleSpectateFeatured(viewer)
+	if msgs := drainMessages(viewer); !hasError(msgs, lib.ErrNoFeaturedGame) {
+		t.Errorf("Expected ErrNoFeaturedGame, got %v", msgs)
+	}
+
+	// A public game is picked over it
+	_, public := startTestGame(srv)
+	srv.handleSpectateFeatured(viewer)
+	if data := featuredData(t, viewer); data.Code != public.Code {
+		t.Errorf("Expected the public game %s, got %s", public.Code, data.Code)
+	}
+}
+
+// TestFeaturedCriterion_Oldest tests that the oldest criterion ignores the move count
+func TestFeaturedCriterion_Oldest(t *testing.T) {
+	srv := NewServer()
+	defer srv.cancelFunc()
+	srv.featuredCriterion = featuredOldest
+
+	_, old := startTestGame(srv)
+	mover, _ := startTestGame(srv)
+	srv.handlePlay(mover, lib.PlayData{Column: 3})
+	old.CreatedAt = old.CreatedAt.Add(-time.Minute)
+
+	viewer := loginTestPlayer(srv, "Carol")
+	srv.handleSpectateFeatured(viewer)
+	if data := featuredData(t, viewer); data.Code != old.Code {
+		t.Errorf("Expected game %s to be featured, got %s", old.Code, data.Code)
+	}
+}
@@ -6,35 +6,57 @@
 package main
 
 import (
+	"log"
 	"strings"
+	"time"
 
 	"github.com/marvinEgger/GOnnect4/server/lib"
 )
 
 const maxGameCodeLength = 5
 
+// displaceConnection detaches a connection replaced by a newer login and tells it why it went quiet
+func (srv *Server) displaceConnection(previous lib.Sender) {
+	// Its disconnect cleanup must not touch the player now owned by the new connection
+	if old, ok := previous.(*lib.Client); ok {
+		old.PlayerID = ""
+		old.GameCode = ""
+	}
+	previous.Send(lib.Message{Type: lib.MsgSessionTaken})
+}
+
 // handleLogin processes login / reconnection
 func (srv *Server) handleLogin(client *lib.Client, data lib.LoginData) {
 	srv.mu.Lock()
 	defer srv.mu.Unlock()
 
+	client.SetFlatBoard(data.FlatBoard)
+
 	var player *lib.Player
 	var game *lib.Game
 
-	// Reconnection attempt
-	if data.PlayerID != nil {
-		if p, exists := srv.lobby[*data.PlayerID]; exists {
+	// Reconnection attempt, an invalid or expired token falls back to a fresh login
+	if data.ResumeToken != "" {
+		id, err := lib.VerifyResumeToken(data.ResumeToken, srv.resumeSecret, time.Now())
+		if err != nil {
+			log.Printf("Rejected resume token: %v", err)
+		} else if p, exists := srv.lobby[id]; exists {
 			player = p
 			player.Username = data.Username
 
-			// Check if player is in a game
-			for code, g := range srv.gamesByCode {
-				if g.HasPlayer(player.ID) {
-					game = g
-					client.GameCode = code
-					break
+			// Check if player is in a game, preferring the active one over finished games
+			game = srv.activeGameFor(player.ID)
+			if game == nil {
+				for _, g := range srv.gamesByCode {
+					if g.HasPlayer(player.ID) {
+						game = g
+						break
+					}
 				}
 			}
+			if game != nil {
+				client.GameCode = game.Code
+			}
 		}
 	}
 
@@ -48,30 +70,59 @@ func (srv *Server) handleLogin(client *lib.Client, data lib.LoginData) {
 		srv.lobby[player.ID] = player
 	}
 
-	// Associate client with player
+	// Associate client with player, a second login displaces the previous connection
 	client.PlayerID = player.ID
-	player.SetSender(client)
+	previous := player.SwapSender(client)
+	if previous != nil && previous != lib.Sender(client) {
+		srv.displaceConnection(previous)
+	}
 
 	// Send welcome
-	player.Send(lib.Message{
-		Type: lib.MsgWelcome,
-		Data: lib.WelcomeData{
-			PlayerID: player.ID,
-			Username: player.Username,
-		},
-	})
+	srv.sendWelcome(player)
 
-	// If reconnecting to a game, send game state
-	if game != nil {
+	// Warn outdated or newer clients without disconnecting them
+	if client.Version != lib.ProtocolVersion {
+		log.Printf("Protocol mismatch for player %q: client v%d, server v%d", player.ID, client.Version, lib.ProtocolVersion)
+		player.Send(lib.Message{
+			Type: lib.MsgVersionMismatch,
+			Data: lib.VersionMismatchData{
+				ClientVersion: client.Version,
+				ServerVersion: lib.ProtocolVersion,
+			},
+		})
+	}
+
+	// Stale bundles are told by the welcome build hash, the server only logs them
+	if lib.IsStaleBuild(data.BuildHash, srv.buildHash) {
+		log.Printf("Stale client build for player %q: client %s, server %s", player.ID, data.BuildHash, srv.buildHash)
+	}
+
+	// A player back on their turn gets a few seconds to move, even if their clock drained
+	// Only a seat that really dropped qualifies, the cleanup grace also goes by the missing sender
+	// A refresh or a second tab takes over a live connection and gets nothing
+	if game != nil && previous == nil {
+		game.EnsureReconnectBuffer(game.GetPlayerIndex(player.ID))
+	}
+
+	// If reconnecting to a game, send game state or resume it for everyone
+	if game != nil && !srv.resumeIfReconnected(game) {
 		srv.sendGameState(player, game)
 	}
+	if game != nil {
+		srv.announcePresence(game, player.ID, lib.MsgOpponentReconnected)
+	}
 }
 
 // handleCreateGame creates a new game
-func (srv *Server) handleCreateGame(client *lib.Client) {
+func (srv *Server) handleCreateGame(client *lib.Client, data lib.CreateGameData) {
 	srv.mu.Lock()
 	defer srv.mu.Unlock()
 
+	if !srv.friendGamesEnabled {
+		srv.sendError(client, lib.ErrFriendGamesDisabled)
+		return
+	}
+
 	// Verify player exists in lobby
 	player := srv.lobby[client.PlayerID]
 	if player == nil {
@@ -79,38 +130,104 @@ func (srv *Server) handleCreateGame(client *lib.Client) {
 		return
 	}
 
-	// Check if player is already in an active game
-	if client.GameCode != "" {
-		if game, exists := srv.gamesByCode[client.GameCode]; exists {
-			// Only allow creating new game if current game is finished
-			if game.GetStatus() != lib.StatusFinished {
-				srv.sendError(client, lib.ErrPlayerAlreadyInGame)
-				return
-			}
-		}
+	// Only allow creating new game if current game is finished
+	if srv.activeGameFor(player.ID) != nil {
+		srv.sendError(client, lib.ErrPlayerAlreadyInGame)
+		return
 	}
 
+	clock, increment, err := srv.timeControlFor(data)
+	if err != nil {
+		srv.sendError(client, err)
+		return
+	}
+
+	// A player stops watching a game or solving puzzles once they play one
+	srv.stopSpectating(client)
+	srv.stopPuzzleRun(client.PlayerID)
+
 	// Create new game and add player as host
-	game := lib.NewGame(initialClockDuration)
+	game := lib.NewGame(clock, increment)
 	game.TimerCallback = srv.handleTimeout
+	game.PauseOnDisconnect = data.PauseOnDisconnect
+	game.BestOf = lib.ClampBestOf(data.BestOf)
+	game.TieBreak = srv.seriesTieBreak
+	game.ReconnectGrace = reconnectGracePeriod
+	game.SpectatorDelay = srv.spectatorDelay
+	game.DetectRepetition = true // Takebacks can bring a position back
+	game.Invite = strings.TrimSpace(data.Invite)
 	game.AddPlayer(player)
 	srv.gamesByCode[game.Code] = game
+	srv.metrics.gamesCreated.Add(1)
 	client.GameCode = game.Code
 
 	// Notify player of game creation
 	player.Send(lib.Message{
 		Type: lib.MsgGameCreated,
-		Data: lib.GameCreatedData{Code: game.Code},
+		Data: lib.GameCreatedData{Code: game.Code, Invite: game.Invite},
 	})
 
 	srv.sendGameState(player, game)
 }
 
+// handlePlayBot starts a game against the server's bot, which moves first half of the time
+func (srv *Server) handlePlayBot(client *lib.Client, data lib.PlayBotData) {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+
+	player := srv.lobby[client.PlayerID]
+	if player == nil {
+		srv.sendError(client, lib.ErrPlayerNotFound)
+		return
+	}
+	if srv.activeGameFor(player.ID) != nil {
+		srv.sendError(client, lib.ErrPlayerAlreadyInGame)
+		return
+	}
+
+	depth := data.Depth
+	if depth == 0 {
+		depth = lib.DefaultBotDepth
+	}
+	bot := lib.NewBot(depth, data.Profile)
+	srv.stopSpectating(client)
+	srv.stopPuzzleRun(client.PlayerID)
+
+	// The bot takes the second seat with a clock it can never run out of
+	game := lib.NewGame(initialClockDuration, srv.increment)
+	game.TimerCallback = srv.handleTimeout
+	game.AllowReplay = false
+	game.Public = false
+	game.PauseOnDisconnect = true
+	game.ReconnectGrace = reconnectGracePeriod
+	game.SpectatorDelay = srv.spectatorDelay
+	game.TimeRemaining[1] = lib.BotClock
+	if !game.AddPlayer(player) || !game.AddPlayer(lib.NewBotPlayer(bot)) {
+		game.Cleanup()
+		srv.sendError(client, lib.ErrGameNotReady)
+		return
+	}
+	srv.gamesByCode[game.Code] = game
+	srv.metrics.gamesCreated.Add(1)
+	client.GameCode = game.Code
+
+	player.Send(lib.Message{
+		Type: lib.MsgGameStart,
+		Data: srv.buildGameStart(game),
+	})
+	srv.scheduleBotTurn(game)
+}
+
 // handleJoinGame joins an existing game
 func (srv *Server) handleJoinGame(client *lib.Client, data lib.JoinGameData) {
 	srv.mu.Lock()
 	defer srv.mu.Unlock()
 
+	if !srv.friendGamesEnabled {
+		srv.sendError(client, lib.ErrFriendGamesDisabled)
+		return
+	}
+
 	// Normalize game code (trim to 5 chars and uppercase)
 	if len(data.Code) > maxGameCodeLength {
 		data.Code = data.Code[:maxGameCodeLength]
@@ -134,6 +251,7 @@ func (srv *Server) handleJoinGame(client *lib.Client, data lib.JoinGameData) {
 	// Handle reconnection (player already in this game)
 	if game.HasPlayer(player.ID) {
 		client.GameCode = game.Code
+		srv.resumeIfReconnected(game)
 		srv.sendGameState(player, game)
 		srv.broadcastToGame(game, lib.Message{
 			Type: lib.MsgGameState,
@@ -142,6 +260,24 @@ func (srv *Server) handleJoinGame(client *lib.Client, data lib.JoinGameData) {
 		return
 	}
 
+	// Matchmaking games are never joinable by code, full or not
+	if !game.Public {
+		srv.sendError(client, lib.ErrPrivateGame)
+		return
+	}
+
+	// A player may only be in one active game
+	if srv.activeGameFor(player.ID) != nil {
+		srv.sendError(client, lib.ErrPlayerAlreadyInGame)
+		return
+	}
+
+	// A reserved game only lets its invited username in
+	if !game.IsInvited(player.Username) {
+		srv.sendError(client, lib.ErrNotInvited)
+		return
+	}
+
 	// Add player to game (fails if game is full)
 	if !game.AddPlayer(player) {
 		srv.sendError(client, lib.ErrGameFull)
@@ -149,16 +285,13 @@ func (srv *Server) handleJoinGame(client *lib.Client, data lib.JoinGameData) {
 	}
 
 	client.GameCode = game.Code
+	srv.stopSpectating(client)
+	srv.stopPuzzleRun(client.PlayerID)
 
 	// Notify both players that game is starting
 	srv.broadcastToGame(game, lib.Message{
 		Type: lib.MsgGameStart,
-		Data: lib.GameStartData{
-			Code:          game.Code,
-			CurrentTurn:   game.CurrentTurn,
-			Players:       srv.getPlayerInfos(game),
-			TimeRemaining: srv.getTimeRemaining(game),
-		},
+		Data: srv.buildGameStart(game),
 	})
 }
 
@@ -179,40 +312,88 @@ func (srv *Server) handlePlay(client *lib.Client, data lib.PlayData) {
 		return
 	}
 
-	err := game.Play(playerIdx, data.Column)
-	if err != nil {
-		srv.sendError(client, err)
+	// Moves faster than humanly possible are dropped and the client is resynced
+	if srv.minMoveTime > 0 && game.IsTooFast(playerIdx, srv.minMoveTime) {
+		if player := srv.lobby[client.PlayerID]; player != nil {
+			srv.sendGameState(player, game)
+		}
+		return
+	}
+
+	if err := game.Play(playerIdx, data.Column); err != nil {
+		switch err {
+		case lib.ErrInvalidMove:
+			// A column outside the board means a misbehaving client
+			log.Printf("Out of range column %d from player %q in game %s", data.Column, client.PlayerID, game.Code)
+			srv.sendErrorCode(client, err, "OUT_OF_RANGE")
+		case lib.ErrColumnFull:
+			srv.sendErrorCode(client, err, "COLUMN_FULL")
+		case lib.ErrGameNotPlaying:
+			// A move that crossed a win, forfeit or timeout on the wire, game_over already carries the final board
+			if game.GetStatus() == lib.StatusFinished {
+				log.Printf("Dropping move from player %q in finished game %s", client.PlayerID, game.Code)
+				return
+			}
+			srv.sendErrorCode(client, err, "GAME_NOT_PLAYING")
+		case lib.ErrGamePaused:
+			srv.sendErrorCode(client, err, "GAME_PAUSED")
+		case lib.ErrNotYourTurn:
+			// Usually a stale turn after a reconnection, correct the client's view instead of failing loudly
+			log.Printf("Out of turn move from player %q in game %s, resyncing", client.PlayerID, game.Code)
+			if player := srv.lobby[client.PlayerID]; player != nil {
+				srv.sendGameState(player, game)
+			}
+		default:
+			srv.sendError(client, err)
+		}
 		return
 	}
 
+	srv.broadcastMove(game, playerIdx, data.Column)
+}
+
+// broadcastMove tells the players and watchers about a move just played and ends the game if it was the last
+// The bot replies when it is its turn next
+func (srv *Server) broadcastMove(game *lib.Game, playerIdx, column int) {
 	// Broadcast move
-	node := game.Board.GetLastPlayedNode(data.Column)
-	srv.broadcastToGame(game, lib.Message{
+	node := game.Board.GetLastPlayedNode(column)
+	move := lib.Message{
 		Type: lib.MsgMove,
 		Data: lib.MoveData{
 			PlayerIdx:     playerIdx,
-			Column:        data.Column,
+			Column:        column,
 			Row:           node.Row,
 			Board:         game.Board.ToArray(),
 			NextTurn:      game.CurrentTurn,
 			TimeRemaining: srv.getTimeRemaining(game),
 		},
-	})
+	}
+	srv.broadcastToGame(game, move)
+	srv.publishGameEvent(game, move)
 
 	// Check game over
 	if game.GetStatus() == lib.StatusFinished {
-		srv.broadcastToGame(game, lib.Message{
-			Type: lib.MsgGameOver,
-			Data: lib.GameOverData{
-				Result: game.Result,
-				Board:  game.Board.ToArray(),
-			},
-		})
+		srv.announceGameOver(game)
+		return
+	}
+	srv.scheduleBotTurn(game)
+}
+
+// flagSuspiciousTiming logs players whose moves were implausibly fast over the whole game
+func (srv *Server) flagSuspiciousTiming(game *lib.Game) {
+	players := game.GetPlayers()
+	for side, timing := range game.GetTiming() {
+		// The bot replies as fast as its think delay allows, by design
+		if !timing.IsSuspicious() || players[side] == nil || players[side].Bot != nil {
+			continue
+		}
+		log.Printf("Suspicious move timing in game %s: player %q averaged %v over %d moves (fastest %v)",
+			game.Code, players[side].ID, timing.Average(), timing.Count, timing.Min)
 	}
 }
 
 // handleReplay processes replay request
-func (srv *Server) handleReplay(client *lib.Client) {
+func (srv *Server) handleReplay(client *lib.Client, data lib.ReplayData) {
 	srv.mu.Lock()
 	defer srv.mu.Unlock()
 
@@ -228,22 +409,26 @@ func (srv *Server) handleReplay(client *lib.Client) {
 		return
 	}
 
-	// Broadcast replay request
-	srv.broadcastToGame(game, lib.Message{
-		Type: lib.MsgReplayReq,
-		Data: lib.ReplayRequestData{PlayerIdx: playerIdx},
-	})
+	// Matchmaking games send players back to the queue instead
+	if !game.AllowReplay {
+		srv.sendError(client, lib.ErrReplayNotAllowed)
+		return
+	}
 
-	// Check if both agreed
-	if game.RequestReplay(playerIdx) {
+	// Both agreed, the new round replaces the request notice
+	if game.RequestReplay(playerIdx, data.KeepSeats) {
 		srv.broadcastToGame(game, lib.Message{
 			Type: lib.MsgGameStart,
-			Data: lib.GameStartData{
-				Code:          game.Code,
-				CurrentTurn:   game.CurrentTurn,
-				Players:       srv.getPlayerInfos(game),
-				TimeRemaining: srv.getTimeRemaining(game),
-			},
+			Data: srv.buildGameStart(game),
+		})
+		return
+	}
+
+	// Only announce requests still waiting for the other player
+	if game.GetStatus() == lib.StatusFinished {
+		srv.broadcastToGame(game, lib.Message{
+			Type: lib.MsgReplayReq,
+			Data: lib.ReplayRequestData{PlayerIdx: playerIdx, KeepSeats: data.KeepSeats},
 		})
 	}
 }
@@ -265,15 +450,40 @@ func (srv *Server) handleForfeit(client *lib.Client) {
 		return
 	}
 
+	// The game over was already announced, a repeated forfeit must not credit the win again
+	if game.GetStatus() == lib.StatusFinished {
+		srv.sendError(client, lib.ErrGameNotPlaying)
+		return
+	}
+
+	srv.penalizeEarlyLeave(game, playerIdx)
 	game.Forfeit(playerIdx)
 
-	srv.broadcastToGame(game, lib.Message{
-		Type: lib.MsgGameOver,
-		Data: lib.GameOverData{
-			Result: game.Result,
-			Board:  game.Board.ToArray(),
-		},
-	})
+	srv.announceGameOver(game)
+}
+
+// notifyReplayDeclined tells the remaining players that no rematch will happen
+func (srv *Server) notifyReplayDeclined(game *lib.Game, leaver lib.PlayerID) {
+	for _, p := range game.GetMembers() {
+		if p != nil && p.ID != leaver {
+			p.Send(lib.Message{Type: lib.MsgReplayDeclined})
+		}
+	}
+}
+
+// closeWaitingGame deletes the game a player hosts while it still waits for an opponent
+func (srv *Server) closeWaitingGame(client *lib.Client) {
+	game := srv.activeGameFor(client.PlayerID)
+	if game == nil || game.GetStatus() != lib.StatusWaiting {
+		return
+	}
+
+	game.Cleanup()
+	delete(srv.gamesByCode, game.Code)
+	srv.closeGameEvents(game.Code)
+	if client.GameCode == game.Code {
+		client.GameCode = ""
+	}
 }
 
 // handleLeaveLobby processes leave lobby request
@@ -284,22 +494,20 @@ func (srv *Server) handleLeaveLobby(client *lib.Client) {
 	// Clean up player's current game if any
 	if client.GameCode != "" {
 		if game, exists := srv.gamesByCode[client.GameCode]; exists {
-			// Waiting game: delete it (player was alone waiting for opponent)
-			if game.GetStatus() == lib.StatusWaiting {
-				game.Cleanup()
-				delete(srv.gamesByCode, client.GameCode)
+			// Finished game: leaving declines any rematch
+			if game.GetStatus() == lib.StatusFinished && game.AllowReplay {
+				srv.notifyReplayDeclined(game, client.PlayerID)
+
+				// Waiting game: delete it (player was alone waiting for opponent)
+			} else if game.GetStatus() == lib.StatusWaiting {
+				srv.closeWaitingGame(client)
 				// Active game: forfeit (opponent wins)
 			} else if game.GetStatus() == lib.StatusPlaying {
 				playerIdx := game.GetPlayerIndex(client.PlayerID)
 				if playerIdx >= 0 {
+					srv.penalizeEarlyLeave(game, playerIdx)
 					game.Forfeit(playerIdx)
-					srv.broadcastToGame(game, lib.Message{
-						Type: lib.MsgGameOver,
-						Data: lib.GameOverData{
-							Result: game.Result,
-							Board:  game.Board.ToArray(),
-						},
-					})
+					srv.announceGameOver(game)
 				}
 			}
 		}
@@ -307,16 +515,11 @@ func (srv *Server) handleLeaveLobby(client *lib.Client) {
 
 	// Clear player's game code
 	client.GameCode = ""
+	srv.stopSpectating(client)
 
 	// Send welcome message to return player to lobby
 	player := srv.lobby[client.PlayerID]
 	if player != nil {
-		player.Send(lib.Message{
-			Type: lib.MsgWelcome,
-			Data: lib.WelcomeData{
-				PlayerID: player.ID,
-				Username: player.Username,
-			},
-		})
+		srv.sendWelcome(player)
 	}
 }
@@ -0,0 +1,1149 @@
+// Copyright (c) 2025 Haute école d'ingénierie et d'architecture de Fribourg
+// SPDX-License-Identifier: Apache-2.0
+// Author: Marvin Egger marvin.egger@hotmail.ch
+// Created: 16.10.2026
+
+package main
+
+import (
+	"bytes"
+	"log"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/marvinEgger/GOnnect4/server/lib"
+)
+
+// loginTestPlayer logs a new player in and discards the welcome message
+func loginTestPlayer(srv *Server, username string) *lib.Client {
+	client := newTestClient()
+	srv.handleLogin(client, lib.LoginData{Username: username})
+	drainMessages(client)
+	return client
+}
+
+// matchTestPlayers queues two players and accepts their ready check
+func matchTestPlayers(srv *Server, first, second *lib.Client) {
+	srv.handleJoinMatchmaking(first)
+	srv.handleJoinMatchmaking(second)
+	srv.handleReadyResponse(first, lib.ReadyResponseData{Accept: true})
+	srv.handleReadyResponse(second, lib.ReadyResponseData{Accept: true})
+}
+
+// hasMessage checks if one of the messages has the given type
+func hasMessage(msgs []lib.Message, msgType lib.MessageType) bool {
+	for _, msg := range msgs {
+		if msg.Type == msgType {
+			return true
+		}
+	}
+	return false
+}
+
+// hasError checks if one of the messages is the given error
+func hasError(msgs []lib.Message, err error) bool {
+	for _, msg := range msgs {
+		if data, ok := msg.Data.(lib.ErrorData); ok && msg.Type == lib.MsgError && data.Message == err.Error() {
+			return true
+		}
+	}
+	return false
+}
+
+// TestHandleReplay_RejectedForMatchmakingGame tests that matchmaking games cannot be replayed
+func TestHandleReplay_RejectedForMatchmakingGame(t *testing.T) {
+	srv := NewServer()
+	defer srv.cancelFunc()
+
+	alice := loginTestPlayer(srv, "Alice")
+	bob := loginTestPlayer(srv, "Bob")
+	matchTestPlayers(srv, alice, bob)
+
+	game := srv.findGameForClient(alice)
+	if game == nil {
+		t.Fatal("Players should have been matched")
+	}
+
+	srv.handleForfeit(alice)
+	drainMessages(alice)
+	drainMessages(bob)
+
+	srv.handleReplay(bob, lib.ReplayData{})
+
+	if !hasError(drainMessages(bob), lib.ErrReplayNotAllowed) {
+		t.Error("Replay should be rejected for a matchmaking game")
+	}
+	if hasMessage(drainMessages(alice), lib.MsgReplayReq) {
+		t.Error("Opponent should not receive a replay request")
+	}
+}
+
+// TestHandleReplay_AllowedForFriendGame tests that friend games restart when both agree
+func TestHandleReplay_AllowedForFriendGame(t *testing.T) {
+	srv := NewServer()
+	defer srv.cancelFunc()
+
+	alice := loginTestPlayer(srv, "Alice")
+	bob := loginTestPlayer(srv, "Bob")
+	srv.handleCreateGame(alice, lib.CreateGameData{})
+	srv.handleJoinGame(bob, lib.JoinGameData{Code: alice.GameCode})
+	srv.handleForfeit(alice)
+	drainMessages(alice)
+	drainMessages(bob)
+
+	srv.handleReplay(alice, lib.ReplayData{})
+	srv.handleReplay(bob, lib.ReplayData{})
+
+	if !hasMessage(drainMessages(bob), lib.MsgGameStart) {
+		t.Error("Friend game should restart when both players request a replay")
+	}
+}
+
+// TestHandleReplay_SeatChoice tests that seats swap by default and stay when both players keep them
+func TestHandleReplay_SeatChoice(t *testing.T) {
+	cases := []struct {
+		name        string
+		alice, bob  bool // keep_seats sent by each player
+		wantSwapped bool
+	}{
+		{"swap", false, false, true},
+		{"keep", true, true, false},
+	}
+
+	for _, tc := range cases {
+		srv := NewServer()
+		alice := loginTestPlayer(srv, "Alice")
+		bob := loginTestPlayer(srv, "Bob")
+		srv.handleCreateGame(alice, lib.CreateGameData{})
+		srv.handleJoinGame(bob, lib.JoinGameData{Code: alice.GameCode})
+		game := srv.findGameForClient(alice)
+		aliceSeat := game.GetPlayerIndex(alice.PlayerID)
+		srv.handleForfeit(alice)
+
+		srv.handleReplay(alice, lib.ReplayData{KeepSeats: tc.alice})
+		srv.handleReplay(bob, lib.ReplayData{KeepSeats: tc.bob})
+
+		if game.GetStatus() != lib.StatusPlaying {
+			t.Fatalf("%s: game should restart", tc.name)
+		}
+		if swapped := game.GetPlayerIndex(alice.PlayerID) != aliceSeat; swapped != tc.wantSwapped {
+			t.Errorf("%s: expected swapped=%v, got %v", tc.name, tc.wantSwapped, swapped)
+		}
+		srv.cancelFunc()
+	}
+}
+
+// TestHandleReplay_SeatCounterProposal tests that a different seat choice waits for the other player again
+func TestHandleReplay_SeatCounterProposal(t *testing.T) {
+	srv := NewServer()
+	defer srv.cancelFunc()
+
+	alice := loginTestPlayer(srv, "Alice")
+	bob := loginTestPlayer(srv, "Bob")
+	srv.handleCreateGame(alice, lib.CreateGameData{})
+	srv.handleJoinGame(bob, lib.JoinGameData{Code: alice.GameCode})
+	game := srv.findGameForClient(alice)
+	aliceSeat := game.GetPlayerIndex(alice.PlayerID)
+	srv.handleForfeit(alice)
+
+	srv.handleReplay(alice, lib.ReplayData{})
+	srv.handleReplay(bob, lib.ReplayData{KeepSeats: true})
+	if game.GetStatus() != lib.StatusFinished {
+		t.Fatal("Disagreeing seat choices should not restart the game")
+	}
+
+	srv.handleReplay(alice, lib.ReplayData{KeepSeats: true})
+	if game.GetStatus() != lib.StatusPlaying || game.GetPlayerIndex(alice.PlayerID) != aliceSeat {
+		t.Error("Accepting the counter-proposal should restart with the same seats")
+	}
+}
+
+// TestHandleForfeit_RankedAppliesCooldown tests that forfeiting a matchmaking game blocks matchmaking
+func TestHandleForfeit_RankedAppliesCooldown(t *testing.T) {
+	srv := NewServer()
+	defer srv.cancelFunc()
+
+	alice := loginTestPlayer(srv, "Alice")
+	bob := loginTestPlayer(srv, "Bob")
+	matchTestPlayers(srv, alice, bob)
+
+	srv.handleForfeit(alice)
+	drainMessages(alice)
+
+	srv.handleJoinMatchmaking(alice)
+
+	if !hasMessage(drainMessages(alice), lib.MsgCooldown) {
+		t.Error("Player who forfeited a ranked game should be on cooldown")
+	}
+	if srv.queueIndex(alice.PlayerID) >= 0 {
+		t.Error("Player on cooldown should not be queued")
+	}
+}
+
+// TestBuildGameState_Ranked tests that starts and states tell clients whether the game is ranked
+func TestBuildGameState_Ranked(t *testing.T) {
+	srv := NewServer()
+	defer srv.cancelFunc()
+
+	alice := loginTestPlayer(srv, "Alice")
+	bob := loginTestPlayer(srv, "Bob")
+	matchTestPlayers(srv, alice, bob)
+	ranked := srv.findGameForClient(alice)
+	if ranked == nil {
+		t.Fatal("Players should have been matched")
+	}
+	if !srv.buildGameStart(ranked).Ranked || !srv.buildGameState(ranked, alice.PlayerID).Ranked {
+		t.Error("A matchmaking game should be sent as ranked")
+	}
+
+	_, friendly := startTestGame(srv)
+	if srv.buildGameStart(friendly).Ranked || srv.buildGameState(friendly, "").Ranked {
+		t.Error("A friend game should not be sent as ranked")
+	}
+}
+
+// TestBuildGameState_Moves tests that the state lists the moves of the round in order of play
+func TestBuildGameState_Moves(t *testing.T) {
+	srv := NewServer()
+	defer srv.cancelFunc()
+
+	_, game := startTestGame(srv)
+	first := game.CurrentTurn
+	if err := game.Play(first, 3); err != nil {
+		t.Fatalf("First move failed: %v", err)
+	}
+	if err := game.Play(1-first, 3); err != nil {
+		t.Fatalf("Second move failed: %v", err)
+	}
+
+	moves := srv.buildGameState(game, "").Moves
+	want := []lib.LastMove{{Col: 3, Row: lib.Rows - 1}, {Col: 3, Row: lib.Rows - 2}}
+	if len(moves) != len(want) || moves[0] != want[0] || moves[1] != want[1] {
+		t.Errorf("Expected moves %v, got %v", want, moves)
+	}
+}
+
+// TestHandleForfeit_FlagsSuspiciousTiming tests that a game ending without a final move still checks move timing
+func TestHandleForfeit_FlagsSuspiciousTiming(t *testing.T) {
+	srv := NewServer()
+	defer srv.cancelFunc()
+
+	var logs bytes.Buffer
+	defer log.SetOutput(log.Writer())
+	log.SetOutput(&logs)
+
+	mover, game := startTestGame(srv)
+	for _, col := range []int{0, 0, 1, 1, 2, 2, 4, 3, 5, 4} {
+		game.Play(game.CurrentTurn, col)
+	}
+	srv.handleForfeit(mover)
+
+	if !strings.Contains(logs.String(), "Suspicious move timing") {
+		t.Errorf("Expected the fast game to be flagged, got logs %q", logs.String())
+	}
+}
+
+// TestHandleForfeit_FriendGameExempt tests that friend games never apply a cooldown
+func TestHandleForfeit_FriendGameExempt(t *testing.T) {
+	srv := NewServer()
+	defer srv.cancelFunc()
+
+	alice := loginTestPlayer(srv, "Alice")
+	bob := loginTestPlayer(srv, "Bob")
+	srv.handleCreateGame(alice, lib.CreateGameData{})
+	srv.handleJoinGame(bob, lib.JoinGameData{Code: alice.GameCode})
+
+	srv.handleForfeit(alice)
+	drainMessages(alice)
+
+	srv.handleJoinMatchmaking(alice)
+
+	msgs := drainMessages(alice)
+	if hasMessage(msgs, lib.MsgCooldown) {
+		t.Error("Forfeiting a friend game should not apply a cooldown")
+	}
+	if !hasMessage(msgs, lib.MsgMatchmakingSearching) {
+		t.Error("Player should be able to search after a friend game")
+	}
+}
+
+// TestHandleForfeit_RepeatedCountsOnce tests that forfeiting a finished game again credits nothing more
+func TestHandleForfeit_RepeatedCountsOnce(t *testing.T) {
+	srv := NewServer()
+	defer srv.cancelFunc()
+
+	alice := loginTestPlayer(srv, "Alice")
+	bob := loginTestPlayer(srv, "Bob")
+	matchTestPlayers(srv, alice, bob)
+	if srv.findGameForClient(alice) == nil {
+		t.Fatal("Players should have been matched")
+	}
+
+	srv.handleForfeit(alice)
+	winner := srv.lobby[bob.PlayerID]
+	wins, rating := winner.GetWins(), winner.GetRating()
+	drainMessages(alice)
+
+	srv.handleForfeit(alice)
+	if !hasError(drainMessages(alice), lib.ErrGameNotPlaying) {
+		t.Error("Expected ErrGameNotPlaying for a second forfeit")
+	}
+	if winner.GetWins() != wins || wins != 1 {
+		t.Errorf("Expected a single win, got %d", winner.GetWins())
+	}
+	if winner.GetRating() != rating {
+		t.Errorf("Expected the rating to stay %d, got %d", rating, winner.GetRating())
+	}
+	if games := srv.history.Recent(lib.HistoryKey("Bob"), 0); len(games) != 1 {
+		t.Errorf("Expected one history entry, got %d", len(games))
+	}
+}
+
+// TestPauseOnDisconnect_ResumesOnReconnect tests that friend games with the pause policy wait for the player
+func TestPauseOnDisconnect_ResumesOnReconnect(t *testing.T) {
+	srv := NewServer()
+	defer srv.cancelFunc()
+
+	alice := loginTestPlayer(srv, "Alice")
+	bob := loginTestPlayer(srv, "Bob")
+	srv.handleCreateGame(alice, lib.CreateGameData{PauseOnDisconnect: true})
+	srv.handleJoinGame(bob, lib.JoinGameData{Code: alice.GameCode})
+	game := srv.findGameForClient(alice)
+
+	srv.lobby[alice.PlayerID].SetSender(nil)
+	srv.pauseForDisconnect(alice)
+
+	if !game.IsPaused() {
+		t.Fatal("Game should pause when a player disconnects")
+	}
+
+	token := lib.MintResumeToken(alice.PlayerID, srv.resumeSecret, time.Now())
+	srv.handleLogin(newTestClient(), lib.LoginData{Username: "Alice", ResumeToken: token})
+
+	if game.IsPaused() {
+		t.Error("Game should resume once the player reconnects")
+	}
+	if game.GetStatus() != lib.StatusPlaying {
+		t.Error("Game should still be playing after the pause")
+	}
+}
+
+// TestPauseOnDisconnect_ForfeitThenRematch tests that a game forfeited while paused rematches with a running clock
+func TestPauseOnDisconnect_ForfeitThenRematch(t *testing.T) {
+	srv := NewServer()
+	defer srv.cancelFunc()
+
+	alice := loginTestPlayer(srv, "Alice")
+	bob := loginTestPlayer(srv, "Bob")
+	srv.handleCreateGame(alice, lib.CreateGameData{PauseOnDisconnect: true})
+	srv.handleJoinGame(bob, lib.JoinGameData{Code: alice.GameCode})
+	game := srv.findGameForClient(alice)
+	defer game.Cleanup()
+
+	srv.lobby[alice.PlayerID].SetSender(nil)
+	srv.pauseForDisconnect(alice)
+	srv.handleForfeit(bob)
+	if game.IsPaused() {
+		t.Fatal("A finished game should not stay paused")
+	}
+
+	returned := newTestClient()
+	token := lib.MintResumeToken(alice.PlayerID, srv.resumeSecret, time.Now())
+	srv.handleLogin(returned, lib.LoginData{Username: "Alice", ResumeToken: token})
+	srv.handleReplay(returned, lib.ReplayData{})
+	srv.handleReplay(bob, lib.ReplayData{})
+
+	if game.GetStatus() != lib.StatusPlaying || game.IsPaused() {
+		t.Fatalf("Expected a running rematch, got status %v paused %v", game.GetStatus(), game.IsPaused())
+	}
+	if err := game.Play(game.CurrentTurn, 0); err != nil {
+		t.Errorf("Expected the rematch to accept moves, got %v", err)
+	}
+}
+
+// TestPauseOnDisconnect_AnnouncesPresence tests that the opponent hears about a drop and the return
+func TestPauseOnDisconnect_AnnouncesPresence(t *testing.T) {
+	srv := NewServer()
+	defer srv.cancelFunc()
+
+	alice := loginTestPlayer(srv, "Alice")
+	bob := loginTestPlayer(srv, "Bob")
+	srv.handleCreateGame(alice, lib.CreateGameData{PauseOnDisconnect: true})
+	srv.handleJoinGame(bob, lib.JoinGameData{Code: alice.GameCode})
+	drainMessages(bob)
+
+	srv.lobby[alice.PlayerID].SetSender(nil)
+	srv.pauseForDisconnect(alice)
+
+	var presence *lib.PresenceData
+	for _, msg := range drainMessages(bob) {
+		if data, ok := msg.Data.(lib.PresenceData); ok && msg.Type == lib.MsgOpponentDisconnected {
+			presence = &data
+		}
+	}
+	if presence == nil {
+		t.Fatal("Opponent should be told the player disconnected")
+	}
+	if !presence.Paused || presence.PlayerIdx != srv.findGameForClient(bob).GetPlayerIndex(alice.PlayerID) {
+		t.Errorf("Expected a paused presence for the disconnected side, got %+v", *presence)
+	}
+
+	token := lib.MintResumeToken(alice.PlayerID, srv.resumeSecret, time.Now())
+	srv.handleLogin(newTestClient(), lib.LoginData{Username: "Alice", ResumeToken: token})
+
+	if !hasMessage(drainMessages(bob), lib.MsgOpponentReconnected) {
+		t.Error("Opponent should be told the player reconnected")
+	}
+}
+
+// TestHandleLeaveLobby_DeclinesReplay tests that leaving a finished friend game notifies the opponent
+func TestHandleLeaveLobby_DeclinesReplay(t *testing.T) {
+	srv := NewServer()
+	defer srv.cancelFunc()
+
+	alice := loginTestPlayer(srv, "Alice")
+	bob := loginTestPlayer(srv, "Bob")
+	srv.handleCreateGame(alice, lib.CreateGameData{})
+	srv.handleJoinGame(bob, lib.JoinGameData{Code: alice.GameCode})
+	srv.handleForfeit(alice)
+	drainMessages(bob)
+
+	srv.handleLeaveLobby(alice)
+
+	if !hasMessage(drainMessages(bob), lib.MsgReplayDeclined) {
+		t.Error("Opponent should be told the rematch was declined")
+	}
+}
+
+// TestActiveGameInvariant_JoinSecondGame tests that a player cannot join a game while in another active game
+func TestActiveGameInvariant_JoinSecondGame(t *testing.T) {
+	srv := NewServer()
+	defer srv.cancelFunc()
+
+	alice := loginTestPlayer(srv, "Alice")
+	bob := loginTestPlayer(srv, "Bob")
+	srv.handleCreateGame(alice, lib.CreateGameData{})
+	srv.handleCreateGame(bob, lib.CreateGameData{})
+	drainMessages(alice)
+	drainMessages(bob)
+
+	srv.handleJoinGame(bob, lib.JoinGameData{Code: alice.GameCode})
+
+	if !hasError(drainMessages(bob), lib.ErrPlayerAlreadyInGame) {
+		t.Error("Joining a second active game should be rejected")
+	}
+	if game := srv.gamesByCode[alice.GameCode]; game.HasPlayer(bob.PlayerID) {
+		t.Error("Player should not have been added to the second game")
+	}
+}
+
+// TestActiveGameInvariant_CreateTwice tests that a player cannot host two waiting games
+func TestActiveGameInvariant_CreateTwice(t *testing.T) {
+	srv := NewServer()
+	defer srv.cancelFunc()
+
+	alice := loginTestPlayer(srv, "Alice")
+	srv.handleCreateGame(alice, lib.CreateGameData{})
+	drainMessages(alice)
+
+	// A stale cached code must not bypass the check
+	alice.GameCode = ""
+	srv.handleCreateGame(alice, lib.CreateGameData{})
+
+	if !hasError(drainMessages(alice), lib.ErrPlayerAlreadyInGame) {
+		t.Error("Creating a second active game should be rejected")
+	}
+	if len(srv.gamesByCode) != 1 {
+		t.Errorf("Expected a single game, got %d", len(srv.gamesByCode))
+	}
+}
+
+// TestActiveGameInvariant_Matchmaking tests that players in an active game cannot search for another
+func TestActiveGameInvariant_Matchmaking(t *testing.T) {
+	srv := NewServer()
+	defer srv.cancelFunc()
+
+	alice := loginTestPlayer(srv, "Alice")
+	bob := loginTestPlayer(srv, "Bob")
+	srv.handleCreateGame(alice, lib.CreateGameData{})
+	srv.handleJoinGame(bob, lib.JoinGameData{Code: alice.GameCode})
+	drainMessages(alice)
+
+	srv.handleJoinMatchmaking(alice)
+
+	if !hasError(drainMessages(alice), lib.ErrPlayerAlreadyInGame) {
+		t.Error("Joining matchmaking from an active game should be rejected")
+	}
+	if len(srv.matchmakingQueue) != 0 {
+		t.Error("Player should not be queued")
+	}
+}
+
+// TestHandleJoinMatchmaking_ClosesWaitingGame tests that searching for a match abandons a hosted waiting game
+func TestHandleJoinMatchmaking_ClosesWaitingGame(t *testing.T) {
+	srv := NewServer()
+	defer srv.cancelFunc()
+
+	alice := loginTestPlayer(srv, "Alice")
+	srv.handleCreateGame(alice, lib.CreateGameData{})
+	code := alice.GameCode
+	drainMessages(alice)
+
+	srv.handleJoinMatchmaking(alice)
+
+	if _, exists := srv.gamesByCode[code]; exists {
+		t.Error("Waiting game should be closed when its host joins matchmaking")
+	}
+	if alice.GameCode != "" {
+		t.Error("Host should no longer be attached to the closed game")
+	}
+	if !hasMessage(drainMessages(alice), lib.MsgMatchmakingSearching) {
+		t.Error("Host should be searching for a match")
+	}
+}
+
+// TestHandleLogin_ResumeToken tests that a valid resume token restores the same player
+func TestHandleLogin_ResumeToken(t *testing.T) {
+	srv := NewServer()
+	defer srv.cancelFunc()
+
+	alice := loginTestPlayer(srv, "Alice")
+	playerID := alice.PlayerID
+	token := lib.MintResumeToken(playerID, srv.resumeSecret, time.Now())
+
+	client := newTestClient()
+	srv.handleLogin(client, lib.LoginData{Username: "Alice", ResumeToken: token})
+
+	if client.PlayerID != playerID {
+		t.Errorf("Resume token should restore player %s, got %s", playerID, client.PlayerID)
+	}
+}
+
+// TestHandleLogin_WelcomeBuildHash tests that the welcome tells clients which build the server runs
+func TestHandleLogin_WelcomeBuildHash(t *testing.T) {
+	srv := NewServer()
+	defer srv.cancelFunc()
+	srv.buildHash = "abc123"
+
+	client := newTestClient()
+	srv.handleLogin(client, lib.LoginData{Username: "Alice", BuildHash: "old999"})
+
+	for _, msg := range drainMessages(client) {
+		if welcome, ok := msg.Data.(lib.WelcomeData); ok {
+			if welcome.BuildHash != "abc123" {
+				t.Errorf("Expected build hash abc123, got %q", welcome.BuildHash)
+			}
+			return
+		}
+	}
+	t.Error("Expected a welcome message, a stale build is not disconnected")
+}
+
+// TestHandleLogin_ForgedResumeToken tests that a token signed with another secret starts a new session
+func TestHandleLogin_ForgedResumeToken(t *testing.T) {
+	srv := NewServer()
+	defer srv.cancelFunc()
+
+	alice := loginTestPlayer(srv, "Alice")
+	token := lib.MintResumeToken(alice.PlayerID, []byte("not the server secret"), time.Now())
+
+	client := newTestClient()
+	srv.handleLogin(client, lib.LoginData{Username: "Mallory", ResumeToken: token})
+
+	if client.PlayerID == alice.PlayerID {
+		t.Error("Forged resume token should not take over the player")
+	}
+}
+
+// TestHandlePlay_MinMoveTime tests that an instant move is dropped with a resync when a minimum think time is set
+func TestHandlePlay_MinMoveTime(t *testing.T) {
+	srv := NewServer()
+	defer srv.cancelFunc()
+	srv.minMoveTime = time.Minute
+
+	alice := loginTestPlayer(srv, "Alice")
+	bob := loginTestPlayer(srv, "Bob")
+	srv.handleCreateGame(alice, lib.CreateGameData{})
+	srv.handleJoinGame(bob, lib.JoinGameData{Code: alice.GameCode})
+	game := srv.findGameForClient(alice)
+	drainMessages(alice)
+	drainMessages(bob)
+
+	mover := alice
+	if game.GetPlayerIndex(bob.PlayerID) == game.CurrentTurn {
+		mover = bob
+	}
+	srv.handlePlay(mover, lib.PlayData{Column: 3})
+
+	msgs := drainMessages(mover)
+	if hasMessage(msgs, lib.MsgMove) {
+		t.Error("Instant move should not be applied")
+	}
+	if !hasMessage(msgs, lib.MsgGameState) {
+		t.Error("Player should be resynced with the game state")
+	}
+	if game.MoveCount != 0 {
+		t.Errorf("Expected no move played, got %d", game.MoveCount)
+	}
+}
+
+// TestHandleLogin_DisplacesPreviousConnection tests that a second login takes over and notifies the first tab
+func TestHandleLogin_DisplacesPreviousConnection(t *testing.T) {
+	srv := NewServer()
+	defer srv.cancelFunc()
+
+	first := loginTestPlayer(srv, "Alice")
+	playerID := first.PlayerID
+	token := lib.MintResumeToken(playerID, srv.resumeSecret, time.Now())
+
+	second := newTestClient()
+	srv.handleLogin(second, lib.LoginData{Username: "Alice", ResumeToken: token})
+	drainMessages(second)
+
+	if !hasMessage(drainMessages(first), lib.MsgSessionTaken) {
+		t.Error("Displaced connection should be told the session was taken")
+	}
+	if first.PlayerID != "" {
+		t.Error("Displaced connection should be detached from the player")
+	}
+
+	srv.lobby[playerID].Send(lib.Message{Type: lib.MsgQueueUpdate})
+	if !hasMessage(drainMessages(second), lib.MsgQueueUpdate) {
+		t.Error("New connection should receive the player's messages")
+	}
+}
+
+// startTestGame starts a friend game and returns the client whose turn it is
+func startTestGame(srv *Server) (*lib.Client, *lib.Game) {
+	alice := loginTestPlayer(srv, "Alice")
+	bob := loginTestPlayer(srv, "Bob")
+	srv.handleCreateGame(alice, lib.CreateGameData{})
+	srv.handleJoinGame(bob, lib.JoinGameData{Code: alice.GameCode})
+	game := srv.findGameForClient(alice)
+	drainMessages(alice)
+	drainMessages(bob)
+
+	if game.GetPlayerIndex(bob.PlayerID) == game.CurrentTurn {
+		return bob, game
+	}
+	return alice, game
+}
+
+// errorCode returns the code of the first error message, empty if none
+func errorCode(msgs []lib.Message) string {
+	for _, msg := range msgs {
+		if data, ok := msg.Data.(lib.ErrorData); ok && msg.Type == lib.MsgError {
+			return data.Code
+		}
+	}
+	return ""
+}
+
+// TestHandlePlay_OutOfRange tests that a column outside the board is reported as OUT_OF_RANGE
+func TestHandlePlay_OutOfRange(t *testing.T) {
+	srv := NewServer()
+	defer srv.cancelFunc()
+
+	mover, _ := startTestGame(srv)
+	for _, column := range []int{-1, lib.Cols, 99} {
+		srv.handlePlay(mover, lib.PlayData{Column: column})
+		if code := errorCode(drainMessages(mover)); code != "OUT_OF_RANGE" {
+			t.Errorf("Column %d: expected OUT_OF_RANGE, got %q", column, code)
+		}
+	}
+}
+
+// TestHandlePlay_ColumnFull tests that a full column is reported as COLUMN_FULL
+func TestHandlePlay_ColumnFull(t *testing.T) {
+	srv := NewServer()
+	defer srv.cancelFunc()
+
+	// An even number of moves hands the turn back to the same player
+	mover, game := startTestGame(srv)
+	for i := 0; i < lib.Rows; i++ {
+		game.Play(game.CurrentTurn, 0)
+	}
+
+	srv.handlePlay(mover, lib.PlayData{Column: 0})
+	if code := errorCode(drainMessages(mover)); code != "COLUMN_FULL" {
+		t.Errorf("Expected COLUMN_FULL, got %q", code)
+	}
+}
+
+// TestHandlePlay_PausedCode tests that a move in a paused game is reported as GAME_PAUSED
+func TestHandlePlay_PausedCode(t *testing.T) {
+	srv := NewServer()
+	defer srv.cancelFunc()
+
+	mover, game := startTestGame(srv)
+	game.PauseOnDisconnect = true
+	game.Pause()
+
+	srv.handlePlay(mover, lib.PlayData{Column: 0})
+	if code := errorCode(drainMessages(mover)); code != "GAME_PAUSED" {
+		t.Errorf("Expected GAME_PAUSED, got %q", code)
+	}
+}
+
+// TestHandlePlay_OutOfTurnResyncs tests that a move out of turn gets a game state, not an error
+func TestHandlePlay_OutOfTurnResyncs(t *testing.T) {
+	srv := NewServer()
+	defer srv.cancelFunc()
+
+	mover, game := startTestGame(srv)
+	srv.handlePlay(mover, lib.PlayData{Column: 0})
+	drainMessages(mover)
+
+	// The client still believes it is its turn
+	srv.handlePlay(mover, lib.PlayData{Column: 1})
+	msgs := drainMessages(mover)
+	if !hasMessage(msgs, lib.MsgGameState) {
+		t.Error("Expected a game state resync")
+	}
+	if hasMessage(msgs, lib.MsgError) {
+		t.Error("Out of turn move should not be reported as an error")
+	}
+	if game.Board.ToArray()[lib.Rows-1][1] != lib.CellEmpty {
+		t.Error("Out of turn move should not be played")
+	}
+}
+
+// TestHandlePlay_BeforeStart tests that a move sent before the game begins is rejected and leaves the board empty
+func TestHandlePlay_BeforeStart(t *testing.T) {
+	srv := NewServer()
+	defer srv.cancelFunc()
+
+	alice := loginTestPlayer(srv, "Alice")
+	srv.handleCreateGame(alice, lib.CreateGameData{})
+	game := srv.findGameForClient(alice)
+	drainMessages(alice)
+
+	srv.handlePlay(alice, lib.PlayData{Column: 3})
+	if !hasError(drainMessages(alice), lib.ErrGameNotPlaying) {
+		t.Error("Expected ErrGameNotPlaying before the opponent joined")
+	}
+
+	bob := loginTestPlayer(srv, "Bob")
+	srv.handleJoinGame(bob, lib.JoinGameData{Code: game.Code})
+	if game.GetMoveCount() != 0 || game.Board.ToArray()[lib.Rows-1][3] != lib.CellEmpty {
+		t.Error("The early move should not count once play begins")
+	}
+}
+
+// TestHandlePlay_AfterGameOver tests that a move arriving just after the game ended is dropped without an error
+func TestHandlePlay_AfterGameOver(t *testing.T) {
+	finishes := map[string]func(srv *Server, first, second *lib.Client){
+		"forfeit": func(srv *Server, first, second *lib.Client) {
+			srv.handleForfeit(first)
+		},
+		"win": func(srv *Server, first, second *lib.Client) {
+			for i := 0; i < 3; i++ {
+				srv.handlePlay(first, lib.PlayData{Column: 0})
+				srv.handlePlay(second, lib.PlayData{Column: 1})
+			}
+			srv.handlePlay(first, lib.PlayData{Column: 0})
+		},
+	}
+
+	for name, finish := range finishes {
+		t.Run(name, func(t *testing.T) {
+			srv := NewServer()
+			defer srv.cancelFunc()
+
+			alice := loginTestPlayer(srv, "Alice")
+			bob := loginTestPlayer(srv, "Bob")
+			srv.handleCreateGame(alice, lib.CreateGameData{})
+			srv.handleJoinGame(bob, lib.JoinGameData{Code: alice.GameCode})
+			game := srv.findGameForClient(alice)
+
+			first, second := alice, bob
+			if game.GetPlayerIndex(bob.PlayerID) == game.CurrentTurn {
+				first, second = bob, alice
+			}
+			finish(srv, first, second)
+			if game.GetStatus() != lib.StatusFinished {
+				t.Fatal("The game should be over")
+			}
+			board := game.Board.ToArray()
+			drainMessages(second)
+
+			// The other player's move was already on its way
+			srv.handlePlay(second, lib.PlayData{Column: 5})
+			if msgs := drainMessages(second); hasMessage(msgs, lib.MsgError) {
+				t.Errorf("A move crossing the end of the game should not produce an error, got %v", msgs)
+			}
+			if !reflect.DeepEqual(game.Board.ToArray(), board) {
+				t.Error("The late move should not change the board")
+			}
+		})
+	}
+}
+
+// TestHandleJoinGame_MatchmakingGame tests that matchmaking games cannot be joined by code, full or not
+func TestHandleJoinGame_MatchmakingGame(t *testing.T) {
+	srv := NewServer()
+	defer srv.cancelFunc()
+
+	alice := loginTestPlayer(srv, "Alice")
+	bob := loginTestPlayer(srv, "Bob")
+	matchTestPlayers(srv, alice, bob)
+	game := srv.findGameForClient(alice)
+
+	carol := loginTestPlayer(srv, "Carol")
+	srv.handleJoinGame(carol, lib.JoinGameData{Code: game.Code})
+	if !hasError(drainMessages(carol), lib.ErrPrivateGame) {
+		t.Error("Joining a matchmaking game by code should be refused as private")
+	}
+
+	// A seat left open must not let a stranger in either
+	dave := loginTestPlayer(srv, "Dave")
+	srv.handleCreateGame(dave, lib.CreateGameData{})
+	waiting := srv.findGameForClient(dave)
+	waiting.Public = false
+
+	srv.handleJoinGame(carol, lib.JoinGameData{Code: waiting.Code})
+	if !hasError(drainMessages(carol), lib.ErrPrivateGame) {
+		t.Error("A private game with an open seat should still be refused")
+	}
+	if waiting.HasPlayer(carol.PlayerID) {
+		t.Error("The refused player should not be seated")
+	}
+
+	// The matched players can still come back to their own game
+	drainMessages(alice)
+	srv.handleJoinGame(alice, lib.JoinGameData{Code: game.Code})
+	if !hasMessage(drainMessages(alice), lib.MsgGameState) {
+		t.Error("A matched player should be able to rejoin their game")
+	}
+}
+
+// TestHandleJoinGame_Invite tests that a reserved game only lets the invited username in
+func TestHandleJoinGame_Invite(t *testing.T) {
+	srv := NewServer()
+	defer srv.cancelFunc()
+
+	alice := loginTestPlayer(srv, "Alice")
+	srv.handleCreateGame(alice, lib.CreateGameData{Invite: " bob "})
+	game := srv.findGameForClient(alice)
+
+	carol := loginTestPlayer(srv, "Carol")
+	srv.handleJoinGame(carol, lib.JoinGameData{Code: game.Code})
+	if !hasError(drainMessages(carol), lib.ErrNotInvited) {
+		t.Error("An uninvited player should be rejected")
+	}
+	if game.HasPlayer(carol.PlayerID) {
+		t.Error("An uninvited player should not be added")
+	}
+
+	bob := loginTestPlayer(srv, "Bob")
+	srv.handleJoinGame(bob, lib.JoinGameData{Code: game.Code})
+	if !hasMessage(drainMessages(bob), lib.MsgGameStart) {
+		t.Error("The invited player should join, ignoring case and spaces")
+	}
+}
+
+// TestAnnounceGameOver_CreditsWinner tests that a forfeit counts as a win for the opponent only
+func TestAnnounceGameOver_CreditsWinner(t *testing.T) {
+	srv := NewServer()
+	defer srv.cancelFunc()
+
+	mover, game := startTestGame(srv)
+	players := game.GetPlayers()
+	winner := players[1-game.GetPlayerIndex(mover.PlayerID)]
+
+	srv.handleForfeit(mover)
+
+	if winner.GetWins() != 1 {
+		t.Errorf("Winner should have 1 win, got %d", winner.GetWins())
+	}
+	if loser := srv.lobby[mover.PlayerID]; loser.GetWins() != 0 {
+		t.Errorf("Loser should have no win, got %d", loser.GetWins())
+	}
+	if hasMessage(drainMessages(mover), lib.MsgStats) {
+		t.Error("Loser should not receive a stats update")
+	}
+}
+
+// statsUpdate returns the last stats message received, ok false if none
+func statsUpdate(msgs []lib.Message) (stats lib.StatsData, ok bool) {
+	for _, msg := range msgs {
+		if data, isStats := msg.Data.(lib.StatsData); isStats && msg.Type == lib.MsgStats {
+			stats, ok = data, true
+		}
+	}
+	return stats, ok
+}
+
+// TestAnnounceGameOver_Streaks tests that wins extend a streak and a later draw breaks it
+func TestAnnounceGameOver_Streaks(t *testing.T) {
+	srv := NewServer()
+	defer srv.cancelFunc()
+
+	mover, game := startTestGame(srv)
+	winnerIdx := 1 - game.GetPlayerIndex(mover.PlayerID)
+	winner := game.GetPlayers()[winnerIdx]
+	winner.RecordWin()
+
+	srv.handleForfeit(mover)
+	if streak, best := winner.GetStreaks(); streak != 2 || best != 2 {
+		t.Errorf("Expected a streak of 2, got %d (best %d)", streak, best)
+	}
+
+	// The next game of the pair ends in a draw
+	game.Result = lib.ResultDraw
+	srv.announceGameOver(game)
+
+	if streak, best := winner.GetStreaks(); streak != 0 || best != 2 {
+		t.Errorf("A draw should break the streak and keep the best, got %d (best %d)", streak, best)
+	}
+}
+
+// TestAnnounceGameOver_LossResetsStreak tests that the loser is told their streak ended
+func TestAnnounceGameOver_LossResetsStreak(t *testing.T) {
+	srv := NewServer()
+	defer srv.cancelFunc()
+
+	mover, _ := startTestGame(srv)
+	loser := srv.lobby[mover.PlayerID]
+	loser.RecordWin()
+
+	srv.handleForfeit(mover)
+
+	if streak, best := loser.GetStreaks(); streak != 0 || best != 1 {
+		t.Errorf("Expected the streak to reset and the best to stay 1, got %d (best %d)", streak, best)
+	}
+	stats, ok := statsUpdate(drainMessages(mover))
+	if !ok || stats.Streak != 0 || stats.BestStreak != 1 {
+		t.Errorf("Expected a stats update with the broken streak, got %+v (sent %v)", stats, ok)
+	}
+}
+
+// TestAnnounceGameOver_AbortKeepsStreak tests that abandoning a game that never started keeps the streak
+func TestAnnounceGameOver_AbortKeepsStreak(t *testing.T) {
+	srv := NewServer()
+	defer srv.cancelFunc()
+
+	alice := loginTestPlayer(srv, "Alice")
+	player := srv.lobby[alice.PlayerID]
+	player.RecordWin()
+
+	srv.handleCreateGame(alice, lib.CreateGameData{})
+	srv.handleForfeit(alice)
+
+	if streak, _ := player.GetStreaks(); streak != 1 {
+		t.Errorf("An aborted game should not break the streak, got %d", streak)
+	}
+	if _, ok := statsUpdate(drainMessages(alice)); ok {
+		t.Error("An aborted game should not send a stats update")
+	}
+}
+
+// TestDisabledModes_RejectRequests tests that modes turned off by the operator refuse to start games
+func TestDisabledModes_RejectRequests(t *testing.T) {
+	srv := NewServer()
+	defer srv.cancelFunc()
+	srv.friendGamesEnabled = false
+	srv.matchmakingEnabled = false
+
+	alice := loginTestPlayer(srv, "Alice")
+	srv.handleCreateGame(alice, lib.CreateGameData{})
+	srv.handleJoinGame(alice, lib.JoinGameData{Code: "ABCDE"})
+	srv.handleJoinMatchmaking(alice)
+
+	msgs := drainMessages(alice)
+	if !hasError(msgs, lib.ErrFriendGamesDisabled) {
+		t.Error("Friend games should be rejected when disabled")
+	}
+	if !hasError(msgs, lib.ErrMatchmakingDisabled) {
+		t.Error("Matchmaking should be rejected when disabled")
+	}
+	if len(srv.gamesByCode) != 0 || len(srv.matchmakingQueue) != 0 {
+		t.Error("No game or queue entry should be created")
+	}
+}
+
+// TestCapabilities_FollowConfig tests that the advertised features match the server configuration
+func TestCapabilities_FollowConfig(t *testing.T) {
+	srv := NewServer()
+	defer srv.cancelFunc()
+
+	if caps := srv.capabilities(); !caps.FriendGames || !caps.Matchmaking || !caps.Chat || !caps.Series {
+		t.Errorf("Expected the classic features enabled by default, got %+v", *caps)
+	}
+
+	srv.friendGamesEnabled = false
+	srv.chatEnabled = false
+	if caps := srv.capabilities(); caps.FriendGames || caps.Series || caps.Chat || !caps.Matchmaking {
+		t.Errorf("Expected only matchmaking enabled, got %+v", *caps)
+	}
+
+	alice := loginTestPlayer(srv, "Alice")
+	srv.handleLobbyChat(alice, lib.LobbyChatData{Text: "hello"})
+	if !hasError(drainMessages(alice), lib.ErrChatDisabled) {
+		t.Error("Chat should be rejected when disabled")
+	}
+}
+
+// TestSendWelcome_Capabilities tests that the welcome tells clients which modes are enabled
+func TestSendWelcome_Capabilities(t *testing.T) {
+	srv := NewServer()
+	defer srv.cancelFunc()
+	srv.matchmakingEnabled = false
+
+	client := newTestClient()
+	srv.handleLogin(client, lib.LoginData{Username: "Alice"})
+
+	for _, msg := range drainMessages(client) {
+		if data, ok := msg.Data.(lib.WelcomeData); ok {
+			if data.Capabilities == nil || !data.Capabilities.FriendGames || data.Capabilities.Matchmaking {
+				t.Errorf("Expected only friend games enabled, got %+v", data.Capabilities)
+			}
+			return
+		}
+	}
+	t.Error("Expected a welcome message")
+}
+
+// TestHandleReplay_NoNoticeWhenAgreed tests that the accepting request starts the game without a request notice
+func TestHandleReplay_NoNoticeWhenAgreed(t *testing.T) {
+	srv := NewServer()
+	defer srv.cancelFunc()
+
+	alice := loginTestPlayer(srv, "Alice")
+	bob := loginTestPlayer(srv, "Bob")
+	srv.handleCreateGame(alice, lib.CreateGameData{})
+	srv.handleJoinGame(bob, lib.JoinGameData{Code: alice.GameCode})
+	srv.handleForfeit(alice)
+	drainMessages(bob)
+
+	srv.handleReplay(alice, lib.ReplayData{})
+	if !hasMessage(drainMessages(bob), lib.MsgReplayReq) {
+		t.Fatal("A pending request should be announced")
+	}
+
+	srv.handleReplay(bob, lib.ReplayData{})
+
+	msgs := drainMessages(bob)
+	if !hasMessage(msgs, lib.MsgGameStart) {
+		t.Error("Game should restart once both agreed")
+	}
+	if hasMessage(msgs, lib.MsgReplayReq) {
+		t.Error("The accepting request should not be announced")
+	}
+}
+
+// TestHandleLogin_ReconnectBufferBeforeTimeout tests that reconnecting just before expiry leaves time to move
+func TestHandleLogin_ReconnectBufferBeforeTimeout(t *testing.T) {
+	srv := NewServer()
+	defer srv.cancelFunc()
+
+	mover, game := startTestGame(srv)
+	moverIdx := game.GetPlayerIndex(mover.PlayerID)
+	srv.lobby[mover.PlayerID].SetSender(nil)
+	srv.pauseForDisconnect(mover)
+
+	// The clock drained while offline, 100ms are left
+	game.TimeRemaining[moverIdx] = time.Second
+	game.TurnStartedAt = time.Now().Add(-900 * time.Millisecond)
+
+	token := lib.MintResumeToken(mover.PlayerID, srv.resumeSecret, time.Now())
+	srv.handleLogin(newTestClient(), lib.LoginData{Username: "Alice", ResumeToken: token})
+
+	if left := game.GetTimeRemaining()[moverIdx]; left < lib.ReconnectBuffer-time.Second {
+		t.Errorf("Expected at least %v left after reconnecting, got %v", lib.ReconnectBuffer, left)
+	}
+
+	// The timeout of the drained clock may still be waiting for the server lock
+	srv.handleTimeout(game.Code, moverIdx)
+	if game.GetStatus() != lib.StatusPlaying {
+		t.Error("A stale timeout should not end the game after a reconnect")
+	}
+}
+
+// TestHandleLogin_ResumeWithoutDisconnectKeepsClock tests that refreshes over a live connection do not refill the clock
+func TestHandleLogin_ResumeWithoutDisconnectKeepsClock(t *testing.T) {
+	srv := NewServer()
+	defer srv.cancelFunc()
+
+	mover, game := startTestGame(srv)
+	moverIdx := game.GetPlayerIndex(mover.PlayerID)
+	game.TimeRemaining[moverIdx] = time.Second
+	before := game.GetTimeRemaining()[moverIdx]
+
+	token := lib.MintResumeToken(mover.PlayerID, srv.resumeSecret, time.Now())
+	srv.handleLogin(newTestClient(), lib.LoginData{Username: "Alice", ResumeToken: token})
+	srv.handleLogin(newTestClient(), lib.LoginData{Username: "Alice", ResumeToken: token})
+
+	if left := game.GetTimeRemaining()[moverIdx]; left > before {
+		t.Errorf("Expected the clock to stay at %v without a disconnect, got %v", before, left)
+	}
+}
+
+// TestHandleLogin_ReconnectBufferOncePerTurn tests that dropping twice on the same turn refills the clock once
+func TestHandleLogin_ReconnectBufferOncePerTurn(t *testing.T) {
+	srv := NewServer()
+	defer srv.cancelFunc()
+
+	mover, game := startTestGame(srv)
+	moverIdx := game.GetPlayerIndex(mover.PlayerID)
+	token := lib.MintResumeToken(mover.PlayerID, srv.resumeSecret, time.Now())
+
+	game.TimeRemaining[moverIdx] = time.Second
+	srv.lobby[mover.PlayerID].SetSender(nil)
+	srv.handleLogin(newTestClient(), lib.LoginData{Username: "Alice", ResumeToken: token})
+	if left := game.GetTimeRemaining()[moverIdx]; left < lib.ReconnectBuffer-time.Second {
+		t.Fatalf("Expected the first reconnect to leave %v, got %v", lib.ReconnectBuffer, left)
+	}
+
+	game.TimeRemaining[moverIdx] = time.Second
+	srv.lobby[mover.PlayerID].SetSender(nil)
+	srv.handleLogin(newTestClient(), lib.LoginData{Username: "Alice", ResumeToken: token})
+	if left := game.GetTimeRemaining()[moverIdx]; left > time.Second {
+		t.Errorf("Expected no second buffer on the same turn, got %v", left)
+	}
+}
+
+// TestHandleCreateGame_TimeControl tests the default, a picked and an out of bounds time control
+func TestHandleCreateGame_TimeControl(t *testing.T) {
+	srv := NewServer()
+	defer srv.cancelFunc()
+
+	tests := []struct {
+		name      string
+		data      lib.CreateGameData
+		clock     time.Duration
+		increment time.Duration
+	}{
+		{"default", lib.CreateGameData{}, initialClockDuration, 0},
+		{"picked", lib.CreateGameData{ClockSeconds: 300, IncrementSeconds: 5}, 5 * time.Minute, 5 * time.Second},
+		{"increment only", lib.CreateGameData{IncrementSeconds: 2}, initialClockDuration, 2 * time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			host := loginTestPlayer(srv, "Host")
+			srv.handleCreateGame(host, tt.data)
+			game := srv.findGameForClient(host)
+			if game == nil {
+				t.Fatal("Expected a game to be created")
+			}
+			defer game.Cleanup()
+
+			if game.InitialClock != tt.clock || game.Increment != tt.increment {
+				t.Errorf("Expected %v + %v, got %v + %v", tt.clock, tt.increment, game.InitialClock, game.Increment)
+			}
+			if game.TimeRemaining[0] != tt.clock {
+				t.Errorf("Expected the clocks to start at %v, got %v", tt.clock, game.TimeRemaining[0])
+			}
+		})
+	}
+
+	for _, data := range []lib.CreateGameData{{ClockSeconds: 5}, {ClockSeconds: 3600}, {ClockSeconds: 60, IncrementSeconds: -1}} {
+		host := loginTestPlayer(srv, "Host")
+		srv.handleCreateGame(host, data)
+		if !hasError(drainMessages(host), lib.ErrInvalidTimeControl) || host.GameCode != "" {
+			t.Errorf("Expected %+v to be refused", data)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+// Copyright (c) 2025 Haute école d'ingénierie et d'architecture de Fribourg
+// SPDX-License-Identifier: Apache-2.0
+// Author: Marvin Egger marvin.egger@hotmail.ch
+// Created: 16.10.2026
+
+package main
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/marvinEgger/GOnnect4/server/lib"
+)
+
+// serverMetrics counts events since the server started, handlers bump them without holding mu
+// Finished games are not counted here, they are read from the saved win stats and game totals
+type serverMetrics struct {
+	gamesCreated atomic.Int64
+}
+
+// ServerStats is a snapshot of the load of the server and of its counters
+type ServerStats struct {
+	Games int `json:"games"` // Games held in memory, waiting, playing or finished
+	Lobby int `json:"lobby"` // Players logged in, connected or not
+	Queue int `json:"queue"` // Players waiting in matchmaking
+
+	GamesCreated  int64 `json:"games_created"`  // Since the server started
+	GamesFinished int64 `json:"games_finished"` // With a result, over all time
+	Timeouts      int64 `json:"timeouts"`       // Over all time
+	Forfeits      int64 `json:"forfeits"`       // Over all time
+}
+
+// healthResponse is the body served on /healthz
+type healthResponse struct {
+	Status string `json:"status"`
+	Games  int    `json:"games"`
+	Lobby  int    `json:"lobby"`
+	Queue  int    `json:"queue"`
+}
+
+// Stats returns the current load of the server and its counters
+func (srv *Server) Stats() ServerStats {
+	srv.mu.RLock()
+	stats := ServerStats{
+		Games: len(srv.gamesByCode),
+		Lobby: len(srv.lobby),
+		Queue: len(srv.matchmakingQueue),
+	}
+	srv.mu.RUnlock()
+
+	stats.GamesCreated = srv.metrics.gamesCreated.Load()
+	wins := srv.winStats.Snapshot()
+	stats.GamesFinished = int64(srv.gameTotals.Snapshot(time.Now()).AllTime.Games)
+	stats.Timeouts = int64(wins[lib.WinTimeout])
+	stats.Forfeits = int64(wins[lib.WinForfeit])
+	return stats
+}
+
+// handleHealth reports that the server is up along with how loaded it is
+func (srv *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	stats := srv.Stats()
+	w.Header().Set("Content-Type", "application/json")
+	response := healthResponse{Status: "ok", Games: stats.Games, Lobby: stats.Lobby, Queue: stats.Queue}
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Printf("Failed to write health: %v", err)
+	}
+}
+
+// handleMetrics serves the load of the server and the counters since it started as JSON
+func (srv *Server) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(srv.Stats()); err != nil {
+		log.Printf("Failed to write metrics: %v", err)
+	}
+}
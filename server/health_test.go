@@ -0,0 +1,73 @@
+// Copyright (c) 2025 Haute école d'ingénierie et d'architecture de Fribourg
+// SPDX-License-Identifier: Apache-2.0
+// Author: Marvin Egger marvin.egger@hotmail.ch
+// Created: 16.10.2026
+
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/marvinEgger/GOnnect4/server/lib"
+)
+
+// TestHandleHealth_ReportsLoad tests that the health check answers 200 with the current load
+func TestHandleHealth_ReportsLoad(t *testing.T) {
+	srv := NewServer()
+	defer srv.cancelFunc()
+
+	startTestGame(srv)
+	srv.handleJoinMatchmaking(loginTestPlayer(srv, "Carol"))
+
+	recorder := httptest.NewRecorder()
+	srv.handleHealth(recorder, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", recorder.Code)
+	}
+
+	var health healthResponse
+	if err := json.NewDecoder(recorder.Body).Decode(&health); err != nil {
+		t.Fatalf("Failed to decode health: %v", err)
+	}
+	want := healthResponse{Status: "ok", Games: 1, Lobby: 3, Queue: 1}
+	if health != want {
+		t.Errorf("Expected %+v, got %+v", want, health)
+	}
+
+	recorder = httptest.NewRecorder()
+	srv.handleHealth(recorder, httptest.NewRequest(http.MethodPost, "/healthz", nil))
+	if recorder.Code != http.StatusMethodNotAllowed {
+		t.Errorf("Expected 405 for POST, got %d", recorder.Code)
+	}
+}
+
+// TestHandleMetrics_CountsGames tests that created, finished and forfeited games are counted, aborted ones are not finished
+func TestHandleMetrics_CountsGames(t *testing.T) {
+	srv := NewServer()
+	defer srv.cancelFunc()
+
+	mover, _ := startTestGame(srv)
+	srv.handleForfeit(mover)
+
+	// Leaving a game nobody joined aborts it without a result
+	carol := loginTestPlayer(srv, "Carol")
+	srv.handleCreateGame(carol, lib.CreateGameData{})
+	srv.handleForfeit(carol)
+
+	recorder := httptest.NewRecorder()
+	srv.handleMetrics(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", recorder.Code)
+	}
+
+	var stats ServerStats
+	if err := json.NewDecoder(recorder.Body).Decode(&stats); err != nil {
+		t.Fatalf("Failed to decode metrics: %v", err)
+	}
+	if stats.GamesCreated != 2 || stats.GamesFinished != 1 || stats.Forfeits != 1 || stats.Timeouts != 0 {
+		t.Errorf("Unexpected counters: %+v", stats)
+	}
+}
@@ -30,6 +30,7 @@ func (srv *Server) getPlayerInfos(game *lib.Game) [2]lib.PlayerInfo {
 				ID:        p.ID,
 				Username:  p.Username,
 				Connected: p.IsConnected(),
+				IsBot:     p.Bot != nil,
 			}
 		}
 	}
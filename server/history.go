@@ -0,0 +1,43 @@
+// Copyright (c) 2025 Haute école d'ingénierie et d'architecture de Fribourg
+// SPDX-License-Identifier: Apache-2.0
+// Author: Marvin Egger marvin.egger@hotmail.ch
+// Created: 16.10.2026
+
+package main
+
+import (
+	"log"
+
+	"github.com/marvinEgger/GOnnect4/server/lib"
+)
+
+// openHistory opens the history file at path, an empty path or an unusable file keeps the history in memory
+func openHistory(path string, limit int) *lib.HistoryStore {
+	if path == "" {
+		return lib.NewHistoryStore(limit)
+	}
+
+	store, err := lib.OpenHistoryStore(path, limit)
+	if err != nil {
+		log.Printf("Keeping the history in memory, cannot open %q: %v", path, err)
+		return lib.NewHistoryStore(limit)
+	}
+	return store
+}
+
+// handleHistoryRequest sends a logged in player its last finished games
+func (srv *Server) handleHistoryRequest(client *lib.Client, data lib.HistoryRequestData) {
+	srv.mu.RLock()
+	player := srv.lobby[client.PlayerID]
+	srv.mu.RUnlock()
+
+	if player == nil {
+		srv.sendError(client, lib.ErrPlayerNotFound)
+		return
+	}
+
+	player.Send(lib.Message{
+		Type: lib.MsgHistoryResponse,
+		Data: lib.HistoryResponseData{Games: srv.history.Recent(lib.HistoryKey(player.Username), data.Limit)},
+	})
+}
@@ -0,0 +1,133 @@
+// Copyright (c) 2025 Haute école d'ingénierie et d'architecture de Fribourg
+// SPDX-License-Identifier: Apache-2.0
+// Author: Marvin Egger marvin.egger@hotmail.ch
+// Created: 16.10.2026
+
+package main
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/marvinEgger/GOnnect4/server/lib"
+)
+
+const (
+	defaultLeaderboardSize = 10
+	maxLeaderboardSize     = 50 // Largest page a single request may ask for
+)
+
+// leaderboardEntry is one ranked player, IDs stay private
+type leaderboardEntry struct {
+	Rank     int    `json:"rank"`
+	Username string `json:"username"`
+	Tag      string `json:"tag"`
+	Wins     int    `json:"wins"`
+}
+
+// leaderboardResponse is the body served on /api/leaderboard
+type leaderboardResponse struct {
+	Players []leaderboardEntry `json:"players"`
+	Offset  int                `json:"offset"`
+	Total   int                `json:"total"` // Players online, across all pages
+}
+
+// handleLeaderboard serves the online players ranked by games won this session
+// Players only live as long as their session, so the board lists who is online right now
+// The page is chosen with ?limit= (at most maxLeaderboardSize) and ?offset=
+func (srv *Server) handleLeaderboard(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	limit, ok := queryInt(r, "limit", defaultLeaderboardSize)
+	if !ok || limit < 1 {
+		http.Error(w, "invalid limit", http.StatusBadRequest)
+		return
+	}
+	limit = min(limit, maxLeaderboardSize)
+
+	offset, ok := queryInt(r, "offset", 0)
+	if !ok || offset < 0 {
+		http.Error(w, "invalid offset", http.StatusBadRequest)
+		return
+	}
+
+	ranking := srv.rankOnlinePlayers()
+	page := ranking[min(offset, len(ranking)):min(offset+limit, len(ranking))]
+
+	w.Header().Set("Content-Type", "application/json")
+	response := leaderboardResponse{Players: page, Offset: offset, Total: len(ranking)}
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Printf("Failed to write leaderboard: %v", err)
+	}
+}
+
+// rankOnlinePlayers sorts the connected players by wins, ties by username
+func (srv *Server) rankOnlinePlayers() []leaderboardEntry {
+	ranked := srv.rankConnected((*lib.Player).GetWins)
+	ranking := make([]leaderboardEntry, len(ranked))
+	for i, entry := range ranked {
+		ranking[i] = leaderboardEntry{Rank: entry.rank, Username: entry.username, Tag: entry.tag, Wins: entry.score}
+	}
+	return ranking
+}
+
+// rankedPlayer is a connected player with the score they were ranked by
+type rankedPlayer struct {
+	rank     int
+	username string
+	tag      string
+	score    int
+}
+
+// rankConnected sorts the connected players by the given score, highest first, ties by username
+// Players with the same score share a rank
+func (srv *Server) rankConnected(score func(*lib.Player) int) []rankedPlayer {
+	srv.mu.RLock()
+	ranking := make([]rankedPlayer, 0, len(srv.lobby))
+	for _, player := range srv.lobby {
+		if !player.IsConnected() {
+			continue
+		}
+		ranking = append(ranking, rankedPlayer{
+			username: player.Username,
+			tag:      player.Tag(),
+			score:    score(player),
+		})
+	}
+	srv.mu.RUnlock()
+
+	sort.Slice(ranking, func(i, j int) bool {
+		if ranking[i].score != ranking[j].score {
+			return ranking[i].score > ranking[j].score
+		}
+		if ranking[i].username != ranking[j].username {
+			return ranking[i].username < ranking[j].username
+		}
+		return ranking[i].tag < ranking[j].tag
+	})
+
+	for i := range ranking {
+		ranking[i].rank = i + 1
+		if i > 0 && ranking[i].score == ranking[i-1].score {
+			ranking[i].rank = ranking[i-1].rank
+		}
+	}
+	return ranking
+}
+
+// queryInt reads an integer query parameter, fallback when it is absent
+func queryInt(r *http.Request, name string, fallback int) (int, bool) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return fallback, true
+	}
+	n, err := strconv.Atoi(value)
+	return n, err == nil
+}
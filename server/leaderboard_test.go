@@ -0,0 +1,89 @@
+// Copyright (c) 2025 Haute école d'ingénierie et d'architecture de Fribourg
+// SPDX-License-Identifier: Apache-2.0
+// Author: Marvin Egger marvin.egger@hotmail.ch
+// Created: 16.10.2026
+
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// fetchLeaderboard requests the leaderboard with a query string and decodes it
+func fetchLeaderboard(t *testing.T, srv *Server, query string) (int, leaderboardResponse) {
+	t.Helper()
+	recorder := httptest.NewRecorder()
+	srv.handleLeaderboard(recorder, httptest.NewRequest(http.MethodGet, "/api/leaderboard"+query, nil))
+
+	var board leaderboardResponse
+	if recorder.Code == http.StatusOK {
+		if err := json.NewDecoder(recorder.Body).Decode(&board); err != nil {
+			t.Fatalf("Failed to decode leaderboard: %v", err)
+		}
+	}
+	return recorder.Code, board
+}
+
+// TestHandleLeaderboard_RanksOnlinePlayers tests the order by wins, shared ranks and offline players
+func TestHandleLeaderboard_RanksOnlinePlayers(t *testing.T) {
+	srv := NewServer()
+	defer srv.cancelFunc()
+
+	wins := map[string]int{"Alice": 1, "Bob": 3, "Carol": 1, "Dave": 5}
+	for name, count := range wins {
+		player := srv.lobby[loginTestPlayer(srv, name).PlayerID]
+		for i := 0; i < count; i++ {
+			player.RecordWin()
+		}
+		if name == "Dave" {
+			player.SetSender(nil)
+		}
+	}
+
+	code, board := fetchLeaderboard(t, srv, "")
+	if code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", code)
+	}
+	if board.Total != 3 {
+		t.Fatalf("Expected the 3 online players, got %d", board.Total)
+	}
+
+	want := []struct {
+		rank     int
+		username string
+	}{{1, "Bob"}, {2, "Alice"}, {2, "Carol"}}
+	for i, entry := range board.Players {
+		if entry.Rank != want[i].rank || entry.Username != want[i].username {
+			t.Errorf("Entry %d: expected #%d %s, got #%d %s", i, want[i].rank, want[i].username, entry.Rank, entry.Username)
+		}
+	}
+}
+
+// TestHandleLeaderboard_Pages tests limit, offset and invalid parameters
+func TestHandleLeaderboard_Pages(t *testing.T) {
+	srv := NewServer()
+	defer srv.cancelFunc()
+
+	for _, name := range []string{"Alice", "Bob", "Carol"} {
+		loginTestPlayer(srv, name)
+	}
+
+	_, board := fetchLeaderboard(t, srv, "?limit=2&offset=1")
+	if len(board.Players) != 2 || board.Players[0].Username != "Bob" || board.Offset != 1 {
+		t.Errorf("Expected Bob and Carol from offset 1, got %+v", board)
+	}
+
+	_, board = fetchLeaderboard(t, srv, "?offset=10")
+	if len(board.Players) != 0 || board.Total != 3 {
+		t.Errorf("Expected an empty page past the end, got %+v", board)
+	}
+
+	for _, query := range []string{"?limit=0", "?limit=x", "?offset=-1"} {
+		if code, _ := fetchLeaderboard(t, srv, query); code != http.StatusBadRequest {
+			t.Errorf("%s: expected 400, got %d", query, code)
+		}
+	}
+}
@@ -5,30 +5,88 @@
 
 package lib
 
+import "hash/fnv"
+
+// Dimensions of the classic board, used when a game does not choose its own
 const (
 	Rows      = 6
 	Cols      = 7
 	WinLength = 4
 )
 
+// Limits of a configurable board
+const (
+	MinBoardSize = 4
+	MaxBoardSize = 12
+)
+
+// BoardConfig sets the dimensions of a board and the line length that wins
+type BoardConfig struct {
+	Rows      int `json:"rows"`
+	Cols      int `json:"cols"`
+	WinLength int `json:"win_length"`
+}
+
+// DefaultBoardConfig is the classic 6x7 board with four in a row
+var DefaultBoardConfig = BoardConfig{Rows: Rows, Cols: Cols, WinLength: WinLength}
+
+// Validate checks that the board fits the limits and that a winning line fits on it
+func (c BoardConfig) Validate() error {
+	if c.Rows < MinBoardSize || c.Rows > MaxBoardSize || c.Cols < MinBoardSize || c.Cols > MaxBoardSize {
+		return ErrInvalidBoardConfig
+	}
+	if c.WinLength < 3 || c.WinLength > max(c.Rows, c.Cols) {
+		return ErrInvalidBoardConfig
+	}
+	return nil
+}
+
 // Board represents the game board as a graph of connected nodes
 type Board struct {
 	nodes      [][]*Node
-	colHeights [Cols]int
+	colHeights []int
 	rows       int
 	cols       int
+	winLength  int
 }
 
-// NewBoard creates a new board and builds the node graph
+// NewBoard creates a classic 6x7 board and builds the node graph
 func NewBoard() *Board {
+	return NewBoardWithSize(Rows, Cols, WinLength)
+}
+
+// NewBoardWithSize creates a board of the given dimensions, the sizes are expected to pass BoardConfig.Validate
+func NewBoardWithSize(rows, cols, winLength int) *Board {
 	b := &Board{
-		rows: Rows,
-		cols: Cols,
+		colHeights: make([]int, cols),
+		rows:       rows,
+		cols:       cols,
+		winLength:  winLength,
 	}
 	b.buildGraph()
 	return b
 }
 
+// Rows returns the number of rows of the board
+func (b *Board) Rows() int {
+	return b.rows
+}
+
+// Cols returns the number of columns of the board
+func (b *Board) Cols() int {
+	return b.cols
+}
+
+// WinLength returns the number of aligned tokens that wins
+func (b *Board) WinLength() int {
+	return b.winLength
+}
+
+// Config returns the dimensions the board was created with
+func (b *Board) Config() BoardConfig {
+	return BoardConfig{Rows: b.rows, Cols: b.cols, WinLength: b.winLength}
+}
+
 // buildGraph creates all nodes and establishes neighbor relationships
 func (b *Board) buildGraph() {
 	// Create all nodes
@@ -105,9 +163,50 @@ func (b *Board) Play(col int, player Cell) (*Node, bool) {
 	return node, true
 }
 
+// undo takes the top token back out of a column, used by searches exploring moves in place
+func (b *Board) undo(col int) {
+	if col < 0 || col >= b.cols || b.colHeights[col] == 0 {
+		return
+	}
+	row := b.rows - b.colHeights[col]
+	b.nodes[row][col].SetOwner(CellEmpty)
+	b.colHeights[col]--
+}
+
+// Clone returns an independent board with the same tokens
+func (b *Board) Clone() *Board {
+	clone := NewBoardWithSize(b.rows, b.cols, b.winLength)
+	for row := 0; row < b.rows; row++ {
+		for col := 0; col < b.cols; col++ {
+			clone.nodes[row][col].SetOwner(b.nodes[row][col].Owner)
+		}
+	}
+	copy(clone.colHeights, b.colHeights)
+	return clone
+}
+
 // CheckWin checks if the last played node creates a winning condition
 func (b *Board) CheckWin(node *Node) bool {
-	return node.CheckWin(WinLength)
+	return node.CheckWin(b.winLength)
+}
+
+// WinningCells returns the coordinates of the line won by the last played node, nil if it did not win
+func (b *Board) WinningCells(node *Node) []LastMove {
+	line := node.WinningLine(b.winLength)
+	if line == nil {
+		return nil
+	}
+
+	cells := make([]LastMove, len(line))
+	for i, n := range line {
+		cells[i] = LastMove{Col: n.Col, Row: n.Row}
+	}
+	return cells
+}
+
+// WinningDirection returns the direction of the line won by the last played node
+func (b *Board) WinningDirection(node *Node) (Direction, bool) {
+	return node.WinningDirection(b.winLength)
 }
 
 // IsFull checks if the board is completely full
@@ -149,13 +248,25 @@ func (b *Board) Reset() {
 	}
 }
 
-// ToArray exports the board state as a 2D array
-func (b *Board) ToArray() [Rows][Cols]Cell {
-	var arr [Rows][Cols]Cell
+// ToArray exports the board state as rows of cells, top row first
+func (b *Board) ToArray() [][]Cell {
+	arr := make([][]Cell, b.rows)
 	for row := 0; row < b.rows; row++ {
+		arr[row] = make([]Cell, b.cols)
 		for col := 0; col < b.cols; col++ {
 			arr[row][col] = b.nodes[row][col].Owner
 		}
 	}
 	return arr
 }
+
+// CanonicalHash returns a hash of the cell owners read row by row, equal boards share the same hash
+func (b *Board) CanonicalHash() uint64 {
+	hash := fnv.New64a()
+	for row := 0; row < b.rows; row++ {
+		for col := 0; col < b.cols; col++ {
+			hash.Write([]byte{byte(b.nodes[row][col].Owner)})
+		}
+	}
+	return hash.Sum64()
+}
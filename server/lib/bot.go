@@ -0,0 +1,250 @@
+// Copyright (c) 2025 Haute école d'ingénierie et d'architecture de Fribourg
+// SPDX-License-Identifier: Apache-2.0
+// Author: Marvin Egger marvin.egger@hotmail.ch
+// Created: 16.10.2026
+
+package lib
+
+import (
+	"math"
+	"time"
+)
+
+// Bot pacing, replies are delayed so easy bots feel beatable and deep ones deliberate
+const (
+	BotThinkPerDepth = 150 * time.Millisecond
+	MaxBotThinkDelay = 1500 * time.Millisecond
+
+	// BotClock is long enough that a bot never flags
+	BotClock = 24 * time.Hour
+
+	// Search depths in plies, the deepest still replies well within the think delay
+	DefaultBotDepth = 5
+	MaxBotDepth     = 7
+)
+
+// Scores of the bot search, a win outweighs any evaluation and sooner wins score higher
+const (
+	botWinScore   = 1_000_000
+	botThreeScore = 5.0
+	botTwoScore   = 2.0
+	botCenterBias = 3.0
+)
+
+// windowDirections walk every winning line exactly once, from its first cell
+var windowDirections = [4]Direction{DirRight, DirDown, DirDownRight, DirDownLeft}
+
+// BotThinkDelay returns the artificial delay before a bot searching at depth replies
+func BotThinkDelay(depth int) time.Duration {
+	if depth < 1 {
+		depth = 1
+	}
+
+	delay := time.Duration(depth) * BotThinkPerDepth
+	if delay > MaxBotThinkDelay {
+		return MaxBotThinkDelay
+	}
+	return delay
+}
+
+// BotProfile selects the personality of a bot
+type BotProfile string
+
+const (
+	BotBalanced   BotProfile = "balanced"
+	BotAggressive BotProfile = "aggressive"
+	BotDefensive  BotProfile = "defensive"
+)
+
+// BotWeights scale the terms of the bot evaluation function
+// They only shade positions without a forced result, forced wins and losses are always scored first
+type BotWeights struct {
+	Threat float64 // Own open lines close to four
+	Block  float64 // Opponent open lines close to four
+	Center float64 // Tokens in the center columns
+}
+
+var botProfiles = map[BotProfile]BotWeights{
+	BotBalanced:   {Threat: 1.0, Block: 1.0, Center: 1.0},
+	BotAggressive: {Threat: 1.6, Block: 0.7, Center: 1.0},
+	BotDefensive:  {Threat: 0.7, Block: 1.6, Center: 1.0},
+}
+
+// Weights returns the evaluation weights of a profile, unknown profiles play balanced
+func (p BotProfile) Weights() BotWeights {
+	if weights, ok := botProfiles[p]; ok {
+		return weights
+	}
+	return botProfiles[BotBalanced]
+}
+
+// IsValid checks if a profile is one of the known personalities
+func (p BotProfile) IsValid() bool {
+	_, ok := botProfiles[p]
+	return ok
+}
+
+// botSender drops every message, a bot reads the game directly
+type botSender struct{}
+
+// Send ignores the message
+func (botSender) Send(Message) {}
+
+// NewBotPlayer creates the synthetic player seated for a bot
+// It always counts as connected, so the bot never pauses a game or waits for a reconnection
+func NewBotPlayer(bot *Bot) *Player {
+	player := NewPlayer("Computer", BotClock)
+	player.Bot = bot
+	player.sender = botSender{}
+	return player
+}
+
+// Bot picks moves with a minimax search, deeper bots play stronger
+type Bot struct {
+	Depth   int
+	Profile BotProfile
+}
+
+// NewBot creates a bot searching depth plies, out of range depths are clamped
+func NewBot(depth int, profile BotProfile) *Bot {
+	return &Bot{Depth: min(max(depth, 1), MaxBotDepth), Profile: profile}
+}
+
+// ChooseMove returns the column the bot plays as me, -1 if the board is full
+// The search runs on a copy, the board passed in is never modified
+func (bot *Bot) ChooseMove(b *Board, me Cell) int {
+	board := b.Clone()
+	weights := bot.Profile.Weights()
+
+	order := searchOrder(board.Cols())
+
+	best, bestScore := -1, math.Inf(-1)
+	alpha, beta := math.Inf(-1), math.Inf(1)
+	for _, col := range order {
+		node, ok := board.Play(col, me)
+		if !ok {
+			continue
+		}
+		score := -bot.negamax(board, node, opponentOf(me), bot.Depth-1, -beta, -alpha, order, weights)
+		board.undo(col)
+
+		if score > bestScore {
+			best, bestScore = col, score
+		}
+		alpha = max(alpha, score)
+	}
+	return best
+}
+
+// negamax scores the position for toMove after the opponent played last, with alpha-beta pruning
+func (bot *Bot) negamax(board *Board, last *Node, toMove Cell, depth int, alpha, beta float64, order []int, weights BotWeights) float64 {
+	// The previous move won, the side to move lost, later losses hurt less
+	if board.CheckWin(last) {
+		return -float64(botWinScore + depth)
+	}
+	if board.IsFull() {
+		return 0
+	}
+	if depth == 0 {
+		return evaluate(board, toMove, weights)
+	}
+
+	best := math.Inf(-1)
+	for _, col := range order {
+		node, ok := board.Play(col, toMove)
+		if !ok {
+			continue
+		}
+		score := -bot.negamax(board, node, opponentOf(toMove), depth-1, -beta, -alpha, order, weights)
+		board.undo(col)
+
+		best = max(best, score)
+		alpha = max(alpha, score)
+		if alpha >= beta {
+			break
+		}
+	}
+	return best
+}
+
+// searchOrder lists the columns from the center out, they are most often best and prune the most
+func searchOrder(cols int) []int {
+	order := make([]int, 0, cols)
+	center := (cols - 1) / 2
+	order = append(order, center)
+	for offset := 1; len(order) < cols; offset++ {
+		if center-offset >= 0 {
+			order = append(order, center-offset)
+		}
+		if center+offset < cols {
+			order = append(order, center+offset)
+		}
+	}
+	return order
+}
+
+// evaluate scores a quiet position for me from its open winning lines and center control
+func evaluate(board *Board, me Cell, weights BotWeights) float64 {
+	opponent := opponentOf(me)
+	length := board.WinLength()
+	score := 0.0
+
+	for row := 0; row < board.Rows(); row++ {
+		for col := 0; col < board.Cols(); col++ {
+			start := board.GetNode(row, col)
+			if col == board.Cols()/2 {
+				switch start.Owner {
+				case me:
+					score += botCenterBias * weights.Center
+				case opponent:
+					score -= botCenterBias * weights.Center
+				}
+			}
+
+			for _, dir := range windowDirections {
+				mine, theirs, ok := countWindow(start, dir, me, length)
+				if !ok {
+					continue
+				}
+				// Lines holding both colors can never be completed
+				switch {
+				case theirs == 0:
+					score += windowScore(mine, length) * weights.Threat
+				case mine == 0:
+					score -= windowScore(theirs, length) * weights.Block
+				}
+			}
+		}
+	}
+	return score
+}
+
+// countWindow counts each side's tokens on the length cells from start along dir, ok false if the line leaves the board
+func countWindow(start *Node, dir Direction, me Cell, length int) (mine, theirs int, ok bool) {
+	node := start
+	for i := 0; i < length; i++ {
+		if node == nil {
+			return 0, 0, false
+		}
+		switch node.Owner {
+		case CellEmpty:
+		case me:
+			mine++
+		default:
+			theirs++
+		}
+		node = node.GetNeighbor(dir)
+	}
+	return mine, theirs, true
+}
+
+// windowScore values a winning line holding count tokens of one side only, one or two tokens short of complete
+func windowScore(count, length int) float64 {
+	switch count {
+	case length - 1:
+		return botThreeScore
+	case length - 2:
+		return botTwoScore
+	}
+	return 0
+}
@@ -0,0 +1,139 @@
+// Copyright (c) 2025 Haute école d'ingénierie et d'architecture de Fribourg
+// SPDX-License-Identifier: Apache-2.0
+// Author: Marvin Egger marvin.egger@hotmail.ch
+// Created: 16.10.2026
+
+package lib
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+// TestBotThinkDelay_Capped tests that the delay grows with depth without exceeding the cap
+func TestBotThinkDelay_Capped(t *testing.T) {
+	if BotThinkDelay(2) <= BotThinkDelay(1) {
+		t.Error("Deeper bots should think longer")
+	}
+	if delay := BotThinkDelay(42); delay != MaxBotThinkDelay {
+		t.Errorf("Expected delay capped at %v, got %v", MaxBotThinkDelay, delay)
+	}
+	if BotThinkDelay(0) != BotThinkPerDepth {
+		t.Error("Invalid depth should use the minimum delay")
+	}
+}
+
+// TestScheduleMove_CancelledOnGameEnd tests that a pending bot reply is dropped when the game ends
+func TestScheduleMove_CancelledOnGameEnd(t *testing.T) {
+	game := NewGame(time.Minute, 0)
+	game.AddPlayer(NewPlayer("Alice", 0))
+	game.AddPlayer(NewPlayer("Bob", 0))
+	defer game.Cleanup()
+
+	played := make(chan struct{}, 1)
+	game.ScheduleMove(20*time.Millisecond, func() { played <- struct{}{} })
+	game.Forfeit(0)
+
+	if game.HasScheduledMove() {
+		t.Error("Forfeit should cancel the scheduled move")
+	}
+
+	select {
+	case <-played:
+		t.Error("Scheduled move should not run after the game ended")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+// TestBotProfile_Weights tests that personalities shade threats and blocks differently
+func TestBotProfile_Weights(t *testing.T) {
+	aggressive := BotAggressive.Weights()
+	defensive := BotDefensive.Weights()
+
+	if aggressive.Threat <= aggressive.Block {
+		t.Error("Aggressive profile should favor its own threats")
+	}
+	if defensive.Block <= defensive.Threat {
+		t.Error("Defensive profile should favor blocking")
+	}
+	if BotProfile("reckless").IsValid() || BotProfile("reckless").Weights() != BotBalanced.Weights() {
+		t.Error("Unknown profiles should fall back to balanced")
+	}
+}
+
+// playColumns drops tokens in turn, player 0 first
+func playColumns(board *Board, cols ...int) {
+	for i, col := range cols {
+		board.Play(col, Cell(i%2)+CellPlayer0)
+	}
+}
+
+// TestChooseMove_TakesWin tests that the bot completes its own line of four
+func TestChooseMove_TakesWin(t *testing.T) {
+	board := NewBoard()
+	playColumns(board, 0, 6, 1, 6, 2)
+
+	// Player 1 to move must block, player 0 to move wins on the spot
+	if col := NewBot(DefaultBotDepth, BotBalanced).ChooseMove(board, CellPlayer0); col != 3 {
+		t.Errorf("Expected the winning column 3, got %d", col)
+	}
+}
+
+// TestChooseMove_BlocksLoss tests that the bot stops an immediate threat
+func TestChooseMove_BlocksLoss(t *testing.T) {
+	board := NewBoard()
+	playColumns(board, 0, 6, 1, 6, 2)
+
+	for _, profile := range []BotProfile{BotBalanced, BotAggressive, BotDefensive} {
+		if col := NewBot(DefaultBotDepth, profile).ChooseMove(board, CellPlayer1); col != 3 {
+			t.Errorf("%s: expected the block in column 3, got %d", profile, col)
+		}
+	}
+}
+
+// TestChooseMove_LeavesBoardUntouched tests that the search runs on a copy
+func TestChooseMove_LeavesBoardUntouched(t *testing.T) {
+	board := NewBoard()
+	playColumns(board, 3, 3, 2)
+	before := board.ToArray()
+
+	NewBot(MaxBotDepth, BotBalanced).ChooseMove(board, CellPlayer1)
+	if !reflect.DeepEqual(board.ToArray(), before) {
+		t.Error("ChooseMove should not change the board it is given")
+	}
+}
+
+// TestChooseMove_FullBoard tests that a full board has no move
+func TestChooseMove_FullBoard(t *testing.T) {
+	board := NewBoard()
+	for col := 0; col < Cols; col++ {
+		for row := 0; row < Rows; row++ {
+			board.Play(col, Cell((col/2+row)%2)+CellPlayer0)
+		}
+	}
+
+	if col := NewBot(DefaultBotDepth, BotBalanced).ChooseMove(board, CellPlayer0); col != -1 {
+		t.Errorf("Expected no move on a full board, got %d", col)
+	}
+}
+
+// TestNewBot_ClampsDepth tests that out of range depths are brought back in range
+func TestNewBot_ClampsDepth(t *testing.T) {
+	if depth := NewBot(0, BotBalanced).Depth; depth != 1 {
+		t.Errorf("Expected depth 1, got %d", depth)
+	}
+	if depth := NewBot(42, BotBalanced).Depth; depth != MaxBotDepth {
+		t.Errorf("Expected depth %d, got %d", MaxBotDepth, depth)
+	}
+}
+
+// TestChooseMove_CustomBoard tests that the bot searches the whole width of a larger board
+func TestChooseMove_CustomBoard(t *testing.T) {
+	board := NewBoardWithSize(8, 9, 5)
+	playColumns(board, 5, 5, 6, 6, 7, 7, 8)
+
+	if col := NewBot(3, BotBalanced).ChooseMove(board, CellPlayer0); col != 4 {
+		t.Errorf("Expected the bot to complete five in a row in column 4, got %d", col)
+	}
+}
@@ -7,6 +7,9 @@ package lib
 
 import (
 	"context"
+	"log"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/coder/websocket"
@@ -18,6 +21,13 @@ const (
 	pongWait       = 60 * time.Second
 	pingPeriod     = (pongWait * 9) / 10
 	sendBufferSize = 256
+
+	deadLetterInterval = time.Second // Min delay between two dead-letter reports per client
+	chatInterval       = time.Second // Min delay between two chat messages per client
+
+	// Token bucket for incoming messages, pings and pongs are handled by the websocket library and never count
+	messageBurst = 20 // Messages a client may send at once
+	messageRate  = 10 // Messages a client may send per second once the burst is spent
 )
 
 // Client handles the websocket connection and implements lib.Sender
@@ -26,6 +36,24 @@ type Client struct {
 	SendChan chan Message
 	PlayerID PlayerID
 	GameCode string
+	Version  int // Protocol version announced at login
+
+	// Code of the game watched as a spectator, empty when not spectating
+	Spectating string
+
+	// Boards are written as a flat row-major array when the client asked for it at login
+	flatBoard atomic.Bool
+
+	lastDeadLetterAt time.Time
+	lastChatAt       time.Time
+
+	messageTokens   float64
+	lastRefillAt    time.Time
+	messageThrottle bool // Messages are being dropped, the client was already told
+
+	// Closed by Shutdown to have the write pump flush and close the connection
+	stop     chan struct{}
+	stopOnce sync.Once
 }
 
 // NewClient creates a new client
@@ -33,6 +61,10 @@ func NewClient(conn *websocket.Conn) *Client {
 	return &Client{
 		Conn:     conn,
 		SendChan: make(chan Message, sendBufferSize),
+
+		messageTokens: messageBurst,
+		lastRefillAt:  time.Now(),
+		stop:          make(chan struct{}),
 	}
 }
 
@@ -51,6 +83,44 @@ func (c *Client) Send(msg Message) {
 	}
 }
 
+// AllowDeadLetter reports whether an unhandled message may be reported, at most once per interval
+func (c *Client) AllowDeadLetter() bool {
+	now := time.Now()
+	if now.Sub(c.lastDeadLetterAt) < deadLetterInterval {
+		return false
+	}
+	c.lastDeadLetterAt = now
+	return true
+}
+
+// AllowChat reports whether the client may send a chat message, at most once per interval
+func (c *Client) AllowChat() bool {
+	now := time.Now()
+	if now.Sub(c.lastChatAt) < chatInterval {
+		return false
+	}
+	c.lastChatAt = now
+	return true
+}
+
+// AllowMessage takes a token for an incoming message and reports whether it may be handled
+// report is true for the first dropped message of a burst, so the client is warned once rather than per message
+func (c *Client) AllowMessage() (allowed, report bool) {
+	now := time.Now()
+	c.messageTokens = min(messageBurst, c.messageTokens+now.Sub(c.lastRefillAt).Seconds()*messageRate)
+	c.lastRefillAt = now
+
+	if c.messageTokens >= 1 {
+		c.messageTokens--
+		c.messageThrottle = false
+		return true, false
+	}
+
+	report = !c.messageThrottle
+	c.messageThrottle = true
+	return false, report
+}
+
 // WritePump pumps messages from the hub to the websocket connection.
 func (c *Client) WritePump() {
 	ticker := time.NewTicker(pingPeriod)
@@ -70,10 +140,7 @@ func (c *Client) WritePump() {
 				return
 			}
 
-			ctx, cancel := context.WithTimeout(context.Background(), writeWait)
-			err := wsjson.Write(ctx, c.Conn, msg)
-			cancel()
-			if err != nil {
+			if err := c.write(msg); err != nil {
 				return
 			}
 
@@ -84,10 +151,56 @@ func (c *Client) WritePump() {
 				return
 			}
 			cancel()
+
+		case <-c.stop:
+			c.flush()
+			c.Conn.Close(websocket.StatusGoingAway, "Server shutting down")
+			return
+		}
+	}
+}
+
+// write sends one message on the connection, with a flat board when the client asked for it
+func (c *Client) write(msg Message) error {
+	if c.flatBoard.Load() {
+		flat, err := FlatBoardMessage(msg)
+		if err != nil {
+			log.Printf("Failed to flatten the board of a %q message: %v", msg.Type, err)
+		}
+		msg = flat
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), writeWait)
+	defer cancel()
+	return wsjson.Write(ctx, c.Conn, msg)
+}
+
+// flush writes the messages still queued, giving up at the first failure
+func (c *Client) flush() {
+	for {
+		select {
+		case msg, ok := <-c.SendChan:
+			if !ok || c.write(msg) != nil {
+				return
+			}
+		default:
+			return
 		}
 	}
 }
 
+// Shutdown has the write pump send the queued messages then close the connection as going away
+// The read loop fails once the connection is closed, so the usual disconnect cleanup runs
+// Safe to call more than once
+func (c *Client) Shutdown() {
+	c.stopOnce.Do(func() { close(c.stop) })
+}
+
+// SetFlatBoard chooses between nested and flat boards for the messages written to this client
+func (c *Client) SetFlatBoard(enabled bool) {
+	c.flatBoard.Store(enabled)
+}
+
 // Close closes the send channel
 func (c *Client) Close() {
 	close(c.SendChan)
@@ -0,0 +1,103 @@
+// Copyright (c) 2025 Haute école d'ingénierie et d'architecture de Fribourg
+// SPDX-License-Identifier: Apache-2.0
+// Author: Marvin Egger marvin.egger@hotmail.ch
+// Created: 16.10.2026
+
+package lib
+
+import (
+	"sync"
+	"time"
+)
+
+// delayedMessage is a message waiting in a DelayedFeed
+type delayedMessage struct {
+	due time.Time
+	msg Message
+}
+
+// DelayedFeed forwards messages in order after a fixed delay
+// Used to hold back what spectators see of a game so streams cannot be sniped
+type DelayedFeed struct {
+	mu      sync.Mutex
+	delay   time.Duration
+	deliver func(Message)
+	queue   []delayedMessage
+	timer   *time.Timer
+	stopped bool
+}
+
+// NewDelayedFeed creates a feed calling deliver for each message once delay has passed
+func NewDelayedFeed(delay time.Duration, deliver func(Message)) *DelayedFeed {
+	return &DelayedFeed{
+		delay:   delay,
+		deliver: deliver,
+	}
+}
+
+// Push queues a message, it is delivered right away when the feed has no delay
+func (f *DelayedFeed) Push(msg Message) {
+	f.mu.Lock()
+	if f.stopped {
+		f.mu.Unlock()
+		return
+	}
+	if f.delay <= 0 {
+		f.mu.Unlock()
+		f.deliver(msg)
+		return
+	}
+
+	f.queue = append(f.queue, delayedMessage{due: time.Now().Add(f.delay), msg: msg})
+	if f.timer == nil {
+		f.timer = time.AfterFunc(f.delay, f.flush)
+	}
+	f.mu.Unlock()
+}
+
+// Pending returns how many messages wait to be delivered
+func (f *DelayedFeed) Pending() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.queue)
+}
+
+// Stop drops every pending message, later pushes are ignored
+func (f *DelayedFeed) Stop() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.stopped = true
+	f.queue = nil
+	if f.timer != nil {
+		f.timer.Stop()
+		f.timer = nil
+	}
+}
+
+// flush delivers the messages that are due and waits for the next one
+func (f *DelayedFeed) flush() {
+	f.mu.Lock()
+	if f.stopped {
+		f.mu.Unlock()
+		return
+	}
+
+	now := time.Now()
+	due := 0
+	for due < len(f.queue) && !f.queue[due].due.After(now) {
+		due++
+	}
+	ready := f.queue[:due]
+	f.queue = f.queue[due:]
+
+	f.timer = nil
+	if len(f.queue) > 0 {
+		f.timer = time.AfterFunc(f.queue[0].due.Sub(now), f.flush)
+	}
+	f.mu.Unlock()
+
+	for _, entry := range ready {
+		f.deliver(entry.msg)
+	}
+}
@@ -0,0 +1,68 @@
+// Copyright (c) 2025 Haute école d'ingénierie et d'architecture de Fribourg
+// SPDX-License-Identifier: Apache-2.0
+// Author: Marvin Egger marvin.egger@hotmail.ch
+// Created: 16.10.2026
+
+package lib
+
+import (
+	"testing"
+	"time"
+)
+
+// TestDelayedFeed_NoDelay tests that a feed without delay delivers immediately
+func TestDelayedFeed_NoDelay(t *testing.T) {
+	var delivered []Message
+	feed := NewDelayedFeed(0, func(msg Message) { delivered = append(delivered, msg) })
+
+	feed.Push(Message{Type: MsgMove})
+
+	if len(delivered) != 1 {
+		t.Errorf("Expected immediate delivery, got %d messages", len(delivered))
+	}
+}
+
+// TestDelayedFeed_KeepsOrder tests that delayed messages arrive after the delay and in order
+func TestDelayedFeed_KeepsOrder(t *testing.T) {
+	received := make(chan Message, 3)
+	feed := NewDelayedFeed(20*time.Millisecond, func(msg Message) { received <- msg })
+	defer feed.Stop()
+
+	feed.Push(Message{Type: MsgGameStart})
+	feed.Push(Message{Type: MsgMove})
+	feed.Push(Message{Type: MsgGameOver})
+
+	if feed.Pending() != 3 {
+		t.Fatalf("Expected 3 pending messages, got %d", feed.Pending())
+	}
+
+	for _, want := range []MessageType{MsgGameStart, MsgMove, MsgGameOver} {
+		select {
+		case msg := <-received:
+			if msg.Type != want {
+				t.Errorf("Expected %s, got %s", want, msg.Type)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("Timed out waiting for %s", want)
+		}
+	}
+}
+
+// TestDelayedFeed_StopDropsPending tests that stopping a feed discards queued messages
+func TestDelayedFeed_StopDropsPending(t *testing.T) {
+	received := make(chan Message, 1)
+	feed := NewDelayedFeed(20*time.Millisecond, func(msg Message) { received <- msg })
+
+	feed.Push(Message{Type: MsgMove})
+	feed.Stop()
+	feed.Push(Message{Type: MsgMove})
+
+	select {
+	case <-received:
+		t.Error("Stopped feed should not deliver")
+	case <-time.After(50 * time.Millisecond):
+	}
+	if feed.Pending() != 0 {
+		t.Error("Stopped feed should not keep messages")
+	}
+}
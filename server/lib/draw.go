@@ -0,0 +1,61 @@
+// Copyright (c) 2025 Haute école d'ingénierie et d'architecture de Fribourg
+// SPDX-License-Identifier: Apache-2.0
+// Author: Marvin Egger marvin.egger@hotmail.ch
+// Created: 16.10.2026
+
+package lib
+
+// OfferDraw proposes to end the game as a draw, an offer made back to the other side agrees to it
+// It returns whether the game ended
+func (g *Game) OfferDraw(playerIdx int) (bool, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if err := g.checkInPlay(); err != nil {
+		return false, err
+	}
+
+	if g.DrawOffers[1-playerIdx] {
+		g.finishAsDraw(DrawAgreement)
+		return true, nil
+	}
+	g.DrawOffers[playerIdx] = true
+	return false, nil
+}
+
+// AcceptDraw ends the game as a draw agreed by both sides, the other side must have offered it
+func (g *Game) AcceptDraw(playerIdx int) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if err := g.checkDrawOffer(playerIdx); err != nil {
+		return err
+	}
+
+	g.finishAsDraw(DrawAgreement)
+	return nil
+}
+
+// DeclineDraw drops the draw offered by the other side, the game goes on
+func (g *Game) DeclineDraw(playerIdx int) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if err := g.checkDrawOffer(playerIdx); err != nil {
+		return err
+	}
+
+	g.DrawOffers = [2]bool{}
+	return nil
+}
+
+// checkDrawOffer makes sure the other side has a draw offer pending, expects g.mu to be held
+func (g *Game) checkDrawOffer(playerIdx int) error {
+	if err := g.checkInPlay(); err != nil {
+		return err
+	}
+	if !g.DrawOffers[1-playerIdx] {
+		return ErrNoDrawOffer
+	}
+	return nil
+}
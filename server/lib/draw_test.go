@@ -0,0 +1,67 @@
+// Copyright (c) 2025 Haute école d'ingénierie et d'architecture de Fribourg
+// SPDX-License-Identifier: Apache-2.0
+// Author: Marvin Egger marvin.egger@hotmail.ch
+// Created: 16.10.2026
+
+package lib
+
+import (
+	"testing"
+	"time"
+)
+
+// TestAcceptDraw_EndsGame tests that an accepted offer finishes the game as a draw by agreement
+func TestAcceptDraw_EndsGame(t *testing.T) {
+	game := NewGame(time.Minute, 0)
+	game.AddPlayer(NewPlayer("Alice", 0))
+	game.AddPlayer(NewPlayer("Bob", 0))
+	defer game.Cleanup()
+
+	if err := game.AcceptDraw(0); err != ErrNoDrawOffer {
+		t.Errorf("Expected ErrNoDrawOffer without an offer, got %v", err)
+	}
+	if agreed, err := game.OfferDraw(0); agreed || err != nil {
+		t.Fatalf("Expected a pending offer, got %v %v", agreed, err)
+	}
+	if err := game.AcceptDraw(0); err != ErrNoDrawOffer {
+		t.Errorf("A side cannot accept its own offer, got %v", err)
+	}
+
+	if err := game.AcceptDraw(1); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if game.Status != StatusFinished || game.Result != ResultDraw || game.DrawReason != DrawAgreement {
+		t.Errorf("Expected a draw by agreement, got %v %v %q", game.Status, game.Result, game.DrawReason)
+	}
+	if _, err := game.OfferDraw(0); err != ErrGameNotPlaying {
+		t.Errorf("Expected ErrGameNotPlaying after the game ended, got %v", err)
+	}
+}
+
+// TestOfferDraw_WithdrawnByMove tests that a move clears the offer and that crossing offers agree
+func TestOfferDraw_WithdrawnByMove(t *testing.T) {
+	game := NewGame(time.Minute, 0)
+	game.AddPlayer(NewPlayer("Alice", 0))
+	game.AddPlayer(NewPlayer("Bob", 0))
+	defer game.Cleanup()
+
+	mover := game.CurrentTurn
+	game.OfferDraw(mover)
+	game.Play(mover, 3)
+	if err := game.AcceptDraw(1 - mover); err != ErrNoDrawOffer {
+		t.Errorf("A move should withdraw the offer, got %v", err)
+	}
+
+	game.OfferDraw(mover)
+	if err := game.DeclineDraw(1 - mover); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if game.Status != StatusPlaying {
+		t.Fatal("A declined offer should not end the game")
+	}
+
+	game.OfferDraw(mover)
+	if agreed, err := game.OfferDraw(1 - mover); !agreed || err != nil {
+		t.Errorf("Crossing offers should agree to the draw, got %v %v", agreed, err)
+	}
+}
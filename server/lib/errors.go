@@ -9,12 +9,43 @@ import "errors"
 
 var (
 	ErrGameNotPlaying      = errors.New("game is not in playing state")
+	ErrGamePaused          = errors.New("game is paused until both players are connected")
 	ErrNotYourTurn         = errors.New("not your turn")
 	ErrInvalidMove         = errors.New("invalid move")
+	ErrColumnFull          = errors.New("column is full")
 	ErrGameNotFound        = errors.New("game not found")
 	ErrGameFull            = errors.New("game is full")
+	ErrGameNotReady        = errors.New("game cannot start before both sides are filled")
 	ErrPlayerNotFound      = errors.New("player not found")
 	ErrPlayerNotInGame     = errors.New("player not in game")
 	ErrPlayerAlreadyInGame = errors.New("player already in game")
 	ErrInvalidUsername     = errors.New("invalid username")
+	ErrInvalidResumeToken  = errors.New("invalid resume token")
+	ErrResumeTokenExpired  = errors.New("resume token expired, please log in again")
+	ErrReplayNotAllowed    = errors.New("replay is not allowed for this game")
+	ErrUnknownMessage      = errors.New("unknown message type")
+	ErrMalformedMessage    = errors.New("malformed message")
+	ErrInvalidChat         = errors.New("chat message is empty or too long")
+	ErrChatTooFast         = errors.New("you are sending messages too fast")
+	ErrRateLimited         = errors.New("too many messages, slow down")
+	ErrInvalidPrefs        = errors.New("invalid preferences")
+	ErrNotInSeries         = errors.New("game is not part of a series")
+	ErrFriendGamesDisabled = errors.New("friend games are disabled on this server")
+	ErrMatchmakingDisabled = errors.New("matchmaking is disabled on this server")
+	ErrChatDisabled        = errors.New("chat is disabled on this server")
+	ErrNoPuzzle            = errors.New("no puzzle in progress")
+	ErrNotInvited          = errors.New("this game is reserved for another player")
+	ErrPrivateGame         = errors.New("this game cannot be joined by code")
+	ErrInvalidBoardConfig  = errors.New("invalid board dimensions")
+	ErrInvalidTimeControl  = errors.New("invalid time control")
+	ErrNothingToUndo       = errors.New("there is no move of yours to take back")
+	ErrNoUndoRequest       = errors.New("no takeback was requested")
+	ErrUndoNotAllowed      = errors.New("takebacks are not available in this game")
+	ErrNoDrawOffer         = errors.New("no draw was offered")
+	ErrDrawNotAllowed      = errors.New("draw offers are not available in this game")
+	ErrTooManySubscribers  = errors.New("too many streams are open on this game")
+	ErrTooManySpectators   = errors.New("too many spectators are watching this game")
+	ErrSpectateOwnGame     = errors.New("you cannot spectate a game you are playing")
+	ErrPrivateSpectate     = errors.New("this game is private and cannot be watched")
+	ErrNoFeaturedGame      = errors.New("no games are in progress right now, check back soon")
 )
@@ -0,0 +1,91 @@
+// Copyright (c) 2025 Haute école d'ingénierie et d'architecture de Fribourg
+// SPDX-License-Identifier: Apache-2.0
+// Author: Marvin Egger marvin.egger@hotmail.ch
+// Created: 16.10.2026
+
+package lib
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+)
+
+// ErrInvalidFlatBoard is returned when a flat board does not match its dimensions
+var ErrInvalidFlatBoard = errors.New("flat board does not match the board dimensions")
+
+// FlattenBoard returns the cells of a board in row-major order, top row first
+func FlattenBoard(board [][]Cell) []Cell {
+	cells := make([]Cell, 0, len(board)*boardCols(board))
+	for row := range board {
+		cells = append(cells, board[row]...)
+	}
+	return cells
+}
+
+// UnflattenBoard rebuilds a board from cells in row-major order
+func UnflattenBoard(cells []Cell, rows, cols int) ([][]Cell, error) {
+	if rows <= 0 || cols <= 0 || len(cells) != rows*cols {
+		return nil, ErrInvalidFlatBoard
+	}
+
+	board := make([][]Cell, rows)
+	for row := range board {
+		board[row] = cells[row*cols : (row+1)*cols : (row+1)*cols]
+	}
+	return board, nil
+}
+
+// boardCols returns the width of a board, 0 when it has no rows
+func boardCols(board [][]Cell) int {
+	if len(board) == 0 {
+		return 0
+	}
+	return len(board[0])
+}
+
+// FlatBoardMessage rewrites the nested board of a message as a flat array with its rows and cols
+// Messages without a board are returned unchanged
+func FlatBoardMessage(msg Message) (Message, error) {
+	if msg.Data == nil {
+		return msg, nil
+	}
+
+	raw, err := json.Marshal(msg.Data)
+	if err != nil {
+		return msg, err
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		// Not an object, nothing to rewrite
+		return msg, nil
+	}
+
+	nested, ok := fields["board"]
+	if !ok {
+		return msg, nil
+	}
+
+	var board [][]Cell
+	if err := json.Unmarshal(nested, &board); err != nil {
+		return msg, err
+	}
+
+	// A []Cell would be encoded as a base64 string, write the numbers instead
+	cells := make([]int, 0, len(board)*boardCols(board))
+	for _, cell := range FlattenBoard(board) {
+		cells = append(cells, int(cell))
+	}
+
+	flat, err := json.Marshal(cells)
+	if err != nil {
+		return msg, err
+	}
+	fields["board"] = flat
+	fields["rows"] = json.RawMessage(strconv.Itoa(len(board)))
+	fields["cols"] = json.RawMessage(strconv.Itoa(boardCols(board)))
+
+	msg.Data = fields
+	return msg, nil
+}
@@ -0,0 +1,79 @@
+// Copyright (c) 2025 Haute école d'ingénierie et d'architecture de Fribourg
+// SPDX-License-Identifier: Apache-2.0
+// Author: Marvin Egger marvin.egger@hotmail.ch
+// Created: 16.10.2026
+
+package lib
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+// TestFlattenBoard_RoundTrip tests that flattening and rebuilding a board is lossless
+func TestFlattenBoard_RoundTrip(t *testing.T) {
+	board := NewBoard().ToArray()
+	board[Rows-1][0] = CellPlayer0
+	board[Rows-1][1] = CellPlayer1
+	board[0][Cols-1] = CellPlayer0
+
+	cells := FlattenBoard(board)
+	if len(cells) != Rows*Cols {
+		t.Fatalf("Expected %d cells, got %d", Rows*Cols, len(cells))
+	}
+	if cells[(Rows-1)*Cols+1] != CellPlayer1 {
+		t.Error("Cells should be in row-major order")
+	}
+
+	rebuilt, err := UnflattenBoard(cells, Rows, Cols)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(rebuilt, board) {
+		t.Error("Rebuilt board should match the original")
+	}
+
+	if _, err := UnflattenBoard(cells[1:], Rows, Cols); err != ErrInvalidFlatBoard {
+		t.Errorf("Expected ErrInvalidFlatBoard for a short board, got %v", err)
+	}
+}
+
+// TestFlatBoardMessage_RewritesBoard tests that only the board field of a message changes
+func TestFlatBoardMessage_RewritesBoard(t *testing.T) {
+	const rows, cols = 8, 9
+	board := NewBoardWithSize(rows, cols, 5).ToArray()
+	board[rows-1][3] = CellPlayer1
+	msg := Message{Type: MsgMove, Data: MoveData{Column: 3, Row: rows - 1, Board: board}}
+
+	flat, err := FlatBoardMessage(msg)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	raw, err := json.Marshal(flat.Data)
+	if err != nil {
+		t.Fatalf("Failed to encode: %v", err)
+	}
+	var decoded struct {
+		Column int   `json:"column"`
+		Board  []int `json:"board"`
+		Rows   int   `json:"rows"`
+		Cols   int   `json:"cols"`
+	}
+	if err := json.Unmarshal(raw, &decoded); err != nil {
+		t.Fatalf("Expected a flat board, got %s", raw)
+	}
+
+	if decoded.Column != 3 || decoded.Rows != rows || decoded.Cols != cols {
+		t.Errorf("Unexpected fields: %s", raw)
+	}
+	if len(decoded.Board) != rows*cols || decoded.Board[(rows-1)*cols+3] != int(CellPlayer1) {
+		t.Errorf("Unexpected flat board: %v", decoded.Board)
+	}
+
+	plain := Message{Type: MsgStats, Data: StatsData{Wins: 1}}
+	if unchanged, _ := FlatBoardMessage(plain); !reflect.DeepEqual(unchanged, plain) {
+		t.Error("Messages without a board should be left unchanged")
+	}
+}
@@ -8,6 +8,7 @@ package lib
 import (
 	"crypto/rand"
 	"encoding/hex"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -24,6 +25,11 @@ const (
 	CellPlayer1
 )
 
+// MarshalJSON writes a cell as a number, so rows of cells are not encoded as base64 strings
+func (c Cell) MarshalJSON() ([]byte, error) {
+	return strconv.AppendUint(nil, uint64(c), 10), nil
+}
+
 // GameStatus represents the current state of a game
 type GameStatus uint8
 
@@ -43,45 +49,144 @@ const (
 	ResultDraw
 )
 
+// DrawReason explains why a game ended in a draw
+type DrawReason string
+
+const (
+	DrawNone       DrawReason = ""
+	DrawBoardFull  DrawReason = "board_full"
+	DrawAgreement  DrawReason = "agreement"
+	DrawStalemate  DrawReason = "stalemate"
+	DrawMoveCap    DrawReason = "move_cap"
+	DrawRepetition DrawReason = "repetition"
+)
+
+// RepetitionLimit is how often a position may occur before the game is drawn
+const RepetitionLimit = 3
+
+// ReconnectBuffer is the least time left to a player who reconnects on their turn
+const ReconnectBuffer = 5 * time.Second
+
+// positionKey identifies a position together with the side to move
+type positionKey struct {
+	hash   uint64
+	toMove int
+}
+
 // LastMove represents the coordinates of the last move
 type LastMove struct {
 	Col int `json:"col"`
 	Row int `json:"row"`
 }
 
+// playedMove is a move of the round with what it changed besides the board, so Undo can restore it
+type playedMove struct {
+	LastMove
+	increment time.Duration // Added to the mover's clock
+	timing    MoveTiming    // Think times of the mover before the move
+}
+
 // Game represents a Connect 4 game session
 type Game struct {
-	mu     sync.RWMutex
-	Code   string
-	Board  *Board
-	Status GameStatus
-	Result GameResult
-
-	Players      [2]*Player
+	mu         sync.RWMutex
+	Code       string
+	Board      *Board
+	Status     GameStatus
+	Result     GameResult
+	DrawReason DrawReason // Set when Result is ResultDraw
+	WinMethod  WinMethod  // Set when a side won
+
+	// Cells of the line that won the round, nil unless it was won on the board
+	WinningCells []LastMove
+
+	Players      [2]*Player // Member currently controlling each side
+	Sides        [2]Side    // All members of each side, Players[i] is Sides[i].Active()
+	TeamSize     int        // Members per side, 1 for classic games
 	CurrentTurn  int
 	MoveCount    int
 	LastPlayedAt time.Time
 	CreatedAt    time.Time
 	LastMove     *LastMove
+	moves        []playedMove // Moves of the round in order, popped by Undo
 
 	ReplayRequests [2]bool
+	UndoRequests   [2]bool       // Takeback asked by the side that made the last move, cleared by the next move
+	DrawOffers     [2]bool       // Draw offered by each side, withdrawn by the next move
+	ReplayKeeps    [2]bool       // Whether each replay request asks to keep the seats
+	Timing         [2]MoveTiming // Think times of each side, used to flag bots
+	AllowReplay    bool          // False for matchmaking games to avoid farming rematches
+	Ranked         bool          // True for matchmaking games, leaving them early is penalized
+	Rated          bool          // Ratings were already moved for this round
+	Public         bool          // False for matchmaking games, only their matched players may join by code
+
+	// Only set for games where moves can be taken back, drops alone never repeat a position
+	DetectRepetition bool
+	positionCounts   map[positionKey]int
+
+	// Spectators see the game this far behind the players, 0 for live
+	SpectatorDelay time.Duration
+
+	// Players watching over their websocket, read-only, guarded by mu
+	spectators map[PlayerID]*Player
+
+	// Holds what the spectators see back by SpectatorDelay, created on the first broadcast
+	spectatorFeed *DelayedFeed
+
+	// Best-of series of friend games, Score follows the sides when they swap
+	BestOf       int // Rounds of the series, 0 or 1 for a single game
+	Score        [2]int
+	RoundsPlayed int
+	ClockBank    [2]time.Duration // Clock left at the end of each round, summed per side
+	TieBreak     SeriesTieBreak   // Applied when the rounds run out on an even score
+	SeriesOver   bool             // A side won the majority or the match was resigned
+	SeriesResult GameResult       // Set once the series is over, ResultDraw for a shared victory
+
+	// Friend games may be reserved for one username, best-effort since usernames are not unique
+	Invite string
+
+	// How long the game is kept once idle or abandoned, set from the game mode, 0 for the server default
+	ReconnectGrace time.Duration
+
+	// Filling both sides does not start the game, the creator calls Start itself
+	ManualStart bool
+
+	// Friend games may wait for a disconnected player instead of running their clock
+	PauseOnDisconnect bool
+	Paused            bool
+
+	// MoveCount+1 of the turn that already got a reconnect buffer, 0 for none
+	bufferedTurn int
 
 	// Timer management
 	InitialClock  time.Duration // Store initial clock for resets
+	Increment     time.Duration // Added to the mover's clock after each move that does not end the game
 	TimeRemaining [2]time.Duration
 	TurnStartedAt time.Time
 	Timer         *time.Timer
 	TimerCallback func(string, int) // Called when timer expires with (gameCode, loserIdx)
+
+	// Delayed reply of a bot, cancelled when the game ends first
+	MoveTimer *time.Timer
 }
 
-// NewGame creates a new game with a random code
-func NewGame(initialClock time.Duration) *Game {
+// NewGame creates a new game on the classic board with a random code
+// Each move adds increment to the clock of the player who made it, 0 for none
+func NewGame(initialClock, increment time.Duration) *Game {
+	return NewGameWithBoard(initialClock, increment, DefaultBoardConfig)
+}
+
+// NewGameWithBoard creates a new game on a board of the given dimensions, the config is expected to be valid
+func NewGameWithBoard(initialClock, increment time.Duration, config BoardConfig) *Game {
 	return &Game{
 		Code:          randomCode(codeLength),
-		Board:         NewBoard(),
+		Board:         NewBoardWithSize(config.Rows, config.Cols, config.WinLength),
 		Status:        StatusWaiting,
+		TeamSize:      1,
+		AllowReplay:   true,
+		Public:        true,
 		CreatedAt:     time.Now(),
 		InitialClock:  initialClock,
+		Increment:     increment,
 		TimeRemaining: [2]time.Duration{initialClock, initialClock},
 	}
 }
@@ -94,24 +199,72 @@ func (game *Game) AddPlayer(player *Player) bool {
 	if game.Status != StatusWaiting {
 		return false
 	}
+	if game.Sides[0].Has(player.ID) || game.Sides[1].Has(player.ID) {
+		return false
+	}
 
-	for i := range game.Players {
-		if game.Players[i] == nil {
-			game.Players[i] = player
-			if i == 1 {
-				game.start()
-			}
-			return true
-		}
+	// Fill the side with fewer members first so teams stay balanced
+	side := 0
+	if game.Sides[1].Size() < game.Sides[0].Size() {
+		side = 1
+	}
+	if game.Sides[side].Size() >= game.teamSize() {
+		return false
 	}
 
-	return false
+	game.Sides[side].Add(player)
+	if game.Players[side] == nil {
+		game.Players[side] = game.Sides[side].Active()
+	}
+
+	if game.sidesFull() && !game.ManualStart {
+		game.start()
+	}
+	return true
+}
+
+// Start begins a game created with ManualStart once its sides are full
+func (g *Game) Start() error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.Status != StatusWaiting || !g.sidesFull() {
+		return ErrGameNotReady
+	}
+	g.start()
+	return nil
+}
+
+// teamSize returns the number of members per side
+func (g *Game) teamSize() int {
+	if g.TeamSize < 1 {
+		return 1
+	}
+	return g.TeamSize
+}
+
+// sidesFull checks if both sides have all their members
+func (g *Game) sidesFull() bool {
+	return g.Sides[0].Size() == g.teamSize() && g.Sides[1].Size() == g.teamSize()
+}
+
+// rotateSide hands control of a side to its next member
+func (g *Game) rotateSide(side int) {
+	g.Sides[side].Rotate()
+	g.Players[side] = g.Sides[side].Active()
+}
+
+// unrotateSide gives control of a side back to the member who had it before rotateSide
+func (g *Game) unrotateSide(side int) {
+	g.Sides[side].RotateBack()
+	g.Players[side] = g.Sides[side].Active()
 }
 
 // start begins the game when both players are ready
 func (g *Game) start() {
 	// Randomize who starts
 	g.CurrentTurn = randomFirstPlayer()
+	g.recordPosition(g.CurrentTurn)
 
 	g.Status = StatusPlaying
 	g.TurnStartedAt = time.Now()
@@ -144,16 +297,19 @@ func (g *Game) startTimer() {
 	})
 }
 
-// stopTimer stops the timer and updates remaining time
-func (g *Game) stopTimer() {
-	if g.Timer != nil {
-		g.Timer.Stop()
-		elapsed := time.Since(g.TurnStartedAt)
-		g.TimeRemaining[g.CurrentTurn] -= elapsed
-		if g.TimeRemaining[g.CurrentTurn] < 0 {
-			g.TimeRemaining[g.CurrentTurn] = 0
-		}
+// stopTimer stops the timer and updates remaining time, returns false if no clock was running
+func (g *Game) stopTimer() bool {
+	if g.Timer == nil {
+		return false
+	}
+
+	g.Timer.Stop()
+	elapsed := time.Since(g.TurnStartedAt)
+	g.TimeRemaining[g.CurrentTurn] -= elapsed
+	if g.TimeRemaining[g.CurrentTurn] < 0 {
+		g.TimeRemaining[g.CurrentTurn] = 0
 	}
+	return true
 }
 
 // Play attempts to play a move in the given column
@@ -165,12 +321,24 @@ func (g *Game) Play(playerIdx, col int) error {
 		return ErrGameNotPlaying
 	}
 
+	if g.Paused {
+		return ErrGamePaused
+	}
+
 	if playerIdx != g.CurrentTurn {
 		return ErrNotYourTurn
 	}
 
+	// Reject bad columns before touching the clock, the turn simply goes on
+	if col < 0 || col >= g.Board.Cols() {
+		return ErrInvalidMove
+	}
+	if !g.Board.canPlay(col) {
+		return ErrColumnFull
+	}
+
 	// Stop timer and update time
-	g.stopTimer()
+	clockRan := g.stopTimer()
 
 	player := Cell(int(CellPlayer0) + playerIdx)
 	node, ok := g.Board.Play(col, player)
@@ -178,31 +346,44 @@ func (g *Game) Play(playerIdx, col int) error {
 		return ErrInvalidMove
 	}
 
+	timing := g.Timing[playerIdx]
+	g.Timing[playerIdx].Record(time.Since(g.TurnStartedAt))
+
 	g.MoveCount++
 	g.LastPlayedAt = time.Now()
 	g.LastMove = &LastMove{Col: node.Col, Row: node.Row}
-
-	// Check for win
-	if g.Board.CheckWin(node) {
-		g.Status = StatusFinished
-		g.Result = GameResult(int(ResultPlayer0Win) + playerIdx)
-		if g.Timer != nil {
-			g.Timer.Stop()
-		}
+	g.moves = append(g.moves, playedMove{LastMove: *g.LastMove, timing: timing})
+	g.UndoRequests = [2]bool{}
+	g.DrawOffers = [2]bool{}
+
+	// Check for win before the full board, the token filling the board may also connect four
+	if dir, won := g.Board.WinningDirection(node); won {
+		g.finish(GameResult(int(ResultPlayer0Win) + playerIdx))
+		g.WinMethod = WinMethodFor(dir)
+		g.WinningCells = g.Board.WinningCells(node)
 		return nil
 	}
 
 	// Check for draw
 	if g.Board.IsFull() {
-		g.Status = StatusFinished
-		g.Result = ResultDraw
-		if g.Timer != nil {
-			g.Timer.Stop()
-		}
+		g.finishAsDraw(DrawBoardFull)
+		return nil
+	}
+
+	// Check for a repeated position with the same side to move
+	if g.recordPosition(1 - playerIdx) {
+		g.finishAsDraw(DrawRepetition)
 		return nil
 	}
 
-	// Switch turn
+	// The increment only rewards moves the game goes on after
+	if clockRan {
+		g.TimeRemaining[playerIdx] += g.Increment
+		g.moves[len(g.moves)-1].increment = g.Increment
+	}
+
+	// Switch turn, team sides also pass control to their next member
+	g.rotateSide(g.CurrentTurn)
 	g.CurrentTurn = 1 - g.CurrentTurn
 	g.TurnStartedAt = time.Now()
 	g.startTimer()
@@ -210,39 +391,192 @@ func (g *Game) Play(playerIdx, col int) error {
 	return nil
 }
 
+// recordPosition counts the current position and reports whether it reached the repetition limit
+func (g *Game) recordPosition(toMove int) bool {
+	if !g.DetectRepetition {
+		return false
+	}
+
+	if g.positionCounts == nil {
+		g.positionCounts = make(map[positionKey]int)
+	}
+	key := positionKey{hash: g.Board.CanonicalHash(), toMove: toMove}
+	g.positionCounts[key]++
+	return g.positionCounts[key] >= RepetitionLimit
+}
+
+// Pause freezes the clock of the current player until Resume is called
+func (g *Game) Pause() bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.Status != StatusPlaying || g.Paused {
+		return false
+	}
+
+	g.stopTimer()
+	g.Timer = nil
+	g.Paused = true
+	return true
+}
+
+// Resume restarts the clock of the current player after a pause
+func (g *Game) Resume() bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.Status != StatusPlaying || !g.Paused {
+		return false
+	}
+
+	g.Paused = false
+	g.topUpClock(g.CurrentTurn)
+	g.TurnStartedAt = time.Now()
+	g.startTimer()
+	return true
+}
+
+// EnsureReconnectBuffer restarts the clock of a reconnected player to move with at least ReconnectBuffer left
+// The old timer is replaced under the game lock, so a timeout due right now cannot flag the player
+// The buffer is granted once per turn, dropping again on the same turn does not refill the clock
+func (g *Game) EnsureReconnectBuffer(playerIdx int) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.Status != StatusPlaying || g.Paused || g.CurrentTurn != playerIdx || g.Timer == nil {
+		return false
+	}
+	if g.bufferedTurn == g.MoveCount+1 {
+		return false
+	}
+
+	g.stopTimer()
+	toppedUp := g.topUpClock(playerIdx)
+	if toppedUp {
+		g.bufferedTurn = g.MoveCount + 1
+	}
+	g.TurnStartedAt = time.Now()
+	g.startTimer()
+	return toppedUp
+}
+
+// topUpClock raises a clock to ReconnectBuffer and reports whether it was below
+func (g *Game) topUpClock(playerIdx int) bool {
+	if g.TimeRemaining[playerIdx] >= ReconnectBuffer {
+		return false
+	}
+	g.TimeRemaining[playerIdx] = ReconnectBuffer
+	return true
+}
+
+// hasRunOut checks if the clock of the player to move is spent, stale timeouts fail this check
+func (g *Game) hasRunOut(playerIdx int) bool {
+	if g.Paused || g.CurrentTurn != playerIdx {
+		return false
+	}
+	return g.TimeRemaining[playerIdx]-time.Since(g.TurnStartedAt) <= 0
+}
+
+// IsTooFast checks if a player moves sooner than min after their turn started
+func (g *Game) IsTooFast(playerIdx int, min time.Duration) bool {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	if g.Status != StatusPlaying || g.Paused || g.CurrentTurn != playerIdx {
+		return false
+	}
+	return time.Since(g.TurnStartedAt) < min
+}
+
+// IsPaused checks if the game is waiting for a disconnected player
+func (g *Game) IsPaused() bool {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return g.Paused
+}
+
 // Forfeit handles a player forfeiting the game
 func (g *Game) Forfeit(loserIdx int) {
 	g.mu.Lock()
 	defer g.mu.Unlock()
+	g.forfeit(loserIdx, WinForfeit)
+}
+
+// ForfeitOnTime ends the game for a player whose clock ran out, in a series only the round is lost
+// A timeout fired just before the clock was restarted, by a move or a reconnect, is ignored
+func (g *Game) ForfeitOnTime(loserIdx int) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
 
+	if g.hasRunOut(loserIdx) {
+		g.forfeit(loserIdx, WinTimeout)
+	}
+}
+
+// forfeit ends the game in favor of the opponent, the caller holds the lock
+func (g *Game) forfeit(loserIdx int, method WinMethod) {
 	if g.Status != StatusPlaying {
 		return
 	}
 
+	opponentIdx := 1 - loserIdx
+	g.finish(GameResult(opponentIdx + 1))
+	g.WinMethod = method
+
+	// Forfeiting resigns the whole match, a timeout like ResignRound only scores the round
+	// finish already ended the series if that round decided it
+	if method != WinTimeout {
+		g.endSeries(GameResult(opponentIdx + 1))
+	}
+}
+
+// finish ends the game with a result, every way a game ends goes through here
+func (g *Game) finish(result GameResult) {
 	if g.Timer != nil {
 		g.Timer.Stop()
 	}
+	g.cancelScheduledMove()
 
-	opponentIdx := 1 - loserIdx
 	g.Status = StatusFinished
-	g.Result = GameResult(opponentIdx + 1)
+	g.Result = result
+	g.scoreRound(result)
+
+	// A game ended while paused can no longer resume, a rematch must not start paused
+	g.Paused = false
+}
+
+// finishAsDraw ends the game as a draw, whatever caused it
+func (g *Game) finishAsDraw(reason DrawReason) {
+	g.finish(ResultDraw)
+	g.DrawReason = reason
 }
 
 // RequestReplay marks a player's desire to replay
-func (g *Game) RequestReplay(playerIdx int) bool {
+func (g *Game) RequestReplay(playerIdx int, keepSeats bool) bool {
 	g.mu.Lock()
 	defer g.mu.Unlock()
 
-	if g.Status != StatusFinished {
+	if g.Status != StatusFinished || !g.AllowReplay {
 		return false
 	}
 
+	// A different seat choice is a counter-proposal the other player has to accept again
+	other := 1 - playerIdx
+	if g.ReplayRequests[other] && g.ReplayKeeps[other] != keepSeats {
+		g.ReplayRequests[other] = false
+	}
 	g.ReplayRequests[playerIdx] = true
+	g.ReplayKeeps[playerIdx] = keepSeats
 
-	// Both players agreed
+	// Both players agreed, a finished series starts over
 	if g.ReplayRequests[0] && g.ReplayRequests[1] {
+		if g.SeriesOver {
+			g.startSeries()
+		}
 		g.reset()
-		g.swapBeginningPlayer()
+		if !keepSeats {
+			g.swapBeginningPlayer()
+		}
 		return true
 	}
 
@@ -251,7 +585,13 @@ func (g *Game) RequestReplay(playerIdx int) bool {
 
 // swapBeginningPlayer changes the turn order
 func (g *Game) swapBeginningPlayer() {
-	g.Players[0], g.Players[1] = g.Players[1], g.Players[0]
+	g.Sides[0], g.Sides[1] = g.Sides[1], g.Sides[0]
+	g.Score[0], g.Score[1] = g.Score[1], g.Score[0]
+	g.ClockBank[0], g.ClockBank[1] = g.ClockBank[1], g.ClockBank[0]
+	for i := range g.Sides {
+		g.Sides[i].ResetRotation()
+		g.Players[i] = g.Sides[i].Active()
+	}
 }
 
 // reset resets the game for a new round
@@ -263,12 +603,24 @@ func (g *Game) reset() {
 	g.Board.Reset()
 	g.Status = StatusPlaying
 	g.Result = ResultNone
+	g.DrawReason = DrawNone
+	g.WinMethod = WinNone
+	g.WinningCells = nil
+	g.Rated = false
 	g.CurrentTurn = 0
 	g.MoveCount = 0
 	g.ReplayRequests = [2]bool{false, false}
+	g.ReplayKeeps = [2]bool{}
+	g.Timing = [2]MoveTiming{}
+	g.positionCounts = nil
+	g.recordPosition(g.CurrentTurn)
 	g.TurnStartedAt = time.Now()
 	g.LastPlayedAt = time.Now()
 	g.LastMove = nil
+	g.moves = nil
+	g.UndoRequests = [2]bool{}
+	g.DrawOffers = [2]bool{}
+	g.bufferedTurn = 0
 
 	// Reset timers to initial clock value
 	g.TimeRemaining[0] = g.InitialClock
@@ -286,7 +638,46 @@ func (g *Game) Cleanup() {
 		g.Timer.Stop()
 		g.Timer = nil
 	}
+	g.cancelScheduledMove()
 	g.TimerCallback = nil
+	if g.spectatorFeed != nil {
+		g.spectatorFeed.Stop()
+		g.spectatorFeed = nil
+	}
+}
+
+// ScheduleMove runs move after delay unless the game ends or another move is scheduled first
+func (g *Game) ScheduleMove(delay time.Duration, move func()) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.cancelScheduledMove()
+
+	var timer *time.Timer
+	timer = time.AfterFunc(delay, func() {
+		g.mu.Lock()
+		if g.MoveTimer == timer {
+			g.MoveTimer = nil
+		}
+		g.mu.Unlock()
+		move()
+	})
+	g.MoveTimer = timer
+}
+
+// HasScheduledMove checks if a delayed move is waiting to be played
+func (g *Game) HasScheduledMove() bool {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return g.MoveTimer != nil
+}
+
+// cancelScheduledMove stops a pending delayed move
+func (g *Game) cancelScheduledMove() {
+	if g.MoveTimer != nil {
+		g.MoveTimer.Stop()
+		g.MoveTimer = nil
+	}
 }
 
 // GetTimeRemaining returns remaining time for both players adjusted for current turn
@@ -295,7 +686,7 @@ func (g *Game) GetTimeRemaining() [2]time.Duration {
 	defer g.mu.RUnlock()
 
 	times := g.TimeRemaining
-	if g.Status == StatusPlaying {
+	if g.Status == StatusPlaying && !g.Paused {
 		// Adjust for current player's elapsed time
 		elapsed := time.Since(g.TurnStartedAt)
 		times[g.CurrentTurn] -= elapsed
@@ -311,8 +702,8 @@ func (g *Game) GetPlayerIndex(id PlayerID) int {
 	g.mu.RLock()
 	defer g.mu.RUnlock()
 
-	for i, p := range g.Players {
-		if p != nil && p.ID == id {
+	for i := range g.Sides {
+		if g.Sides[i].Has(id) {
 			return i
 		}
 	}
@@ -326,6 +717,36 @@ func (g *Game) GetPlayers() [2]*Player {
 	return g.Players
 }
 
+// GetMembers returns every player of both sides
+func (g *Game) GetMembers() []*Player {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	members := make([]*Player, 0, 2*g.teamSize())
+	for i := range g.Sides {
+		members = append(members, g.Sides[i].Members...)
+	}
+	return members
+}
+
+// GetTiming returns a snapshot of the think times of both sides
+func (g *Game) GetTiming() [2]MoveTiming {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return g.Timing
+}
+
+// GetMoves returns a copy of the moves of the round in order of play
+func (g *Game) GetMoves() []LastMove {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	moves := make([]LastMove, len(g.moves))
+	for i, move := range g.moves {
+		moves[i] = move.LastMove
+	}
+	return moves
+}
+
 // GetStatus returns the current game status
 func (g *Game) GetStatus() GameStatus {
 	g.mu.RLock()
@@ -333,16 +754,28 @@ func (g *Game) GetStatus() GameStatus {
 	return g.Status
 }
 
+// GetMoveCount returns the number of moves played this round
+func (g *Game) GetMoveCount() int {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return g.MoveCount
+}
+
 // HasPlayer checks if a player is in this game
 func (g *Game) HasPlayer(id PlayerID) bool {
 	return g.GetPlayerIndex(id) >= 0
 }
 
-// IsFull checks if the game has 2 players
+// IsInvited checks if a username may join, any username may when the game is not reserved
+func (g *Game) IsInvited(username string) bool {
+	return g.Invite == "" || strings.EqualFold(strings.TrimSpace(username), g.Invite)
+}
+
+// IsFull checks if both sides have all their players
 func (g *Game) IsFull() bool {
 	g.mu.RLock()
 	defer g.mu.RUnlock()
-	return g.Players[0] != nil && g.Players[1] != nil
+	return g.sidesFull()
 }
 
 // randomCode generates a random alphanumeric code
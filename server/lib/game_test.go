@@ -5,11 +5,15 @@
 
 package lib
 
-import "testing"
+import (
+	"reflect"
+	"testing"
+	"time"
+)
 
 // TestAddPlayer_FirstPlayer tests adding first player
 func TestAddPlayer_FirstPlayer(t *testing.T) {
-	game := NewGame(0)
+	game := NewGame(0, 0)
 	p1 := NewPlayer("Alice", 0)
 
 	ok := game.AddPlayer(p1)
@@ -28,7 +32,7 @@ func TestAddPlayer_FirstPlayer(t *testing.T) {
 
 // TestAddPlayer_SecondPlayer tests that game starts when second player joins
 func TestAddPlayer_SecondPlayer(t *testing.T) {
-	game := NewGame(0)
+	game := NewGame(0, 0)
 	p1 := NewPlayer("Alice", 0)
 	p2 := NewPlayer("Bob", 0)
 
@@ -50,7 +54,7 @@ func TestAddPlayer_SecondPlayer(t *testing.T) {
 
 // TestAddPlayer_GameFull tests that third player cannot join
 func TestAddPlayer_GameFull(t *testing.T) {
-	game := NewGame(0)
+	game := NewGame(0, 0)
 	p1 := NewPlayer("Alice", 0)
 	p2 := NewPlayer("Bob", 0)
 	p3 := NewPlayer("Charlie", 0)
@@ -66,7 +70,7 @@ func TestAddPlayer_GameFull(t *testing.T) {
 
 // TestAddPlayer_GameAlreadyPlaying tests adding player to active game
 func TestAddPlayer_GameAlreadyPlaying(t *testing.T) {
-	game := NewGame(0)
+	game := NewGame(0, 0)
 	p1 := NewPlayer("Alice", 0)
 	p2 := NewPlayer("Bob", 0)
 	p3 := NewPlayer("Charlie", 0)
@@ -82,9 +86,48 @@ func TestAddPlayer_GameAlreadyPlaying(t *testing.T) {
 	}
 }
 
+// TestAddPlayer_SamePlayerTwice tests that a player cannot take both sides
+func TestAddPlayer_SamePlayerTwice(t *testing.T) {
+	game := NewGame(0, 0)
+	p1 := NewPlayer("Alice", 0)
+
+	game.AddPlayer(p1)
+	if game.AddPlayer(p1) {
+		t.Error("Adding the same player twice should fail")
+	}
+	if game.Status != StatusWaiting {
+		t.Errorf("Expected status Waiting, got %v", game.Status)
+	}
+}
+
+// TestStart_ManualStart tests that a manual start game waits for Start once both sides are filled
+func TestStart_ManualStart(t *testing.T) {
+	game := NewGame(0, 0)
+	game.ManualStart = true
+	game.AddPlayer(NewPlayer("Alice", 0))
+
+	if err := game.Start(); err != ErrGameNotReady {
+		t.Errorf("Expected ErrGameNotReady with one player, got %v", err)
+	}
+
+	game.AddPlayer(NewPlayer("Bob", 0))
+	if game.Status != StatusWaiting {
+		t.Fatalf("Expected status Waiting before Start, got %v", game.Status)
+	}
+	if err := game.Start(); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	if game.Status != StatusPlaying {
+		t.Errorf("Expected status Playing, got %v", game.Status)
+	}
+	if err := game.Start(); err != ErrGameNotReady {
+		t.Errorf("Expected ErrGameNotReady on a running game, got %v", err)
+	}
+}
+
 // TestPlay_ValidMove tests a valid move
 func TestPlay_ValidMove(t *testing.T) {
-	game := NewGame(0)
+	game := NewGame(0, 0)
 	p1 := NewPlayer("Alice", 0)
 	p2 := NewPlayer("Bob", 0)
 	game.AddPlayer(p1)
@@ -111,7 +154,7 @@ func TestPlay_ValidMove(t *testing.T) {
 
 // TestPlay_NotYourTurn tests playing out of turn
 func TestPlay_NotYourTurn(t *testing.T) {
-	game := NewGame(0)
+	game := NewGame(0, 0)
 	p1 := NewPlayer("Alice", 0)
 	p2 := NewPlayer("Bob", 0)
 	game.AddPlayer(p1)
@@ -132,7 +175,7 @@ func TestPlay_NotYourTurn(t *testing.T) {
 
 // TestPlay_InvalidColumn tests playing in invalid column
 func TestPlay_InvalidColumn(t *testing.T) {
-	game := NewGame(0)
+	game := NewGame(0, 0)
 	p1 := NewPlayer("Alice", 0)
 	p2 := NewPlayer("Bob", 0)
 	game.AddPlayer(p1)
@@ -152,7 +195,7 @@ func TestPlay_InvalidColumn(t *testing.T) {
 
 // TestPlay_GameNotPlaying tests playing when game not in playing state
 func TestPlay_GameNotPlaying(t *testing.T) {
-	game := NewGame(0)
+	game := NewGame(0, 0)
 	p1 := NewPlayer("Alice", 0)
 	game.AddPlayer(p1)
 
@@ -165,7 +208,7 @@ func TestPlay_GameNotPlaying(t *testing.T) {
 
 // TestPlay_FullColumn tests playing in full column
 func TestPlay_FullColumn(t *testing.T) {
-	game := NewGame(0)
+	game := NewGame(0, 0)
 	p1 := NewPlayer("Alice", 0)
 	p2 := NewPlayer("Bob", 0)
 	game.AddPlayer(p1)
@@ -182,14 +225,14 @@ func TestPlay_FullColumn(t *testing.T) {
 
 	// Try to play in full column
 	err := game.Play(game.CurrentTurn, 0)
-	if err != ErrInvalidMove {
-		t.Errorf("Expected ErrInvalidMove for full column, got %v", err)
+	if err != ErrColumnFull {
+		t.Errorf("Expected ErrColumnFull for full column, got %v", err)
 	}
 }
 
 // TestPlay_WinDetection tests that win is detected
 func TestPlay_WinDetection(t *testing.T) {
-	game := NewGame(0)
+	game := NewGame(0, 0)
 	p1 := NewPlayer("Alice", 0)
 	p2 := NewPlayer("Bob", 0)
 	game.AddPlayer(p1)
@@ -225,7 +268,7 @@ func TestPlay_WinDetection(t *testing.T) {
 
 // TestPlay_TurnSwitching tests that turns alternate correctly
 func TestPlay_TurnSwitching(t *testing.T) {
-	game := NewGame(0)
+	game := NewGame(0, 0)
 	p1 := NewPlayer("Alice", 0)
 	p2 := NewPlayer("Bob", 0)
 	game.AddPlayer(p1)
@@ -255,7 +298,7 @@ func TestPlay_TurnSwitching(t *testing.T) {
 
 // TestPlay_LastMoveTracking tests that last move is recorded
 func TestPlay_LastMoveTracking(t *testing.T) {
-	game := NewGame(0)
+	game := NewGame(0, 0)
 	p1 := NewPlayer("Alice", 0)
 	p2 := NewPlayer("Bob", 0)
 	game.AddPlayer(p1)
@@ -276,3 +319,382 @@ func TestPlay_LastMoveTracking(t *testing.T) {
 		t.Errorf("Expected LastMove.Row = 5 (bottom), got %d", game.LastMove.Row)
 	}
 }
+
+// TestAddPlayer_TeamSides tests that team games alternate joining players between sides
+func TestAddPlayer_TeamSides(t *testing.T) {
+	game := NewGame(0, 0)
+	game.TeamSize = 2
+	players := []*Player{
+		NewPlayer("Alice", 0),
+		NewPlayer("Bob", 0),
+		NewPlayer("Carol", 0),
+		NewPlayer("Dave", 0),
+	}
+
+	for i, p := range players {
+		if !game.AddPlayer(p) {
+			t.Fatalf("Adding player %d should succeed", i+1)
+		}
+		if i < len(players)-1 && game.Status != StatusWaiting {
+			t.Fatal("Game should wait until both teams are full")
+		}
+	}
+
+	if game.Status != StatusPlaying {
+		t.Error("Game should start once both teams are full")
+	}
+
+	if game.GetPlayerIndex(players[2].ID) != 0 || game.GetPlayerIndex(players[3].ID) != 1 {
+		t.Error("Players should alternate between sides")
+	}
+
+	if len(game.GetMembers()) != 4 {
+		t.Errorf("Expected 4 members, got %d", len(game.GetMembers()))
+	}
+}
+
+// TestPlay_TeamRotation tests that a side hands control to its next member after a move
+func TestPlay_TeamRotation(t *testing.T) {
+	game := NewGame(0, 0)
+	game.TeamSize = 2
+	for _, name := range []string{"Alice", "Bob", "Carol", "Dave"} {
+		game.AddPlayer(NewPlayer(name, 0))
+	}
+
+	side := game.CurrentTurn
+	first := game.Players[side]
+
+	game.Play(side, 0)
+
+	if game.Players[side] == first {
+		t.Error("Side should rotate to its next member after a move")
+	}
+
+	if game.Players[side] != game.Sides[side].Members[1] {
+		t.Error("Active player should match the side rotation")
+	}
+}
+
+// TestPause_FreezesClock tests that a paused game rejects moves and keeps its clock
+func TestPause_FreezesClock(t *testing.T) {
+	game := NewGame(time.Minute, 0)
+	game.AddPlayer(NewPlayer("Alice", 0))
+	game.AddPlayer(NewPlayer("Bob", 0))
+	defer game.Cleanup()
+
+	if !game.Pause() {
+		t.Fatal("Playing game should pause")
+	}
+
+	frozen := game.GetTimeRemaining()
+	time.Sleep(10 * time.Millisecond)
+	if game.GetTimeRemaining() != frozen {
+		t.Error("Clock should not run while paused")
+	}
+
+	if err := game.Play(game.CurrentTurn, 0); err != ErrGamePaused {
+		t.Errorf("Expected ErrGamePaused, got %v", err)
+	}
+
+	if !game.Resume() || game.IsPaused() {
+		t.Error("Paused game should resume")
+	}
+}
+
+// TestPlay_RapidMovesFlagged tests that a game played implausibly fast flags both sides
+func TestPlay_RapidMovesFlagged(t *testing.T) {
+	game := NewGame(time.Minute, 0)
+	game.AddPlayer(NewPlayer("Alice", 0))
+	game.AddPlayer(NewPlayer("Bob", 0))
+	defer game.Cleanup()
+
+	// Five moves per side without completing a line
+	for _, col := range []int{0, 0, 1, 1, 2, 2, 4, 3, 5, 4} {
+		if err := game.Play(game.CurrentTurn, col); err != nil {
+			t.Fatalf("Move in column %d should succeed: %v", col, err)
+		}
+	}
+
+	if timing := game.GetTiming(); !timing[0].IsSuspicious() || !timing[1].IsSuspicious() {
+		t.Errorf("Both sides should be flagged, got %+v", timing)
+	}
+}
+
+// TestMoveTiming_HumanPace tests that normal think times are not flagged
+func TestMoveTiming_HumanPace(t *testing.T) {
+	var timing MoveTiming
+	for i := 0; i < MinMovesForTimingCheck; i++ {
+		timing.Record(2 * time.Second)
+	}
+	timing.Record(50 * time.Millisecond)
+
+	if timing.IsSuspicious() {
+		t.Error("Human paced moves should not be flagged")
+	}
+	if timing.Min != 50*time.Millisecond {
+		t.Errorf("Expected fastest move of 50ms, got %v", timing.Min)
+	}
+}
+
+// TestPlay_BoardFullDrawReason tests that a full board draw records its reason
+func TestPlay_BoardFullDrawReason(t *testing.T) {
+	game := NewGame(time.Minute, 0)
+	game.AddPlayer(NewPlayer("Alice", 0))
+	game.AddPlayer(NewPlayer("Bob", 0))
+	defer game.Cleanup()
+
+	// Fill columns in pairs with a shifted order so no line of four appears
+	for _, pair := range [][2]int{{0, 1}, {2, 3}, {4, 5}} {
+		for i := 0; i < Rows/2; i++ {
+			game.Play(game.CurrentTurn, pair[0])
+			game.Play(game.CurrentTurn, pair[1])
+		}
+		for i := 0; i < Rows/2; i++ {
+			game.Play(game.CurrentTurn, pair[1])
+			game.Play(game.CurrentTurn, pair[0])
+		}
+	}
+	for i := 0; i < Rows; i++ {
+		game.Play(game.CurrentTurn, 6)
+	}
+
+	if game.Status != StatusFinished || game.Result != ResultDraw {
+		t.Fatalf("Expected a draw, got status %v result %v", game.Status, game.Result)
+	}
+	if game.DrawReason != DrawBoardFull {
+		t.Errorf("Expected board full draw reason, got %q", game.DrawReason)
+	}
+}
+
+// TestPlay_FullBoardWin tests that the move filling the board is a win, not a draw, when it connects four
+func TestPlay_FullBoardWin(t *testing.T) {
+	game := NewGame(time.Minute, 0)
+	game.AddPlayer(NewPlayer("Alice", 0))
+	game.AddPlayer(NewPlayer("Bob", 0))
+	defer game.Cleanup()
+
+	// No line of four yet, X on the last free cell completes the diagonal down to row 3
+	layout := []string{
+		"XXOOOX.",
+		"OOXOXXX",
+		"OOXOXOX",
+		"XXOXOOO",
+		"OOOXOXO",
+		"XOXXOXX",
+	}
+	for row := Rows - 1; row >= 0; row-- {
+		for col, owner := range layout[row] {
+			switch owner {
+			case 'X':
+				game.Board.Play(col, CellPlayer0)
+			case 'O':
+				game.Board.Play(col, CellPlayer1)
+			}
+		}
+	}
+	game.CurrentTurn = 0
+
+	if err := game.Play(0, 6); err != nil {
+		t.Fatalf("Last move should be accepted, got %v", err)
+	}
+
+	if !game.Board.IsFull() {
+		t.Fatal("Board should be full after the last move")
+	}
+	if game.Status != StatusFinished || game.Result != ResultPlayer0Win {
+		t.Errorf("Expected a win for player 0, got status %v result %v", game.Status, game.Result)
+	}
+	if game.DrawReason != DrawNone {
+		t.Errorf("A win should not carry a draw reason, got %q", game.DrawReason)
+	}
+	if game.LastMove == nil || game.LastMove.Col != 6 || game.LastMove.Row != 0 {
+		t.Errorf("Expected last move at column 6 row 0, got %+v", game.LastMove)
+	}
+}
+
+// popTop removes the top token of a column, standing in for a reversible variant move
+func popTop(b *Board, col int) {
+	b.GetLastPlayedNode(col).SetOwner(CellEmpty)
+	b.colHeights[col]--
+}
+
+// TestPlay_RepetitionDraw tests that a position repeated three times with the same side to move is drawn
+func TestPlay_RepetitionDraw(t *testing.T) {
+	game := NewGame(time.Minute, 0)
+	game.DetectRepetition = true
+	game.AddPlayer(NewPlayer("Alice", 0))
+	game.AddPlayer(NewPlayer("Bob", 0))
+	defer game.Cleanup()
+
+	for move := 0; move < 2*RepetitionLimit-1; move++ {
+		if game.Status != StatusPlaying {
+			t.Fatalf("Game ended early after %d moves", move)
+		}
+		game.Play(game.CurrentTurn, 0)
+		popTop(game.Board, 0)
+	}
+
+	if game.Status != StatusFinished || game.Result != ResultDraw {
+		t.Fatalf("Expected a draw, got status %v result %v", game.Status, game.Result)
+	}
+	if game.DrawReason != DrawRepetition {
+		t.Errorf("Expected repetition draw reason, got %q", game.DrawReason)
+	}
+}
+
+// TestPlay_RepetitionOffByDefault tests that standard games do not track positions
+func TestPlay_RepetitionOffByDefault(t *testing.T) {
+	game := NewGame(time.Minute, 0)
+	game.AddPlayer(NewPlayer("Alice", 0))
+	game.AddPlayer(NewPlayer("Bob", 0))
+	defer game.Cleanup()
+
+	for move := 0; move < 2*RepetitionLimit; move++ {
+		game.Play(game.CurrentTurn, 0)
+		popTop(game.Board, 0)
+	}
+
+	if game.Status != StatusPlaying {
+		t.Error("Repetition should not end a standard game")
+	}
+	if game.positionCounts != nil {
+		t.Error("Positions should not be recorded in standard games")
+	}
+}
+
+// TestFinishAsDraw_Reasons tests that every draw reason ends the game the same way
+func TestFinishAsDraw_Reasons(t *testing.T) {
+	for _, reason := range []DrawReason{DrawBoardFull, DrawAgreement, DrawStalemate, DrawMoveCap, DrawRepetition} {
+		game := NewGame(time.Minute, 0)
+		game.AddPlayer(NewPlayer("Alice", 0))
+		game.AddPlayer(NewPlayer("Bob", 0))
+		game.ScheduleMove(time.Minute, func() {})
+
+		game.finishAsDraw(reason)
+
+		if game.Status != StatusFinished || game.Result != ResultDraw {
+			t.Errorf("%s: expected a finished draw, got status %v result %v", reason, game.Status, game.Result)
+		}
+		if game.DrawReason != reason {
+			t.Errorf("%s: draw reason not kept, got %q", reason, game.DrawReason)
+		}
+		if game.MoveTimer != nil {
+			t.Errorf("%s: pending moves should be cancelled", reason)
+		}
+		game.Cleanup()
+	}
+}
+
+// TestNewGameWithBoard_ConnectFive tests a wider board where four in a row is not enough
+func TestNewGameWithBoard_ConnectFive(t *testing.T) {
+	game := NewGameWithBoard(time.Minute, 0, BoardConfig{Rows: 8, Cols: 8, WinLength: 5})
+	game.AddPlayer(NewPlayer("Alice", 0))
+	game.AddPlayer(NewPlayer("Bob", 0))
+	defer game.Cleanup()
+
+	board := game.Board.ToArray()
+	if len(board) != 8 || len(board[0]) != 8 {
+		t.Fatalf("Expected an 8x8 array, got %dx%d", len(board), len(board[0]))
+	}
+
+	// The first player builds along the bottom row, the second stacks on top
+	first := game.CurrentTurn
+	for col := 0; col < 4; col++ {
+		game.Play(first, col)
+		game.Play(1-first, col)
+	}
+	if game.Status != StatusPlaying {
+		t.Fatal("Four in a row should not win a connect five game")
+	}
+
+	if err := game.Play(first, 7); err != nil {
+		t.Fatalf("The last column of a wide board should be playable, got %v", err)
+	}
+	game.Play(1-first, 7)
+	game.Play(first, 4)
+	want := ResultPlayer0Win
+	if first == 1 {
+		want = ResultPlayer1Win
+	}
+	if game.Status != StatusFinished || game.Result != want {
+		t.Errorf("Five in a row should win, got status %v result %v", game.Status, game.Result)
+	}
+}
+
+// TestBoardConfig_Validate tests the limits of a configurable board
+func TestBoardConfig_Validate(t *testing.T) {
+	if err := DefaultBoardConfig.Validate(); err != nil {
+		t.Errorf("The classic board should be valid, got %v", err)
+	}
+	for _, config := range []BoardConfig{
+		{Rows: 3, Cols: 7, WinLength: 3},
+		{Rows: 6, Cols: MaxBoardSize + 1, WinLength: 4},
+		{Rows: 6, Cols: 7, WinLength: 8},
+		{Rows: 6, Cols: 7, WinLength: 2},
+	} {
+		if err := config.Validate(); err != ErrInvalidBoardConfig {
+			t.Errorf("Expected %+v to be rejected, got %v", config, err)
+		}
+	}
+}
+
+// TestPlay_Increment tests that a move adds the increment to the mover's clock unless it ends the game
+func TestPlay_Increment(t *testing.T) {
+	game := NewGame(time.Minute, 5*time.Second)
+	game.AddPlayer(NewPlayer("Alice", 0))
+	game.AddPlayer(NewPlayer("Bob", 0))
+	defer game.Cleanup()
+
+	first := game.CurrentTurn
+	if err := game.Play(first, 0); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if remaining := game.GetTimeRemaining()[first]; remaining <= time.Minute {
+		t.Errorf("Expected the increment on top of the clock, got %v", remaining)
+	}
+
+	// Three more in column 0 for the first player, the second plays elsewhere
+	game.Play(1-first, 1)
+	game.Play(first, 0)
+	game.Play(1-first, 1)
+	game.Play(first, 0)
+	game.Play(1-first, 1)
+	before := game.GetTimeRemaining()[first]
+
+	game.Play(first, 0)
+	if game.Status != StatusFinished {
+		t.Fatal("Four in column 0 should win")
+	}
+	if remaining := game.GetTimeRemaining()[first]; remaining > before {
+		t.Errorf("The winning move should not earn the increment, got %v after %v", remaining, before)
+	}
+}
+
+// TestPlay_WinningCells tests that a win records the cells of the line and a reset clears them
+func TestPlay_WinningCells(t *testing.T) {
+	game := NewGame(0, 0)
+	game.AddPlayer(NewPlayer("Alice", 0))
+	game.AddPlayer(NewPlayer("Bob", 0))
+	game.CurrentTurn = 0
+
+	for _, col := range []int{0, 0, 1, 1, 2, 2} {
+		game.Play(game.CurrentTurn, col)
+	}
+	if game.WinningCells != nil {
+		t.Fatal("No winning cells expected before the win")
+	}
+	if err := game.Play(0, 3); err != nil {
+		t.Fatalf("Win move should not error: %v", err)
+	}
+
+	expected := []LastMove{{Col: 0, Row: Rows - 1}, {Col: 1, Row: Rows - 1}, {Col: 2, Row: Rows - 1}, {Col: 3, Row: Rows - 1}}
+	if !reflect.DeepEqual(game.WinningCells, expected) {
+		t.Errorf("Expected %v, got %v", expected, game.WinningCells)
+	}
+
+	game.RequestReplay(0, true)
+	game.RequestReplay(1, true)
+	if game.WinningCells != nil {
+		t.Error("A new round should clear the winning cells")
+	}
+}
@@ -0,0 +1,58 @@
+// Copyright (c) 2025 Haute école d'ingénierie et d'architecture de Fribourg
+// SPDX-License-Identifier: Apache-2.0
+// Author: Marvin Egger marvin.egger@hotmail.ch
+// Created: 16.10.2026
+
+package lib
+
+import "time"
+
+// GameRecord summarizes a finished game for the history of its players
+type GameRecord struct {
+	Code      string     `json:"code"`
+	Players   [2]string  `json:"players"` // Usernames by seat
+	Result    GameResult `json:"result"`
+	MoveCount int        `json:"move_count"`
+	StartedAt time.Time  `json:"started_at"`
+	EndedAt   time.Time  `json:"ended_at"`
+	Board     [][]Cell   `json:"board"`
+	VsBot     bool       `json:"vs_bot,omitempty"` // One of the seats was played by the server
+}
+
+// Record summarizes the game as it ended
+func (g *Game) Record(endedAt time.Time) GameRecord {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	record := GameRecord{
+		Code:      g.Code,
+		Result:    g.Result,
+		MoveCount: g.MoveCount,
+		StartedAt: g.CreatedAt,
+		EndedAt:   endedAt,
+		Board:     g.Board.ToArray(),
+	}
+	for i, player := range g.Players {
+		if player != nil {
+			record.Players[i] = player.Username
+			record.VsBot = record.VsBot || player.Bot != nil
+		}
+	}
+	return record
+}
+
+// Seats returns the side of every human member of the game by history key, teams included
+func (g *Game) Seats() map[string]int {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	seats := make(map[string]int)
+	for i := range g.Sides {
+		for _, member := range g.Sides[i].Members {
+			if member.Bot == nil {
+				seats[HistoryKey(member.Username)] = i
+			}
+		}
+	}
+	return seats
+}
@@ -0,0 +1,163 @@
+// Copyright (c) 2025 Haute école d'ingénierie et d'architecture de Fribourg
+// SPDX-License-Identifier: Apache-2.0
+// Author: Marvin Egger marvin.egger@hotmail.ch
+// Created: 16.10.2026
+
+package lib
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"os"
+	"strings"
+	"sync"
+)
+
+// maxHistoryLine bounds a line of the history file, a record of the largest board fits easily
+const maxHistoryLine = 64 * 1024
+
+// HistoryEntry is a finished game as seen by one of its players
+type HistoryEntry struct {
+	GameRecord
+	Seat int `json:"seat"` // Side the player was on
+}
+
+// historyLine is one finished game in the history file
+type historyLine struct {
+	GameRecord
+	Seats map[string]int `json:"seats"` // Side of every member by history key, teams included
+}
+
+// HistoryKey returns the key a player's games are kept under
+// Player IDs are drawn again on every login after a restart, so games follow the username instead
+// Like invites this is best-effort, two players sharing a username share a history
+func HistoryKey(username string) string {
+	return strings.ToLower(strings.TrimSpace(username))
+}
+
+// HistoryStore keeps the last games of each player and appends every finished game to a JSON lines file
+// It is safe for concurrent use
+type HistoryStore struct {
+	mu       sync.Mutex
+	limit    int
+	file     *os.File // nil keeps the history in memory only
+	byPlayer map[string][]HistoryEntry
+}
+
+// NewHistoryStore creates a store kept in memory only, each player keeps its last limit games
+func NewHistoryStore(limit int) *HistoryStore {
+	return &HistoryStore{
+		limit:    max(limit, 1),
+		byPlayer: make(map[string][]HistoryEntry),
+	}
+}
+
+// OpenHistoryStore loads the games saved at path and appends new ones to it, the file is created if missing
+// Lines that cannot be read, such as one torn by a crash, are skipped
+func OpenHistoryStore(path string, limit int) (*HistoryStore, error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, err
+	}
+
+	store := NewHistoryStore(limit)
+	if err := store.load(file); err != nil {
+		file.Close()
+		return nil, err
+	}
+	store.file = file
+	return store, nil
+}
+
+// load indexes the games of a history file and makes sure the next append starts on a new line
+func (s *HistoryStore) load(file *os.File) error {
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 4096), maxHistoryLine)
+	for scanner.Scan() {
+		var line historyLine
+		if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+			continue
+		}
+		s.index(line)
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	// A torn last line would swallow the next game otherwise
+	info, err := file.Stat()
+	if err != nil || info.Size() == 0 {
+		return err
+	}
+	last := make([]byte, 1)
+	if _, err := file.ReadAt(last, info.Size()-1); err != nil && err != io.EOF {
+		return err
+	}
+	if last[0] != '\n' {
+		_, err = file.Write([]byte{'\n'})
+	}
+	return err
+}
+
+// Append saves a finished game for every member given by history key with its side
+// The game is kept in memory even when writing the file fails, the error is returned for logging
+func (s *HistoryStore) Append(record GameRecord, seats map[string]int) error {
+	line := historyLine{GameRecord: record, Seats: seats}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.index(line)
+	if s.file == nil {
+		return nil
+	}
+
+	data, err := json.Marshal(line)
+	if err != nil {
+		return err
+	}
+	_, err = s.file.Write(append(data, '\n'))
+	return err
+}
+
+// index adds a game to the recent games of its members, dropping their oldest past the limit
+func (s *HistoryStore) index(line historyLine) {
+	for key, seat := range line.Seats {
+		entries := append(s.byPlayer[key], HistoryEntry{GameRecord: line.GameRecord, Seat: seat})
+		if len(entries) > s.limit {
+			entries = append(entries[:0], entries[len(entries)-s.limit:]...)
+		}
+		s.byPlayer[key] = entries
+	}
+}
+
+// Recent returns up to n of the last games kept under a history key, newest first, n <= 0 returns all that are kept
+func (s *HistoryStore) Recent(key string, n int) []HistoryEntry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries := s.byPlayer[key]
+	if n <= 0 || n > len(entries) {
+		n = len(entries)
+	}
+
+	recent := make([]HistoryEntry, 0, n)
+	for i := len(entries) - 1; i >= len(entries)-n; i-- {
+		recent = append(recent, entries[i])
+	}
+	return recent
+}
+
+// Close closes the history file, later games are kept in memory only
+func (s *HistoryStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.file == nil {
+		return nil
+	}
+	err := s.file.Close()
+	s.file = nil
+	return err
+}
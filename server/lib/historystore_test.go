@@ -0,0 +1,93 @@
+// Copyright (c) 2025 Haute école d'ingénierie et d'architecture de Fribourg
+// SPDX-License-Identifier: Apache-2.0
+// Author: Marvin Egger marvin.egger@hotmail.ch
+// Created: 16.10.2026
+
+package lib
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestHistoryStore_Reload tests that saved games come back newest first, bounded per player
+func TestHistoryStore_Reload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.jsonl")
+	store, err := OpenHistoryStore(path, 2)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	seats := map[string]int{"alice": 0, "bob": 1}
+	for i := 1; i <= 3; i++ {
+		if err := store.Append(GameRecord{Code: "GAME", MoveCount: i}, seats); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+	store.Append(GameRecord{MoveCount: 9}, map[string]int{"carol": 1})
+	store.Close()
+
+	reopened, err := OpenHistoryStore(path, 2)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer reopened.Close()
+
+	recent := reopened.Recent("bob", 0)
+	if len(recent) != 2 || recent[0].MoveCount != 3 || recent[1].MoveCount != 2 {
+		t.Fatalf("Expected the last two games newest first, got %+v", recent)
+	}
+	if recent[0].Seat != 1 {
+		t.Errorf("Expected bob on seat 1, got %d", recent[0].Seat)
+	}
+	if recent := reopened.Recent("alice", 1); len(recent) != 1 || recent[0].MoveCount != 3 {
+		t.Errorf("Expected only the last game, got %+v", recent)
+	}
+	if recent := reopened.Recent("dave", 5); len(recent) != 0 {
+		t.Errorf("Expected no games for an unknown player, got %+v", recent)
+	}
+}
+
+// TestHistoryStore_TornLine tests that a line cut short by a crash does not swallow the next game
+func TestHistoryStore_TornLine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.jsonl")
+	if err := os.WriteFile(path, []byte(`{"code":"TORN","seats":{"al`), 0o644); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	store, err := OpenHistoryStore(path, 10)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	store.Append(GameRecord{Code: "NEXT"}, map[string]int{"alice": 0})
+	store.Close()
+
+	reopened, err := OpenHistoryStore(path, 10)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer reopened.Close()
+
+	if recent := reopened.Recent("alice", 0); len(recent) != 1 || recent[0].Code != "NEXT" {
+		t.Errorf("Expected the game saved after the torn line, got %+v", recent)
+	}
+}
+
+// TestHistoryStore_KeepsMostRecent tests that a player keeps only its last limit games, in order
+func TestHistoryStore_KeepsMostRecent(t *testing.T) {
+	store := NewHistoryStore(10)
+	for i := 0; i < 25; i++ {
+		store.Append(GameRecord{MoveCount: i}, map[string]int{"alice": 0})
+	}
+
+	recent := store.Recent("alice", 0)
+	if len(recent) != 10 {
+		t.Fatalf("Expected 10 games kept, got %d", len(recent))
+	}
+	for i, entry := range recent {
+		if entry.MoveCount != 24-i {
+			t.Errorf("Expected game %d at position %d, got %d", 24-i, i, entry.MoveCount)
+		}
+	}
+}
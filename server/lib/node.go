@@ -62,32 +62,48 @@ func (n *Node) countSequence(dir Direction) int {
 	return count
 }
 
+// winAxes lists one direction per line through a node, the opposite direction completes it
+var winAxes = [...]Direction{DirRight, DirUp, DirUpRight, DirDownRight}
+
 // CheckWin checks if placing a token at this node creates a winning sequence
 func (n *Node) CheckWin(winLength int) bool {
+	_, won := n.WinningDirection(winLength)
+	return won
+}
 
+// WinningDirection returns the direction of the line won by placing a token at this node
+// Horizontal lines are reported as DirRight, vertical as DirUp and diagonals as DirUpRight or DirDownRight
+func (n *Node) WinningDirection(winLength int) (Direction, bool) {
 	if n.IsEmpty() {
-		return false
+		return 0, false
 	}
 
-	// Check horizontal (left + right)
-	if 1+n.countSequence(DirLeft)+n.countSequence(DirRight) >= winLength {
-		return true
+	for _, dir := range winAxes {
+		if 1+n.countSequence(dir)+n.countSequence(dir.Opposite()) >= winLength {
+			return dir, true
+		}
 	}
 
-	// Check vertical (up + down)
-	if 1+n.countSequence(DirUp)+n.countSequence(DirDown) >= winLength {
-		return true
-	}
+	return 0, false
+}
 
-	// Check diagonal down-left to up-right
-	if 1+n.countSequence(DirUpRight)+n.countSequence(DirDownLeft) >= winLength {
-		return true
+// WinningLine returns every node of the line won by placing a token at this node, from one end to the other
+// Lines longer than winLength are returned whole, nil when the node does not win
+func (n *Node) WinningLine(winLength int) []*Node {
+	dir, won := n.WinningDirection(winLength)
+	if !won {
+		return nil
 	}
 
-	// Check diagonal up-left to down-right
-	if 1+n.countSequence(DirUpLeft)+n.countSequence(DirDownRight) >= winLength {
-		return true
+	// Walk back to the far end, then collect the line forward through this node
+	start := n
+	for prev := start.GetNeighbor(dir.Opposite()); prev != nil && prev.Owner == n.Owner; prev = prev.GetNeighbor(dir.Opposite()) {
+		start = prev
 	}
 
-	return false
+	var line []*Node
+	for current := start; current != nil && current.Owner == n.Owner; current = current.GetNeighbor(dir) {
+		line = append(line, current)
+	}
+	return line
 }
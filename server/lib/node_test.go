@@ -208,3 +208,52 @@ func TestIsEmpty(t *testing.T) {
 		t.Error("Node with owner should not be empty")
 	}
 }
+
+// TestWinningDirection_ClassifiesLines tests that each kind of line reports its win method
+func TestWinningDirection_ClassifiesLines(t *testing.T) {
+	tests := []struct {
+		name   string
+		nodes  []*Node
+		method WinMethod
+	}{
+		{"horizontal", createHorizontalChain(4, CellPlayer0), WinHorizontal},
+		{"vertical", createVerticalChain(4, CellPlayer0), WinVertical},
+		{"diagonal up-right", createDiagonalUpRightChain(4, CellPlayer1), WinDiagonal},
+		{"diagonal down-right", createDiagonalDownRightChain(4, CellPlayer1), WinDiagonal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir, won := tt.nodes[1].WinningDirection(4)
+			if !won {
+				t.Fatal("Expected a win")
+			}
+			if method := WinMethodFor(dir); method != tt.method {
+				t.Errorf("Expected %s, got %s", tt.method, method)
+			}
+		})
+	}
+
+	if _, won := NewNode(0, 0).WinningDirection(4); won {
+		t.Error("Empty node should NOT win")
+	}
+}
+
+// TestWinningLine_WholeLineInOrder tests that the whole line is returned end to end, whichever node completed it
+func TestWinningLine_WholeLineInOrder(t *testing.T) {
+	nodes := createHorizontalChain(5, CellPlayer0)
+
+	line := nodes[2].WinningLine(4)
+	if len(line) != len(nodes) {
+		t.Fatalf("Expected %d nodes, got %d", len(nodes), len(line))
+	}
+	for i, node := range line {
+		if node != nodes[i] {
+			t.Errorf("Node %d out of order: got col %d", i, node.Col)
+		}
+	}
+
+	if line := NewNode(0, 0).WinningLine(4); line != nil {
+		t.Error("Empty node should have no winning line")
+	}
+}
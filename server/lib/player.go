@@ -6,12 +6,27 @@
 package lib
 
 import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
 )
 
-const tokenLength = 16
+const (
+	tokenLength = 16
+	tagLength   = 2 // bytes, shown as 4 hex characters
+
+	// ResumeTokenTTL is how long a client may reconnect with the token from its last welcome
+	ResumeTokenTTL = time.Hour
+
+	// Matchmaking cooldown after leaving a ranked game, doubled for each repeated offense
+	baseCooldown = 30 * time.Second
+	maxCooldown  = 8 * time.Minute
+)
 
 // Sender interface abstracts the network layer
 type Sender interface {
@@ -28,6 +43,29 @@ type Player struct {
 	Username  string
 	sender    Sender
 	Remaining time.Duration
+
+	// Rage-quit penalty, offenses are counted for the whole session
+	CooldownUntil time.Time
+	offenses      int
+
+	// Display preferences synced by the client, kept for the session
+	prefs Prefs
+
+	// Games won this session, cosmetics are unlocked from it
+	wins int
+
+	// Consecutive wins this session, broken by a loss or a draw
+	streak     int
+	bestStreak int
+
+	// Best puzzle rush score this session
+	puzzleHighScore int
+
+	// ELO rating, moved by ranked games only
+	Rating int
+
+	// Set for the synthetic opponent of a bot game, the server plays its moves
+	Bot *Bot
 }
 
 // NewPlayer creates a new player with a unique ID
@@ -43,9 +81,16 @@ func NewPlayer(username string, initialClock time.Duration) *Player {
 		ID:        PlayerID(newToken(tokenLength)),
 		Username:  username,
 		Remaining: initialClock,
+		Rating:    DefaultRating,
 	}
 }
 
+// Tag returns a short public tag derived from the player ID without revealing it
+func (p *Player) Tag() string {
+	sum := sha256.Sum256([]byte(p.ID))
+	return "#" + hex.EncodeToString(sum[:tagLength])
+}
+
 // SetSender sets the network sender for this player
 func (p *Player) SetSender(s Sender) {
 	p.Lock()
@@ -53,6 +98,15 @@ func (p *Player) SetSender(s Sender) {
 	p.sender = s
 }
 
+// SwapSender sets a new sender and returns the previous one, nil if none
+func (p *Player) SwapSender(s Sender) Sender {
+	p.Lock()
+	defer p.Unlock()
+	previous := p.sender
+	p.sender = s
+	return previous
+}
+
 // IsConnected checks if the player has an active sender
 func (p *Player) IsConnected() bool {
 	p.RLock()
@@ -68,3 +122,91 @@ func (p *Player) Send(msg Message) {
 		p.sender.Send(msg)
 	}
 }
+
+// ApplyCooldown bars the player from matchmaking after leaving a ranked game early
+func (p *Player) ApplyCooldown(now time.Time) time.Duration {
+	p.Lock()
+	defer p.Unlock()
+
+	cooldown := baseCooldown << p.offenses
+	if cooldown > maxCooldown || cooldown <= 0 {
+		cooldown = maxCooldown
+	}
+	p.offenses++
+	p.CooldownUntil = now.Add(cooldown)
+	return cooldown
+}
+
+// CooldownRemaining returns how long the player is still barred from matchmaking
+func (p *Player) CooldownRemaining(now time.Time) time.Duration {
+	p.RLock()
+	defer p.RUnlock()
+
+	if now.After(p.CooldownUntil) {
+		return 0
+	}
+	return p.CooldownUntil.Sub(now)
+}
+
+// MintResumeToken signs a player ID and expiry so clients never store the raw ID
+func MintResumeToken(id PlayerID, secret []byte, now time.Time) string {
+	payload := string(id) + "." + strconv.FormatInt(now.Add(ResumeTokenTTL).Unix(), 10)
+	return base64.RawURLEncoding.EncodeToString([]byte(payload)) + "." +
+		base64.RawURLEncoding.EncodeToString(signResumePayload(payload, secret))
+}
+
+// VerifyResumeToken checks the signature and expiry of a resume token and returns its player ID
+func VerifyResumeToken(token string, secret []byte, now time.Time) (PlayerID, error) {
+	encodedPayload, encodedSignature, found := strings.Cut(token, ".")
+	if !found {
+		return "", ErrInvalidResumeToken
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encodedPayload)
+	if err != nil {
+		return "", ErrInvalidResumeToken
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(encodedSignature)
+	if err != nil {
+		return "", ErrInvalidResumeToken
+	}
+
+	if !hmac.Equal(signature, signResumePayload(string(payload), secret)) {
+		return "", ErrInvalidResumeToken
+	}
+
+	id, expiry, found := strings.Cut(string(payload), ".")
+	if !found {
+		return "", ErrInvalidResumeToken
+	}
+	expiresAt, err := strconv.ParseInt(expiry, 10, 64)
+	if err != nil {
+		return "", ErrInvalidResumeToken
+	}
+	if now.Unix() > expiresAt {
+		return "", ErrResumeTokenExpired
+	}
+
+	return PlayerID(id), nil
+}
+
+// signResumePayload computes the HMAC-SHA256 of a resume token payload
+func signResumePayload(payload string, secret []byte) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(payload))
+	return mac.Sum(nil)
+}
+
+// SetPrefs replaces the synced display preferences
+func (p *Player) SetPrefs(prefs Prefs) {
+	p.Lock()
+	defer p.Unlock()
+	p.prefs = prefs
+}
+
+// GetPrefs returns the synced display preferences, nil if never saved
+func (p *Player) GetPrefs() Prefs {
+	p.RLock()
+	defer p.RUnlock()
+	return p.prefs
+}
@@ -0,0 +1,126 @@
+// Copyright (c) 2025 Haute école d'ingénierie et d'architecture de Fribourg
+// SPDX-License-Identifier: Apache-2.0
+// Author: Marvin Egger marvin.egger@hotmail.ch
+// Created: 16.10.2026
+
+package lib
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestApplyCooldown_FirstOffense tests the base cooldown duration
+func TestApplyCooldown_FirstOffense(t *testing.T) {
+	player := NewPlayer("Alice", 0)
+	now := time.Now()
+
+	if cooldown := player.ApplyCooldown(now); cooldown != baseCooldown {
+		t.Errorf("Expected %v cooldown, got %v", baseCooldown, cooldown)
+	}
+
+	if player.CooldownRemaining(now) != baseCooldown {
+		t.Error("Cooldown should be active right after the offense")
+	}
+}
+
+// TestApplyCooldown_Escalates tests that repeated offenses double the cooldown up to the cap
+func TestApplyCooldown_Escalates(t *testing.T) {
+	player := NewPlayer("Alice", 0)
+	now := time.Now()
+
+	player.ApplyCooldown(now)
+	if cooldown := player.ApplyCooldown(now); cooldown != 2*baseCooldown {
+		t.Errorf("Second offense should double the cooldown, got %v", cooldown)
+	}
+
+	for i := 0; i < 10; i++ {
+		player.ApplyCooldown(now)
+	}
+	if cooldown := player.ApplyCooldown(now); cooldown != maxCooldown {
+		t.Errorf("Cooldown should be capped at %v, got %v", maxCooldown, cooldown)
+	}
+}
+
+// TestCooldownRemaining_Expires tests that the cooldown ends after its duration
+func TestCooldownRemaining_Expires(t *testing.T) {
+	player := NewPlayer("Alice", 0)
+	now := time.Now()
+
+	if player.CooldownRemaining(now) != 0 {
+		t.Error("New player should not have a cooldown")
+	}
+
+	player.ApplyCooldown(now)
+	if player.CooldownRemaining(now.Add(baseCooldown+time.Second)) != 0 {
+		t.Error("Cooldown should expire after its duration")
+	}
+}
+
+// TestResumeToken_RoundTrip tests that a freshly minted token resolves to its player
+func TestResumeToken_RoundTrip(t *testing.T) {
+	secret := []byte("secret")
+	now := time.Now()
+
+	id, err := VerifyResumeToken(MintResumeToken("ABC123", secret, now), secret, now)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if id != "ABC123" {
+		t.Errorf("Expected ABC123, got %s", id)
+	}
+}
+
+// TestResumeToken_Expired tests that tokens are rejected after their lifetime
+func TestResumeToken_Expired(t *testing.T) {
+	secret := []byte("secret")
+	now := time.Now()
+	token := MintResumeToken("ABC123", secret, now)
+
+	if _, err := VerifyResumeToken(token, secret, now.Add(ResumeTokenTTL+time.Minute)); err != ErrResumeTokenExpired {
+		t.Errorf("Expected ErrResumeTokenExpired, got %v", err)
+	}
+}
+
+// TestResumeToken_Tampered tests that changing the payload or the secret invalidates the token
+func TestResumeToken_Tampered(t *testing.T) {
+	secret := []byte("secret")
+	now := time.Now()
+	token := MintResumeToken("ABC123", secret, now)
+	forged := MintResumeToken("XYZ789", []byte("other"), now)
+
+	_, signature, _ := strings.Cut(token, ".")
+	payload, _, _ := strings.Cut(forged, ".")
+
+	tests := []string{"", "garbage", payload + "." + signature, forged}
+	for _, candidate := range tests {
+		if _, err := VerifyResumeToken(candidate, secret, now); err != ErrInvalidResumeToken {
+			t.Errorf("Token %q: expected ErrInvalidResumeToken, got %v", candidate, err)
+		}
+	}
+}
+
+// TestRecordWin_Unlocks tests that cosmetics unlock exactly when their milestone is reached
+func TestRecordWin_Unlocks(t *testing.T) {
+	player := NewPlayer("Alice", 0)
+
+	for wins := 1; wins <= UnlockHeartsWins; wins++ {
+		unlocked := player.RecordWin()
+
+		var want []string
+		switch wins {
+		case UnlockFruitWins:
+			want = []string{UnlockFruitSkin}
+		case UnlockHeartsWins:
+			want = []string{UnlockHeartsSkin}
+		}
+		if len(unlocked) != len(want) || (len(want) > 0 && unlocked[0] != want[0]) {
+			t.Errorf("Win %d: expected unlocks %v, got %v", wins, want, unlocked)
+		}
+	}
+
+	if got := UnlocksFor(player.GetWins()); len(got) != 2 {
+		t.Errorf("Expected both skins unlocked after %d wins, got %v", player.GetWins(), got)
+	}
+}
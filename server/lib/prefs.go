@@ -0,0 +1,42 @@
+// Copyright (c) 2025 Haute école d'ingénierie et d'architecture de Fribourg
+// SPDX-License-Identifier: Apache-2.0
+// Author: Marvin Egger marvin.egger@hotmail.ch
+// Created: 16.10.2026
+
+package lib
+
+// MaxPrefValueLength bounds each synced preference value
+const MaxPrefValueLength = 16
+
+// PrefKeys lists the client preferences that may be synced through the server
+var PrefKeys = map[string]bool{
+	"renderStyle":    true,
+	"discSkin":       true,
+	"highlightColor": true,
+	"highlightPulse": true,
+	"winningPreview": true,
+	"gravityTrail":   true,
+	"mirrorBoard":    true,
+	"confirmMoves":   true,
+	"turnAlerts":     true,
+	"autoRematch":    true,
+	"highContrast":   true,
+	"moveNumbers":    true,
+	"meFirst":        true,
+	"blockHints":     true,
+	"dropEasing":     true,
+	"streamPalette":  true,
+}
+
+// Prefs holds display preferences keyed like the client's localStorage
+type Prefs map[string]string
+
+// Validate checks that only known keys with short values are present
+func (p Prefs) Validate() error {
+	for key, value := range p {
+		if !PrefKeys[key] || len(value) > MaxPrefValueLength {
+			return ErrInvalidPrefs
+		}
+	}
+	return nil
+}
@@ -5,6 +5,13 @@
 
 package lib
 
+// ProtocolVersion is the protocol version spoken by this server
+// Clients that do not send a version are treated as version 1
+const (
+	ProtocolVersion       = 2
+	defaultClientProtocol = 1
+)
+
 // MessageType identifies the type of websocket message
 type MessageType string
 
@@ -19,6 +26,21 @@ const (
 	MsgLeaveLobby       MessageType = "leave_lobby"
 	MsgJoinMatchmaking  MessageType = "join_matchmaking"
 	MsgLeaveMatchmaking MessageType = "leave_matchmaking"
+	MsgReadyResponse    MessageType = "ready_response"
+	MsgLobbyChat        MessageType = "lobby_chat" // Also broadcast back to lobby players
+	MsgSavePrefs        MessageType = "save_prefs"
+	MsgResignRound      MessageType = "resign_round"
+	MsgPuzzleStart      MessageType = "puzzle_start"
+	MsgPuzzleAnswer     MessageType = "puzzle_answer"
+	MsgSpectateFeatured MessageType = "spectate_featured"
+	MsgPlayBot          MessageType = "play_bot"
+	MsgSpectate         MessageType = "spectate"
+	MsgHistoryRequest   MessageType = "history_request"
+	MsgUndoRequest      MessageType = "undo_request"  // Also relayed to both players of the game
+	MsgUndoResponse     MessageType = "undo_response" // Also relayed to both players of the game
+	MsgLeaderboard      MessageType = "leaderboard"   // Also sent back with the ranking
+	MsgDrawOffer        MessageType = "draw_offer"    // Also relayed to both players of the game
+	MsgDrawResponse     MessageType = "draw_response" // Also relayed to both players of the game
 
 	// Server to Client
 	MsgWelcome              MessageType = "welcome"
@@ -31,29 +53,129 @@ const (
 	MsgError                MessageType = "error"
 	MsgMatchmakingSearching MessageType = "matchmaking_searching"
 	MsgQueueUpdate          MessageType = "queue_update"
+	MsgVersionMismatch      MessageType = "version_mismatch"
+	MsgCooldown             MessageType = "cooldown"
+	MsgReadyCheck           MessageType = "ready_check"
+	MsgReadyCheckFailed     MessageType = "ready_check_failed"
+	MsgReplayDeclined       MessageType = "replay_declined"
+	MsgOpponentThinking     MessageType = "opponent_thinking"
+	MsgSessionTaken         MessageType = "session_taken"
+	MsgOpponentDisconnected MessageType = "opponent_disconnected"
+	MsgOpponentReconnected  MessageType = "opponent_reconnected"
+	MsgStats                MessageType = "stats"
+	MsgAnnouncement         MessageType = "announcement"
+	MsgPuzzleNext           MessageType = "puzzle_next"
+	MsgPuzzleResult         MessageType = "puzzle_result"
+	MsgFeaturedGame         MessageType = "featured_game"
+	MsgHistoryResponse      MessageType = "history_response"
+	MsgServerShutdown       MessageType = "server_shutdown"
+	MsgTimeSync             MessageType = "time_sync"
 )
 
+// ClientMessageTypes lists every message type a client may send to the server
+var ClientMessageTypes = []MessageType{
+	MsgLogin,
+	MsgCreateGame,
+	MsgJoinGame,
+	MsgPlay,
+	MsgReplay,
+	MsgForfeit,
+	MsgLeaveLobby,
+	MsgJoinMatchmaking,
+	MsgLeaveMatchmaking,
+	MsgReadyResponse,
+	MsgLobbyChat,
+	MsgSavePrefs,
+	MsgResignRound,
+	MsgPuzzleStart,
+	MsgPuzzleAnswer,
+	MsgSpectateFeatured,
+	MsgPlayBot,
+	MsgSpectate,
+	MsgHistoryRequest,
+	MsgUndoRequest,
+	MsgUndoResponse,
+	MsgLeaderboard,
+	MsgDrawOffer,
+	MsgDrawResponse,
+}
+
 // Message represents a websocket message
 type Message struct {
-	Type MessageType `json:"type"`
-	Data interface{} `json:"data,omitempty"`
+	Type    MessageType `json:"type"`
+	Version int         `json:"version,omitempty"`
+	Data    interface{} `json:"data,omitempty"`
+}
+
+// DevBuild is the build hash of binaries built without one
+const DevBuild = "dev"
+
+// IsStaleBuild reports whether two known build hashes differ
+// Development builds and clients that do not send a hash are never reported
+func IsStaleBuild(clientHash, serverHash string) bool {
+	if clientHash == "" || clientHash == DevBuild || serverHash == "" || serverHash == DevBuild {
+		return false
+	}
+	return clientHash != serverHash
+}
+
+// ClientVersion returns the protocol version announced by a client message
+func (m Message) ClientVersion() int {
+	if m.Version <= 0 {
+		return defaultClientProtocol
+	}
+	return m.Version
 }
 
 // LoginData contains login credentials
 type LoginData struct {
-	Username string    `json:"username"`
-	PlayerID *PlayerID `json:"player_id,omitempty"` // for reconnection
+	Username    string `json:"username"`
+	ResumeToken string `json:"resume_token,omitempty"` // for reconnection
+	FlatBoard   bool   `json:"flat_board,omitempty"`   // Receive boards as a flat row-major array with rows and cols
+	BuildHash   string `json:"build_hash,omitempty"`   // Build of the client bundle, empty for older clients
 }
 
 // WelcomeData sent after successful login
 type WelcomeData struct {
-	PlayerID PlayerID `json:"player_id"`
-	Username string   `json:"username"`
+	PlayerID    PlayerID `json:"player_id"`
+	Username    string   `json:"username"`
+	ResumeToken string   `json:"resume_token"` // signed and short-lived, stored by the client instead of the ID
+	Prefs       Prefs    `json:"prefs,omitempty"`
+	Wins        int      `json:"wins"`
+	Unlocks     []string `json:"unlocks"`
+	BuildHash   string   `json:"build_hash,omitempty"` // Build of the server, clients from another build are stale
+
+	// Absent for older servers, clients then assume every mode is available
+	Capabilities *ServerCapabilities `json:"capabilities,omitempty"`
+}
+
+// ServerCapabilities lists the optional features enabled on this server
+// New optional features add a field here so clients can hide what a deployment turned off
+type ServerCapabilities struct {
+	FriendGames bool `json:"friend_games"`
+	Matchmaking bool `json:"matchmaking"`
+	Chat        bool `json:"chat"`
+	Series      bool `json:"series"` // Best-of friend games
+	Bot         bool `json:"bot"`
+	FlatBoard   bool `json:"flat_board"` // Boards can be requested in flat form at login
+	Puzzles     bool `json:"puzzles"`    // Single-player puzzle rush
 }
 
 // GameCreatedData sent when game is created
 type GameCreatedData struct {
-	Code string `json:"code"`
+	Code   string `json:"code"`
+	Invite string `json:"invite,omitempty"` // Username the game is reserved for
+}
+
+// CreateGameData contains the options of a new friend game
+type CreateGameData struct {
+	PauseOnDisconnect bool   `json:"pause_on_disconnect"`
+	BestOf            int    `json:"best_of,omitempty"` // Rounds of a series, 0 or 1 for a single game
+	Invite            string `json:"invite,omitempty"`  // Only this username may join, best-effort
+
+	// Time control picked by the host, the server default when both are unset
+	ClockSeconds     int `json:"clock_seconds,omitempty"`
+	IncrementSeconds int `json:"increment_seconds,omitempty"`
 }
 
 // JoinGameData contains game join request
@@ -61,11 +183,51 @@ type JoinGameData struct {
 	Code string `json:"code"`
 }
 
+// SpectateData contains the code of a game to watch
+type SpectateData struct {
+	Code string `json:"code"`
+}
+
+// HistoryRequestData asks for the player's last finished games
+type HistoryRequestData struct {
+	Limit int `json:"limit,omitempty"` // Every kept game when unset
+}
+
+// HistoryResponseData lists the player's last finished games, newest first
+type HistoryResponseData struct {
+	Games []HistoryEntry `json:"games"`
+}
+
+// TimeSyncData carries the authoritative clocks of a game in progress, sent every few seconds
+type TimeSyncData struct {
+	TimeRemaining [2]int64 `json:"time_remaining"` // milliseconds
+	CurrentTurn   int      `json:"current_turn"`   // Side whose clock is running
+}
+
+// LeaderboardRequestData asks for the best rated players online
+type LeaderboardRequestData struct {
+	Limit int `json:"limit,omitempty"` // Server default when unset
+}
+
+// RatingEntry is one player of the rating leaderboard, IDs stay private
+type RatingEntry struct {
+	Rank     int    `json:"rank"`
+	Username string `json:"username"`
+	Tag      string `json:"tag"`
+	Rating   int    `json:"rating"`
+}
+
+// LeaderboardData lists the best rated players online, best first
+type LeaderboardData struct {
+	Players []RatingEntry `json:"players"`
+}
+
 // PlayerInfo contains public player information
 type PlayerInfo struct {
 	ID        PlayerID `json:"id"`
 	Username  string   `json:"username"`
 	Connected bool     `json:"connected"`
+	IsBot     bool     `json:"is_bot"` // Seat played by the server, never set for a human
 }
 
 // GameStartData sent when game starts
@@ -74,6 +236,10 @@ type GameStartData struct {
 	CurrentTurn   int           `json:"current_turn"`
 	Players       [2]PlayerInfo `json:"players"`
 	TimeRemaining [2]int64      `json:"time_remaining"` // milliseconds
+	AllowReplay   bool          `json:"allow_replay"`
+	Ranked        bool          `json:"ranked,omitempty"` // Matchmaking game, hints stay off
+	BestOf        int           `json:"best_of,omitempty"`
+	Score         [2]int        `json:"score"` // Rounds won per side in a series
 }
 
 // PlayData contains a move request
@@ -81,27 +247,60 @@ type PlayData struct {
 	Column int `json:"column"`
 }
 
+// ReplayData contains a rematch request, seats swap unless both players ask to keep them
+type ReplayData struct {
+	KeepSeats bool `json:"keep_seats,omitempty"`
+}
+
 // MoveData broadcasts a move to both players
 type MoveData struct {
-	PlayerIdx     int              `json:"player_idx"`
-	Column        int              `json:"column"`
-	Row           int              `json:"row"`
-	Board         [Rows][Cols]Cell `json:"board"`
-	NextTurn      int              `json:"next_turn"`
-	TimeRemaining [2]int64         `json:"time_remaining"` // milliseconds
+	PlayerIdx     int      `json:"player_idx"`
+	Column        int      `json:"column"`
+	Row           int      `json:"row"`
+	Board         [][]Cell `json:"board"`
+	NextTurn      int      `json:"next_turn"`
+	TimeRemaining [2]int64 `json:"time_remaining"` // milliseconds
 }
 
 // GameOverData sent when game ends
 type GameOverData struct {
-	Result GameResult       `json:"result"`
-	Board  [Rows][Cols]Cell `json:"board"`
+	Result       GameResult `json:"result"`
+	DrawReason   DrawReason `json:"draw_reason,omitempty"`
+	Board        [][]Cell   `json:"board"`
+	WinningCells [][2]int   `json:"winning_cells,omitempty"` // Row and column of each cell of the winning line
+	Score        [2]int     `json:"score"`
+	SeriesOver   bool       `json:"series_over"`             // False while more rounds of a series follow
+	SeriesResult GameResult `json:"series_result,omitempty"` // Winner of a finished series, a draw is a shared victory
 }
 
 // ReplayRequestData sent when a player requests replay
 type ReplayRequestData struct {
+	PlayerIdx int  `json:"player_idx"`
+	KeepSeats bool `json:"keep_seats,omitempty"` // Seats proposed for the next round
+}
+
+// UndoRequestData announces a takeback request, the player index is filled in by the server
+type UndoRequestData struct {
+	PlayerIdx int `json:"player_idx"`
+}
+
+// UndoResponseData answers a takeback request, relayed with the index of the player who answered
+type UndoResponseData struct {
+	Accept    bool `json:"accept"`
+	PlayerIdx int  `json:"player_idx"`
+}
+
+// DrawOfferData announces a draw offer, the player index is filled in by the server
+type DrawOfferData struct {
 	PlayerIdx int `json:"player_idx"`
 }
 
+// DrawResponseData answers a draw offer, relayed with the index of the player who answered
+type DrawResponseData struct {
+	Accept    bool `json:"accept"`
+	PlayerIdx int  `json:"player_idx"`
+}
+
 // ErrorData contains error information
 type ErrorData struct {
 	Message string `json:"message"`
@@ -110,20 +309,150 @@ type ErrorData struct {
 
 // GameStateData contains full game state for reconnection
 type GameStateData struct {
-	Code           string           `json:"code"`
-	Status         GameStatus       `json:"status"`
-	Result         GameResult       `json:"result"`
-	Board          [Rows][Cols]Cell `json:"board"`
-	Players        [2]PlayerInfo    `json:"players"`
-	PlayerIdx      int              `json:"player_idx"`
-	CurrentTurn    int              `json:"current_turn"`
-	MoveCount      int              `json:"move_count"`
-	TimeRemaining  [2]int64         `json:"time_remaining"` // milliseconds
-	ReplayRequests [2]bool          `json:"replay_requests"`
-	LastMove       *LastMove        `json:"last_move,omitempty"`
+	Code           string        `json:"code"`
+	Status         GameStatus    `json:"status"`
+	Result         GameResult    `json:"result"`
+	DrawReason     DrawReason    `json:"draw_reason,omitempty"`
+	Board          [][]Cell      `json:"board"`
+	Players        [2]PlayerInfo `json:"players"`
+	PlayerIdx      int           `json:"player_idx"`
+	CurrentTurn    int           `json:"current_turn"`
+	MoveCount      int           `json:"move_count"`
+	TimeRemaining  [2]int64      `json:"time_remaining"` // milliseconds
+	ReplayRequests [2]bool       `json:"replay_requests"`
+	AllowReplay    bool          `json:"allow_replay"`
+	Ranked         bool          `json:"ranked,omitempty"`
+	Paused         bool          `json:"paused"`
+	GraceRemaining int64         `json:"grace_remaining_ms,omitempty"` // milliseconds until an idle game is closed
+	LastMove       *LastMove     `json:"last_move,omitempty"`
+	Moves          []LastMove    `json:"moves,omitempty"` // Moves of the round in order of play
+	BestOf         int           `json:"best_of,omitempty"`
+	Score          [2]int        `json:"score"`
+	SeriesOver     bool          `json:"series_over,omitempty"`
+	SeriesResult   GameResult    `json:"series_result,omitempty"`
 }
 
 // QueueUpdateData contains matchmaking queue information
 type QueueUpdateData struct {
 	PlayersInQueue int `json:"players_in_queue"`
 }
+
+// VersionMismatchData warns a client that its protocol version differs from the server
+type VersionMismatchData struct {
+	ClientVersion int `json:"client_version"`
+	ServerVersion int `json:"server_version"`
+}
+
+// CooldownData tells a player how long matchmaking stays unavailable
+type CooldownData struct {
+	SecondsRemaining int `json:"seconds_remaining"`
+}
+
+// ReadyCheckData asks matched players to confirm they are ready to play
+type ReadyCheckData struct {
+	TimeoutSeconds int `json:"timeout_seconds"`
+}
+
+// ReadyResponseData contains a player's answer to a ready check
+type ReadyResponseData struct {
+	Accept bool `json:"accept"`
+}
+
+// ReadyCheckFailedData tells a player the match was cancelled and whether they are back in the queue
+type ReadyCheckFailedData struct {
+	Requeued bool `json:"requeued"`
+}
+
+// LobbyChatData contains a chat message sent to the lobby
+type LobbyChatData struct {
+	Text string `json:"text"`
+}
+
+// LobbyChatMessageData broadcasts a lobby chat message with its author
+type LobbyChatMessageData struct {
+	Tag      string `json:"tag"` // Short stable tag telling apart players with the same username
+	Username string `json:"username"`
+	Text     string `json:"text"`
+}
+
+// ThinkingData tells players whether a bot side is preparing its reply
+type ThinkingData struct {
+	PlayerIdx int  `json:"player_idx"`
+	Thinking  bool `json:"thinking"`
+}
+
+// PresenceData tells players that a side dropped or came back during a game
+type PresenceData struct {
+	PlayerIdx int  `json:"player_idx"`
+	Paused    bool `json:"paused"` // Whether the clocks are frozen while the side is away
+}
+
+// StatsData tells a player their session wins, win streaks and unlocked cosmetics
+type StatsData struct {
+	Wins       int      `json:"wins"`
+	Unlocks    []string `json:"unlocks"`
+	NewUnlocks []string `json:"new_unlocks,omitempty"` // Unlocked by the game that just ended
+	Streak     int      `json:"streak"`                // Consecutive wins, 0 after a loss or a draw
+	BestStreak int      `json:"best_streak"`
+	Rating     int      `json:"rating"`
+	RatingGain int      `json:"rating_gain,omitempty"` // Points won, or lost when negative, by the ranked game that just ended
+}
+
+// Announcement levels
+const (
+	AnnouncementInfo    = "info"
+	AnnouncementWarning = "warning"
+)
+
+// AnnouncementData is an operator notice shown to every connected player
+type AnnouncementData struct {
+	Text  string `json:"text"`
+	Level string `json:"level"` // AnnouncementInfo or AnnouncementWarning
+}
+
+// PlayBotData starts a game against the server's bot
+type PlayBotData struct {
+	Depth   int        `json:"depth,omitempty"`   // Search depth in plies, DefaultBotDepth when unset
+	Profile BotProfile `json:"profile,omitempty"` // Balanced when unset
+}
+
+// PuzzleAnswerData contains the column played on the current puzzle
+type PuzzleAnswerData struct {
+	Column int `json:"column"`
+}
+
+// PuzzleData is the next position of a puzzle rush
+type PuzzleData struct {
+	Number    int              `json:"number"` // 1 for the first puzzle of the run
+	Board     [Rows][Cols]Cell `json:"board"`
+	PlayerIdx int              `json:"player_idx"` // Side to move, the player plays its tokens
+	Depth     int              `json:"depth"`      // Own moves needed to force the win
+	TimeLimit int64            `json:"time_limit"` // milliseconds
+	Score     int              `json:"score"`
+	HighScore int              `json:"high_score"`
+}
+
+// PuzzleResultData tells how an answer was judged, a wrong answer or a timeout ends the run
+type PuzzleResultData struct {
+	Correct      bool  `json:"correct"`
+	TimedOut     bool  `json:"timed_out,omitempty"`
+	Column       int   `json:"column"`
+	Solutions    []int `json:"solutions"`
+	Points       int   `json:"points"`
+	Score        int   `json:"score"`
+	HighScore    int   `json:"high_score"`
+	NewHighScore bool  `json:"new_high_score,omitempty"`
+	Over         bool  `json:"over"`
+}
+
+// FeaturedGameData points a lobby player to the game to watch through its event stream
+type FeaturedGameData struct {
+	Code      string        `json:"code"`
+	Players   [2]PlayerInfo `json:"players"` // IDs are left empty
+	MoveCount int           `json:"move_count"`
+}
+
+// SavePrefsData contains the display preferences a player syncs across devices
+type SavePrefsData struct {
+	Prefs Prefs `json:"prefs"`
+}
@@ -0,0 +1,530 @@
+// Copyright (c) 2025 Haute école d'ingénierie et d'architecture de Fribourg
+// SPDX-License-Identifier: Apache-2.0
+// Author: Marvin Egger marvin.egger@hotmail.ch
+// Created: 16.10.2026
+
+package lib
+
+import (
+	"encoding/json"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"math/rand"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// The client is built for js/wasm in its own module, so its message structs are mirrored here
+// TestProtocol_ClientMirrorsMatchSource keeps these copies in sync with the client sources
+
+type clientPlayer struct {
+	ID       string `json:"id"`
+	Username string `json:"username"`
+	IsBot    bool   `json:"is_bot"`
+}
+
+type clientLastMove struct {
+	Col int
+	Row int
+}
+
+type clientWelcomeData struct {
+	PlayerID     string                    `json:"player_id"`
+	Username     string                    `json:"username"`
+	ResumeToken  string                    `json:"resume_token"`
+	Prefs        map[string]string         `json:"prefs"`
+	Wins         int                       `json:"wins"`
+	Unlocks      []string                  `json:"unlocks"`
+	BuildHash    string                    `json:"build_hash,omitempty"`
+	Capabilities *clientServerCapabilities `json:"capabilities,omitempty"`
+}
+
+type clientServerCapabilities struct {
+	FriendGames bool `json:"friend_games"`
+	Matchmaking bool `json:"matchmaking"`
+	Chat        bool `json:"chat"`
+	Series      bool `json:"series"`
+	Bot         bool `json:"bot"`
+	Puzzles     bool `json:"puzzles"`
+}
+
+type clientGameCreatedData struct {
+	Code   string `json:"code"`
+	Invite string `json:"invite,omitempty"`
+}
+
+type clientGameStartData struct {
+	Code          string          `json:"code"`
+	CurrentTurn   int             `json:"current_turn"`
+	Players       [2]clientPlayer `json:"players"`
+	TimeRemaining [2]int64        `json:"time_remaining"`
+	AllowReplay   bool            `json:"allow_replay"`
+	Ranked        bool            `json:"ranked,omitempty"`
+	BestOf        int             `json:"best_of,omitempty"`
+	Score         [2]int          `json:"score"`
+}
+
+type clientGameStateData struct {
+	Code           string           `json:"code"`
+	Status         int              `json:"status"`
+	Result         int              `json:"result"`
+	DrawReason     string           `json:"draw_reason,omitempty"`
+	CurrentTurn    int              `json:"current_turn"`
+	MoveCount      int              `json:"move_count"`
+	Board          [6][7]int        `json:"board"`
+	Players        [2]clientPlayer  `json:"players"`
+	TimeRemaining  [2]int64         `json:"time_remaining"`
+	ReplayRequests [2]bool          `json:"replay_requests"`
+	AllowReplay    bool             `json:"allow_replay"`
+	Ranked         bool             `json:"ranked,omitempty"`
+	Paused         bool             `json:"paused"`
+	GraceRemaining int64            `json:"grace_remaining_ms,omitempty"`
+	LastMove       *clientLastMove  `json:"last_move,omitempty"`
+	Moves          []clientLastMove `json:"moves,omitempty"`
+	BestOf         int              `json:"best_of,omitempty"`
+	Score          [2]int           `json:"score"`
+	SeriesOver     bool             `json:"series_over,omitempty"`
+	SeriesResult   int              `json:"series_result,omitempty"`
+}
+
+type clientMoveData struct {
+	PlayerIdx     int       `json:"player_idx"`
+	Column        int       `json:"column"`
+	Row           int       `json:"row"`
+	Board         [6][7]int `json:"board"`
+	NextTurn      int       `json:"next_turn"`
+	TimeRemaining [2]int64  `json:"time_remaining"`
+}
+
+type clientGameOverData struct {
+	Result       int       `json:"result"`
+	DrawReason   string    `json:"draw_reason,omitempty"`
+	Board        [6][7]int `json:"board"`
+	WinningCells [][2]int  `json:"winning_cells,omitempty"`
+	Score        [2]int    `json:"score"`
+	SeriesOver   bool      `json:"series_over"`
+	SeriesResult int       `json:"series_result,omitempty"`
+}
+
+type clientReplayRequestData struct {
+	PlayerIdx int  `json:"player_idx"`
+	KeepSeats bool `json:"keep_seats,omitempty"`
+}
+
+type clientVersionMismatchData struct {
+	ClientVersion int `json:"client_version"`
+	ServerVersion int `json:"server_version"`
+}
+
+type clientCooldownData struct {
+	SecondsRemaining int `json:"seconds_remaining"`
+}
+
+type clientReadyCheckData struct {
+	TimeoutSeconds int `json:"timeout_seconds"`
+}
+
+type clientReadyCheckFailedData struct {
+	Requeued bool `json:"requeued"`
+}
+
+type clientLobbyChatMessageData struct {
+	Tag      string `json:"tag"`
+	Username string `json:"username"`
+	Text     string `json:"text"`
+}
+
+type clientThinkingData struct {
+	PlayerIdx int  `json:"player_idx"`
+	Thinking  bool `json:"thinking"`
+}
+
+type clientAnnouncementData struct {
+	Text  string `json:"text"`
+	Level string `json:"level"`
+}
+
+type clientPresenceData struct {
+	PlayerIdx int  `json:"player_idx"`
+	Paused    bool `json:"paused"`
+}
+
+type clientTimeSyncData struct {
+	TimeRemaining [2]int64 `json:"time_remaining"`
+	CurrentTurn   int      `json:"current_turn"`
+}
+
+type clientStatsData struct {
+	Wins       int      `json:"wins"`
+	Unlocks    []string `json:"unlocks"`
+	NewUnlocks []string `json:"new_unlocks,omitempty"`
+	Streak     int      `json:"streak"`
+	BestStreak int      `json:"best_streak"`
+	Rating     int      `json:"rating"`
+	RatingGain int      `json:"rating_gain,omitempty"`
+}
+
+type clientPuzzleData struct {
+	Number    int       `json:"number"`
+	Board     [6][7]int `json:"board"`
+	PlayerIdx int       `json:"player_idx"`
+	Depth     int       `json:"depth"`
+	TimeLimit int64     `json:"time_limit"`
+	Score     int       `json:"score"`
+	HighScore int       `json:"high_score"`
+}
+
+type clientPuzzleResultData struct {
+	Correct      bool  `json:"correct"`
+	TimedOut     bool  `json:"timed_out,omitempty"`
+	Column       int   `json:"column"`
+	Solutions    []int `json:"solutions"`
+	Points       int   `json:"points"`
+	Score        int   `json:"score"`
+	HighScore    int   `json:"high_score"`
+	NewHighScore bool  `json:"new_high_score,omitempty"`
+	Over         bool  `json:"over"`
+}
+
+type clientFeaturedGameData struct {
+	Code      string          `json:"code"`
+	Players   [2]clientPlayer `json:"players"`
+	MoveCount int             `json:"move_count"`
+}
+
+type clientHistoryEntry struct {
+	Code      string    `json:"code"`
+	Players   [2]string `json:"players"`
+	Result    int       `json:"result"`
+	MoveCount int       `json:"move_count"`
+	Board     [6][7]int `json:"board"`
+	VsBot     bool      `json:"vs_bot,omitempty"`
+	Seat      int       `json:"seat"`
+}
+
+type clientHistoryResponseData struct {
+	Games []clientHistoryEntry `json:"games"`
+}
+
+type clientErrorData struct {
+	Message string `json:"message"`
+	Code    string `json:"code,omitempty"`
+}
+
+// protocolPairs lists each server message struct with the client struct decoding it
+var protocolPairs = []struct {
+	server interface{}
+	client interface{}
+}{
+	{WelcomeData{}, clientWelcomeData{}},
+	{GameCreatedData{}, clientGameCreatedData{}},
+	{GameStartData{}, clientGameStartData{}},
+	{GameStateData{}, clientGameStateData{}},
+	{MoveData{}, clientMoveData{}},
+	{GameOverData{}, clientGameOverData{}},
+	{ReplayRequestData{}, clientReplayRequestData{}},
+	{VersionMismatchData{}, clientVersionMismatchData{}},
+	{CooldownData{}, clientCooldownData{}},
+	{ReadyCheckData{}, clientReadyCheckData{}},
+	{ReadyCheckFailedData{}, clientReadyCheckFailedData{}},
+	{LobbyChatMessageData{}, clientLobbyChatMessageData{}},
+	{ThinkingData{}, clientThinkingData{}},
+	{PresenceData{}, clientPresenceData{}},
+	{AnnouncementData{}, clientAnnouncementData{}},
+	{StatsData{}, clientStatsData{}},
+	{TimeSyncData{}, clientTimeSyncData{}},
+	{PuzzleData{}, clientPuzzleData{}},
+	{PuzzleResultData{}, clientPuzzleResultData{}},
+	{FeaturedGameData{}, clientFeaturedGameData{}},
+	{HistoryResponseData{}, clientHistoryResponseData{}},
+	{ErrorData{}, clientErrorData{}},
+}
+
+// clientSources are the client files declaring the mirrored structs
+var clientSources = []string{
+	"../../client/wasm/lib/client.go",
+	"../../client/wasm/lib/state.go",
+}
+
+// jsonName returns the JSON key of a struct field, ok is false for fields left out of JSON
+func jsonName(field reflect.StructField) (name string, ok bool) {
+	if field.PkgPath != "" {
+		return "", false
+	}
+
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return "", false
+	}
+	if name, _, _ = strings.Cut(tag, ","); name != "" {
+		return name, true
+	}
+	return field.Name, true
+}
+
+// fieldByJSONName finds the field decoded from a JSON key, matching case-insensitively like encoding/json
+func fieldByJSONName(v reflect.Value, key string) (reflect.Value, bool) {
+	for i := 0; i < v.NumField(); i++ {
+		// Fields of an untagged embedded struct are encoded as if declared in the outer one
+		if field := v.Type().Field(i); field.Anonymous && field.Type.Kind() == reflect.Struct && field.Tag.Get("json") == "" {
+			if inner, found := fieldByJSONName(v.Field(i), key); found {
+				return inner, true
+			}
+			continue
+		}
+		if name, ok := jsonName(v.Type().Field(i)); ok && strings.EqualFold(name, key) {
+			return v.Field(i), true
+		}
+	}
+	return reflect.Value{}, false
+}
+
+// randomFill sets v to a random value, recursing into composite types
+func randomFill(v reflect.Value, r *rand.Rand) {
+	switch v.Kind() {
+	case reflect.Bool:
+		v.SetBool(r.Intn(2) == 1)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n := r.Int63() >> (64 - v.Type().Bits())
+		if r.Intn(2) == 1 {
+			n = -n
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		v.SetUint(r.Uint64() >> (64 - v.Type().Bits()))
+	case reflect.String:
+		v.SetString(randomString(r))
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			randomFill(v.Index(i), r)
+		}
+	case reflect.Slice:
+		n := r.Intn(4)
+		// Clients only render the classic board, which is all the protocol can create
+		if v.Type() == reflect.TypeOf([][]Cell(nil)) {
+			v.Set(reflect.ValueOf(NewBoard().ToArray()))
+			for row := 0; row < Rows; row++ {
+				for col := 0; col < Cols; col++ {
+					randomFill(v.Index(row).Index(col), r)
+				}
+			}
+			return
+		}
+		v.Set(reflect.MakeSlice(v.Type(), n, n))
+		for i := 0; i < n; i++ {
+			randomFill(v.Index(i), r)
+		}
+	case reflect.Map:
+		v.Set(reflect.MakeMap(v.Type()))
+		for i := r.Intn(4); i > 0; i-- {
+			key := reflect.New(v.Type().Key()).Elem()
+			value := reflect.New(v.Type().Elem()).Elem()
+			randomFill(key, r)
+			randomFill(value, r)
+			v.SetMapIndex(key, value)
+		}
+	case reflect.Pointer:
+		if r.Intn(2) == 0 {
+			v.Set(reflect.Zero(v.Type()))
+			return
+		}
+		v.Set(reflect.New(v.Type().Elem()))
+		randomFill(v.Elem(), r)
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).IsExported() {
+				randomFill(v.Field(i), r)
+			}
+		}
+	}
+}
+
+// randomString returns a short string mixing ASCII, accents and emoji
+func randomString(r *rand.Rand) string {
+	alphabet := []rune("abcXYZ019 _-\"\\/<>&éü€😀")
+	runes := make([]rune, r.Intn(9))
+	for i := range runes {
+		runes[i] = alphabet[r.Intn(len(alphabet))]
+	}
+	return string(runes)
+}
+
+// compareShared reports fields the client decodes differently from what the server encoded
+func compareShared(t *testing.T, path string, server, client reflect.Value) {
+	t.Helper()
+
+	switch client.Kind() {
+	case reflect.Struct:
+		for i := 0; i < client.NumField(); i++ {
+			name, ok := jsonName(client.Type().Field(i))
+			if !ok {
+				continue
+			}
+			serverField, found := fieldByJSONName(server, name)
+			if !found {
+				t.Errorf("%s.%s is read by the client but never sent by the server", path, name)
+				continue
+			}
+			compareShared(t, path+"."+name, serverField, client.Field(i))
+		}
+	case reflect.Pointer:
+		if server.IsNil() != client.IsNil() {
+			t.Errorf("%s: server nil %v, client nil %v", path, server.IsNil(), client.IsNil())
+			return
+		}
+		if !client.IsNil() {
+			compareShared(t, path, server.Elem(), client.Elem())
+		}
+	case reflect.Array, reflect.Slice:
+		if server.Len() != client.Len() {
+			t.Errorf("%s: server length %d, client length %d", path, server.Len(), client.Len())
+			return
+		}
+		for i := 0; i < client.Len(); i++ {
+			compareShared(t, path+"["+strconv.Itoa(i)+"]", server.Index(i), client.Index(i))
+		}
+	case reflect.Map:
+		// omitempty drops empty maps, so nil and empty are the same message
+		if server.Len() != client.Len() {
+			t.Errorf("%s: server sent %d entries, client decoded %d", path, server.Len(), client.Len())
+			return
+		}
+		for _, key := range server.MapKeys() {
+			value := client.MapIndex(key.Convert(client.Type().Key()))
+			if !value.IsValid() {
+				t.Errorf("%s: client lost key %v", path, key)
+				continue
+			}
+			compareShared(t, path+"["+key.String()+"]", server.MapIndex(key), value)
+		}
+	default:
+		// Named server types such as PlayerID or Cell compare through their JSON encoding
+		want, _ := json.Marshal(server.Interface())
+		got, _ := json.Marshal(client.Interface())
+		if string(want) != string(got) {
+			t.Errorf("%s: server sent %s, client decoded %s", path, want, got)
+		}
+	}
+}
+
+// FuzzProtocol_RoundTrip tests that random server messages decode to the same values on the client
+func FuzzProtocol_RoundTrip(f *testing.F) {
+	for seed := int64(0); seed < 64; seed++ {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, seed int64) {
+		r := rand.New(rand.NewSource(seed))
+
+		for _, pair := range protocolPairs {
+			name := reflect.TypeOf(pair.server).Name()
+
+			server := reflect.New(reflect.TypeOf(pair.server)).Elem()
+			randomFill(server, r)
+
+			raw, err := json.Marshal(server.Interface())
+			if err != nil {
+				t.Fatalf("%s: marshal failed: %v", name, err)
+			}
+
+			client := reflect.New(reflect.TypeOf(pair.client))
+			if err := json.Unmarshal(raw, client.Interface()); err != nil {
+				t.Fatalf("%s: client could not decode %s: %v", name, raw, err)
+			}
+
+			compareShared(t, name, server, client.Elem())
+		}
+	})
+}
+
+// TestProtocol_ClientMirrorsMatchSource tests that the mirrored structs still match the client sources
+func TestProtocol_ClientMirrorsMatchSource(t *testing.T) {
+	sourceFields := make(map[string][]string)
+	fset := token.NewFileSet()
+	for _, path := range clientSources {
+		if _, err := os.Stat(path); err != nil {
+			t.Skipf("Client sources not available: %v", err)
+		}
+		file, err := parser.ParseFile(fset, path, nil, 0)
+		if err != nil {
+			t.Fatalf("Failed to parse %s: %v", path, err)
+		}
+
+		ast.Inspect(file, func(n ast.Node) bool {
+			spec, ok := n.(*ast.TypeSpec)
+			if !ok {
+				return true
+			}
+			if st, ok := spec.Type.(*ast.StructType); ok {
+				sourceFields[spec.Name.Name] = astFields(st)
+			}
+			return false
+		})
+	}
+
+	mirrors := []interface{}{clientPlayer{}, clientLastMove{}, clientServerCapabilities{}, clientHistoryEntry{}}
+	for _, pair := range protocolPairs {
+		mirrors = append(mirrors, pair.client)
+	}
+
+	for _, mirror := range mirrors {
+		mirrorType := reflect.TypeOf(mirror)
+		name := strings.TrimPrefix(mirrorType.Name(), "client")
+		name = strings.ToUpper(name[:1]) + name[1:]
+
+		want, ok := sourceFields[name]
+		if !ok {
+			t.Errorf("Client struct %s no longer exists", name)
+			continue
+		}
+
+		var got []string
+		for i := 0; i < mirrorType.NumField(); i++ {
+			field := mirrorType.Field(i)
+			got = append(got, field.Name+" "+string(field.Tag))
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("Mirror of %s is out of date:\n got %v\nwant %v", name, got, want)
+		}
+	}
+}
+
+// astFields returns the name and tag of each field of a parsed struct
+func astFields(st *ast.StructType) []string {
+	var fields []string
+	for _, field := range st.Fields.List {
+		tag := ""
+		if field.Tag != nil {
+			tag, _ = strconv.Unquote(field.Tag.Value)
+		}
+		for _, name := range field.Names {
+			fields = append(fields, name.Name+" "+tag)
+		}
+	}
+	return fields
+}
+
+// TestIsStaleBuild tests that only two known and different builds are reported
+func TestIsStaleBuild(t *testing.T) {
+	cases := []struct {
+		client, server string
+		want           bool
+	}{
+		{"abc123", "abc123", false},
+		{"abc123", "def456", true},
+		{"", "def456", false},
+		{DevBuild, "def456", false},
+		{"abc123", DevBuild, false},
+		{"abc123", "", false},
+	}
+
+	for _, tc := range cases {
+		if got := IsStaleBuild(tc.client, tc.server); got != tc.want {
+			t.Errorf("IsStaleBuild(%q, %q): expected %v, got %v", tc.client, tc.server, tc.want, got)
+		}
+	}
+}
@@ -0,0 +1,213 @@
+// Copyright (c) 2025 Haute école d'ingénierie et d'architecture de Fribourg
+// SPDX-License-Identifier: Apache-2.0
+// Author: Marvin Egger marvin.egger@hotmail.ch
+// Created: 16.10.2026
+
+package lib
+
+import (
+	"math/rand/v2"
+	"time"
+)
+
+// Puzzle rush settings
+const (
+	MaxPuzzleDepth  = 2 // Puzzles are forced wins in at most this many own moves
+	PuzzleTimeLimit = 20 * time.Second
+
+	minPuzzleMoves    = 6 // Random plies played before a position may become a puzzle
+	puzzleBasePoints  = 100
+	puzzleSpeedPoints = 100 // Earned in full for an instant answer, nothing at the time limit
+)
+
+// Puzzle is a position where the side to move can force a win
+type Puzzle struct {
+	Board     [Rows][Cols]Cell
+	ToMove    Cell
+	Depth     int   // Own moves needed to force the win, 1 for an immediate win
+	Solutions []int // Columns that keep a forced win within Depth
+}
+
+// IsSolution checks if playing col keeps the forced win
+func (p Puzzle) IsSolution(col int) bool {
+	for _, solution := range p.Solutions {
+		if solution == col {
+			return true
+		}
+	}
+	return false
+}
+
+// GeneratePuzzle plays random games until the side to move has a forced win in exactly depth moves
+// The depth is drawn between 1 and maxDepth so a rush mixes easy and harder positions
+func GeneratePuzzle(rng *rand.Rand, maxDepth int) Puzzle {
+	if maxDepth < 1 {
+		maxDepth = 1
+	}
+	depth := 1 + rng.IntN(maxDepth)
+
+	board := NewBoard()
+	for {
+		board.Reset()
+		player := CellPlayer0
+
+		for ply := 0; ply < Rows*Cols; ply++ {
+			if ply >= minPuzzleMoves && !canForceWin(board, player, depth-1) {
+				if solutions := winningColumns(board, player, depth); len(solutions) > 0 {
+					return Puzzle{Board: cellsOf(board), ToMove: player, Depth: depth, Solutions: solutions}
+				}
+			}
+
+			if !playQuietMove(rng, board, player) {
+				break
+			}
+			player = opponentOf(player)
+		}
+	}
+}
+
+// playQuietMove plays a random legal column that does not end the game, false if there is none
+func playQuietMove(rng *rand.Rand, board *Board, player Cell) bool {
+	for _, col := range rng.Perm(Cols) {
+		node, ok := board.Play(col, player)
+		if !ok {
+			continue
+		}
+		if !board.CheckWin(node) {
+			return true
+		}
+		board.undo(col)
+	}
+	return false
+}
+
+// PuzzlePoints scores a correct answer, faster answers earn more
+func PuzzlePoints(elapsed, limit time.Duration) int {
+	if elapsed < 0 {
+		elapsed = 0
+	}
+	if elapsed >= limit {
+		return puzzleBasePoints
+	}
+	return puzzleBasePoints + int(int64(puzzleSpeedPoints)*int64(limit-elapsed)/int64(limit))
+}
+
+// CanForceWin checks if player, to move, wins within depth own moves whatever the opponent replies
+func CanForceWin(cells [Rows][Cols]Cell, player Cell, depth int) bool {
+	return canForceWin(boardFromCells(cells), player, depth)
+}
+
+// WinningColumns returns the columns from which player forces a win within depth own moves
+func WinningColumns(cells [Rows][Cols]Cell, player Cell, depth int) []int {
+	return winningColumns(boardFromCells(cells), player, depth)
+}
+
+// canForceWin searches in place on board, which is left as it was found
+func canForceWin(board *Board, player Cell, depth int) bool {
+	for col := 0; col < Cols; col++ {
+		if forcesWin(board, player, col, depth) {
+			return true
+		}
+	}
+	return false
+}
+
+// winningColumns lists the columns for which forcesWin holds
+func winningColumns(board *Board, player Cell, depth int) []int {
+	var cols []int
+	for col := 0; col < Cols; col++ {
+		if forcesWin(board, player, col, depth) {
+			cols = append(cols, col)
+		}
+	}
+	return cols
+}
+
+// forcesWin checks if playing col wins now or leaves every opponent reply lost within depth moves
+// Every token played during the search is taken back before returning
+func forcesWin(board *Board, player Cell, col, depth int) bool {
+	if depth < 1 {
+		return false
+	}
+
+	node, ok := board.Play(col, player)
+	if !ok {
+		return false
+	}
+	defer board.undo(col)
+
+	if board.CheckWin(node) {
+		return true
+	}
+	if depth == 1 {
+		return false
+	}
+
+	opponent := opponentOf(player)
+	replied := false
+	for reply := 0; reply < Cols; reply++ {
+		replyNode, ok := board.Play(reply, opponent)
+		if !ok {
+			continue
+		}
+		replied = true
+
+		refuted := board.CheckWin(replyNode) || !canForceWin(board, player, depth-1)
+		board.undo(reply)
+		if refuted {
+			return false
+		}
+	}
+
+	// A full board is a draw, not a win
+	return replied
+}
+
+// boardFromCells builds a board holding the given tokens, top row first
+func boardFromCells(cells [Rows][Cols]Cell) *Board {
+	board := NewBoard()
+	for col := 0; col < Cols; col++ {
+		for row := Rows - 1; row >= 0 && cells[row][col] != CellEmpty; row-- {
+			board.Play(col, cells[row][col])
+		}
+	}
+	return board
+}
+
+// cellsOf copies the tokens of a standard size board into an array
+func cellsOf(board *Board) [Rows][Cols]Cell {
+	var cells [Rows][Cols]Cell
+	for row := 0; row < Rows; row++ {
+		for col := 0; col < Cols; col++ {
+			cells[row][col] = board.GetNode(row, col).Owner
+		}
+	}
+	return cells
+}
+
+// opponentOf returns the token of the other side
+func opponentOf(player Cell) Cell {
+	if player == CellPlayer0 {
+		return CellPlayer1
+	}
+	return CellPlayer0
+}
+
+// RecordPuzzleScore keeps the best puzzle rush score and reports whether score beat it
+func (p *Player) RecordPuzzleScore(score int) bool {
+	p.Lock()
+	defer p.Unlock()
+
+	if score <= p.puzzleHighScore {
+		return false
+	}
+	p.puzzleHighScore = score
+	return true
+}
+
+// GetPuzzleHighScore returns the best puzzle rush score this session
+func (p *Player) GetPuzzleHighScore() int {
+	p.RLock()
+	defer p.RUnlock()
+	return p.puzzleHighScore
+}
@@ -0,0 +1,106 @@
+// Copyright (c) 2025 Haute école d'ingénierie et d'architecture de Fribourg
+// SPDX-License-Identifier: Apache-2.0
+// Author: Marvin Egger marvin.egger@hotmail.ch
+// Created: 16.10.2026
+
+package lib
+
+import (
+	"math/rand/v2"
+	"testing"
+	"time"
+)
+
+// TestWinningColumns_ImmediateWin tests that a third token in a row is completed in one move
+func TestWinningColumns_ImmediateWin(t *testing.T) {
+	var board [Rows][Cols]Cell
+	board[Rows-1][0] = CellPlayer0
+	board[Rows-1][1] = CellPlayer0
+	board[Rows-1][2] = CellPlayer0
+	board[Rows-2][0] = CellPlayer1
+	board[Rows-2][1] = CellPlayer1
+
+	cols := WinningColumns(board, CellPlayer0, 1)
+	if len(cols) != 1 || cols[0] != 3 {
+		t.Errorf("Expected column 3 to win at once, got %v", cols)
+	}
+}
+
+// TestWinningColumns_OpenThree tests a forced win in two moves from an open two on the bottom row
+func TestWinningColumns_OpenThree(t *testing.T) {
+	var board [Rows][Cols]Cell
+	board[Rows-1][2] = CellPlayer0
+	board[Rows-1][3] = CellPlayer0
+	board[Rows-2][2] = CellPlayer1
+	board[Rows-2][3] = CellPlayer1
+
+	if CanForceWin(board, CellPlayer0, 1) {
+		t.Fatal("There should be no immediate win")
+	}
+
+	// Either side of the two makes an open three that cannot be blocked twice
+	cols := WinningColumns(board, CellPlayer0, 2)
+	if len(cols) != 2 || cols[0] != 1 || cols[1] != 4 {
+		t.Errorf("Expected columns 1 and 4 to force the win, got %v", cols)
+	}
+}
+
+// TestGeneratePuzzle_Solvable tests that generated puzzles need exactly their depth to win
+func TestGeneratePuzzle_Solvable(t *testing.T) {
+	rng := rand.New(rand.NewPCG(1, 2))
+
+	for i := 0; i < 20; i++ {
+		puzzle := GeneratePuzzle(rng, MaxPuzzleDepth)
+		board := boardFromCells(puzzle.Board)
+
+		if puzzle.Depth < 1 || puzzle.Depth > MaxPuzzleDepth {
+			t.Fatalf("Unexpected depth %d", puzzle.Depth)
+		}
+		if len(puzzle.Solutions) == 0 {
+			t.Fatal("A puzzle should have a solution")
+		}
+		if CanForceWin(puzzle.Board, puzzle.ToMove, puzzle.Depth-1) {
+			t.Errorf("Puzzle %d can be won faster than its depth %d", i, puzzle.Depth)
+		}
+		for _, col := range puzzle.Solutions {
+			if !forcesWin(board, puzzle.ToMove, col, puzzle.Depth) {
+				t.Errorf("Solution %d of puzzle %d does not force the win", col, i)
+			}
+		}
+	}
+}
+
+// TestForcesWin_RestoresBoard tests that the in place search takes back every token it plays
+func TestForcesWin_RestoresBoard(t *testing.T) {
+	var cells [Rows][Cols]Cell
+	cells[Rows-1][2] = CellPlayer0
+	cells[Rows-1][3] = CellPlayer0
+	cells[Rows-2][2] = CellPlayer1
+	cells[Rows-2][3] = CellPlayer1
+
+	board := boardFromCells(cells)
+	for col := 0; col < Cols; col++ {
+		forcesWin(board, CellPlayer0, col, 2)
+	}
+	if cellsOf(board) != cells {
+		t.Error("The search should leave the board as it found it")
+	}
+	if _, ok := board.Play(2, CellPlayer0); !ok || board.GetNode(Rows-3, 2).Owner != CellPlayer0 {
+		t.Error("Column heights should be restored with the tokens")
+	}
+}
+
+// TestPuzzlePoints_FasterEarnsMore tests the speed bonus bounds
+func TestPuzzlePoints_FasterEarnsMore(t *testing.T) {
+	limit := PuzzleTimeLimit
+
+	if got := PuzzlePoints(0, limit); got != puzzleBasePoints+puzzleSpeedPoints {
+		t.Errorf("Instant answer should earn the full bonus, got %d", got)
+	}
+	if got := PuzzlePoints(limit, limit); got != puzzleBasePoints {
+		t.Errorf("Answer at the limit should earn the base points, got %d", got)
+	}
+	if PuzzlePoints(time.Second, limit) <= PuzzlePoints(10*time.Second, limit) {
+		t.Error("Faster answers should earn more")
+	}
+}
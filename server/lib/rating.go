@@ -0,0 +1,41 @@
+// Copyright (c) 2025 Haute école d'ingénierie et d'architecture de Fribourg
+// SPDX-License-Identifier: Apache-2.0
+// Author: Marvin Egger marvin.egger@hotmail.ch
+// Created: 16.10.2026
+
+package lib
+
+import "math"
+
+const (
+	// DefaultRating is the ELO rating every player starts a session with
+	DefaultRating = 1200
+
+	ratingK     = 32  // Most points a single game can move a rating
+	ratingScale = 400 // Rating gap at which the stronger player is expected to score ten times more
+)
+
+// ExpectedScore returns the share of points a player rated rating is expected to take against opponent
+func ExpectedScore(rating, opponent int) float64 {
+	return 1 / (1 + math.Pow(10, float64(opponent-rating)/ratingScale))
+}
+
+// RatingChange returns the points a player gains, negative when lost, for a score of 1 for a win, 0.5 for a draw and 0 for a loss
+func RatingChange(rating, opponent int, score float64) int {
+	return int(math.Round(ratingK * (score - ExpectedScore(rating, opponent))))
+}
+
+// GetRating returns the ELO rating of the player
+func (p *Player) GetRating() int {
+	p.RLock()
+	defer p.RUnlock()
+	return p.Rating
+}
+
+// AdjustRating adds delta to the rating of the player and returns the new rating
+func (p *Player) AdjustRating(delta int) int {
+	p.Lock()
+	defer p.Unlock()
+	p.Rating += delta
+	return p.Rating
+}
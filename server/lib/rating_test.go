@@ -0,0 +1,37 @@
+// Copyright (c) 2025 Haute école d'ingénierie et d'architecture de Fribourg
+// SPDX-License-Identifier: Apache-2.0
+// Author: Marvin Egger marvin.egger@hotmail.ch
+// Created: 16.10.2026
+
+package lib
+
+import "testing"
+
+// TestRatingChange_Elo tests the gains for wins, draws and losses between equal and uneven players
+func TestRatingChange_Elo(t *testing.T) {
+	tests := []struct {
+		name             string
+		rating, opponent int
+		score            float64
+		want             int
+	}{
+		{"equal win", 1200, 1200, 1, 16},
+		{"equal draw", 1200, 1200, 0.5, 0},
+		{"equal loss", 1200, 1200, 0, -16},
+		{"favorite wins", 1600, 1200, 1, 3},
+		{"underdog wins", 1200, 1600, 1, 29},
+		{"underdog draws", 1200, 1600, 0.5, 13},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RatingChange(tt.rating, tt.opponent, tt.score); got != tt.want {
+				t.Errorf("Expected %+d, got %+d", tt.want, got)
+			}
+		})
+	}
+
+	if sum := ExpectedScore(1500, 1300) + ExpectedScore(1300, 1500); sum < 0.999 || sum > 1.001 {
+		t.Errorf("Expected scores of both players should add up to 1, got %f", sum)
+	}
+}
@@ -0,0 +1,159 @@
+// Copyright (c) 2025 Haute école d'ingénierie et d'architecture de Fribourg
+// SPDX-License-Identifier: Apache-2.0
+// Author: Marvin Egger marvin.egger@hotmail.ch
+// Created: 16.10.2026
+
+package lib
+
+import "time"
+
+// MaxBestOf is the longest series a friend game can be set up for
+const MaxBestOf = 9
+
+// SeriesTieBreak decides a series whose rounds ran out without a majority, after draws
+type SeriesTieBreak string
+
+const (
+	TieBreakSuddenDeath SeriesTieBreak = "sudden-death" // Extra rounds until one of them is won
+	TieBreakShared      SeriesTieBreak = "shared"       // Both sides share the victory
+	TieBreakTime        SeriesTieBreak = "time"         // The side with more clock left over all rounds wins, shared if even
+)
+
+// ParseSeriesTieBreak checks a tie-break name, reporting whether it is known
+func ParseSeriesTieBreak(name string) (SeriesTieBreak, bool) {
+	switch tieBreak := SeriesTieBreak(name); tieBreak {
+	case TieBreakSuddenDeath, TieBreakShared, TieBreakTime:
+		return tieBreak, true
+	}
+	return "", false
+}
+
+// ClampBestOf returns a valid series length, 1 for a single game
+func ClampBestOf(bestOf int) int {
+	if bestOf < 1 {
+		return 1
+	}
+	if bestOf > MaxBestOf {
+		return MaxBestOf
+	}
+	return bestOf
+}
+
+// InSeries checks if the game is played as a best-of series
+func (g *Game) InSeries() bool {
+	return g.BestOf > 1
+}
+
+// scoreRound credits a won round to its side and ends the series once a side has a majority
+// When the rounds run out without one, the leader wins and an even score goes to the tie-break
+func (g *Game) scoreRound(result GameResult) {
+	if !g.InSeries() || g.SeriesOver {
+		return
+	}
+
+	g.RoundsPlayed++
+	for i := range g.ClockBank {
+		g.ClockBank[i] += g.TimeRemaining[i]
+	}
+
+	switch result {
+	case ResultPlayer0Win:
+		g.Score[0]++
+	case ResultPlayer1Win:
+		g.Score[1]++
+	}
+
+	switch {
+	case g.Score[0] > g.BestOf/2:
+		g.endSeries(ResultPlayer0Win)
+	case g.Score[1] > g.BestOf/2:
+		g.endSeries(ResultPlayer1Win)
+	case g.RoundsPlayed < g.BestOf:
+	case g.Score[0] > g.Score[1]:
+		g.endSeries(ResultPlayer0Win)
+	case g.Score[1] > g.Score[0]:
+		g.endSeries(ResultPlayer1Win)
+	default:
+		g.breakTie()
+	}
+}
+
+// breakTie applies the tie-break to a series whose rounds ran out on an even score
+func (g *Game) breakTie() {
+	switch g.TieBreak {
+	case TieBreakShared:
+		g.endSeries(ResultDraw)
+	case TieBreakTime:
+		switch {
+		case g.ClockBank[0] > g.ClockBank[1]:
+			g.endSeries(ResultPlayer0Win)
+		case g.ClockBank[1] > g.ClockBank[0]:
+			g.endSeries(ResultPlayer1Win)
+		default:
+			g.endSeries(ResultDraw)
+		}
+	default:
+		// Sudden death, the next won round is one more than the other side has
+	}
+}
+
+// endSeries closes the series, a draw result is a shared victory
+func (g *Game) endSeries(result GameResult) {
+	g.SeriesOver = true
+	g.SeriesResult = result
+}
+
+// startSeries clears the score of a finished series before it is played again
+func (g *Game) startSeries() {
+	g.Score = [2]int{}
+	g.RoundsPlayed = 0
+	g.ClockBank = [2]time.Duration{}
+	g.SeriesOver = false
+	g.SeriesResult = ResultNone
+}
+
+// ResignRound concedes the current round of a series to the opponent
+// The series goes on unless the concession decides it, see NextRound
+func (g *Game) ResignRound(loserIdx int) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if !g.InSeries() {
+		return ErrNotInSeries
+	}
+	if g.Status != StatusPlaying {
+		return ErrGameNotPlaying
+	}
+
+	g.finish(GameResult(2 - loserIdx))
+	g.WinMethod = WinForfeit
+	return nil
+}
+
+// NextRound starts the following round of an undecided series with the other side beginning
+func (g *Game) NextRound() bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if !g.InSeries() || g.SeriesOver || g.Status != StatusFinished {
+		return false
+	}
+
+	g.reset()
+	g.swapBeginningPlayer()
+	return true
+}
+
+// GetSeriesResult returns who won a finished series, ResultDraw for a shared victory
+func (g *Game) GetSeriesResult() GameResult {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return g.SeriesResult
+}
+
+// GetScore returns the rounds won by each side of a series
+func (g *Game) GetScore() [2]int {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return g.Score
+}
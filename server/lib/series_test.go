@@ -0,0 +1,108 @@
+// Copyright (c) 2025 Haute école d'ingénierie et d'architecture de Fribourg
+// SPDX-License-Identifier: Apache-2.0
+// Author: Marvin Egger marvin.egger@hotmail.ch
+// Created: 16.10.2026
+
+package lib
+
+import (
+	"testing"
+	"time"
+)
+
+// newTestSeries starts a best-of series between two players
+func newTestSeries(bestOf int, tieBreak SeriesTieBreak) *Game {
+	game := NewGame(time.Minute, 0)
+	game.BestOf = bestOf
+	game.TieBreak = tieBreak
+	game.AddPlayer(NewPlayer("Alice", 0))
+	game.AddPlayer(NewPlayer("Bob", 0))
+	return game
+}
+
+// drawRounds plays drawn rounds, starting the next one after each
+func drawRounds(t *testing.T, game *Game, rounds int) {
+	t.Helper()
+	for i := 0; i < rounds; i++ {
+		game.finishAsDraw(DrawAgreement)
+		if i < rounds-1 && !game.NextRound() {
+			t.Fatalf("Round %d should be followed by another", i+2)
+		}
+	}
+}
+
+// TestSeries_SuddenDeath tests that an even series goes on until a round is won
+func TestSeries_SuddenDeath(t *testing.T) {
+	game := newTestSeries(2, TieBreakSuddenDeath)
+	drawRounds(t, game, 2)
+	if game.SeriesOver {
+		t.Fatal("An even series should go to sudden death")
+	}
+
+	// Drawn extra rounds do not decide anything either
+	for i := 0; i < 2; i++ {
+		if !game.NextRound() {
+			t.Fatal("Sudden death should start an extra round")
+		}
+		game.finishAsDraw(DrawAgreement)
+		if game.SeriesOver {
+			t.Fatal("A drawn extra round should not end the series")
+		}
+	}
+
+	game.NextRound()
+	game.finish(ResultPlayer1Win)
+	if !game.SeriesOver || game.SeriesResult != ResultPlayer1Win {
+		t.Errorf("Expected the won extra round to decide the series, got over %v result %v", game.SeriesOver, game.SeriesResult)
+	}
+}
+
+// TestSeries_Shared tests that an even series can end as a shared victory
+func TestSeries_Shared(t *testing.T) {
+	game := newTestSeries(4, TieBreakShared)
+	game.finish(ResultPlayer0Win)
+	game.NextRound()
+	game.finish(ResultPlayer0Win)
+	game.NextRound()
+	drawRounds(t, game, 2)
+
+	// Sides swap every round, so each side won one round
+	if score := game.GetScore(); score != [2]int{1, 1} {
+		t.Fatalf("Expected a 1-1 score, got %v", score)
+	}
+	if !game.SeriesOver || game.SeriesResult != ResultDraw {
+		t.Errorf("Expected a shared victory, got over %v result %v", game.SeriesOver, game.SeriesResult)
+	}
+	if game.NextRound() {
+		t.Error("No round should follow a shared victory")
+	}
+}
+
+// TestSeries_Time tests that an even series goes to the side with more clock left
+func TestSeries_Time(t *testing.T) {
+	game := newTestSeries(2, TieBreakTime)
+	game.TimeRemaining = [2]time.Duration{10 * time.Second, 30 * time.Second}
+	game.finishAsDraw(DrawAgreement)
+	game.NextRound()
+
+	// Side 1 of the first round begins the second one on side 0
+	game.TimeRemaining = [2]time.Duration{20 * time.Second, 15 * time.Second}
+	game.finishAsDraw(DrawAgreement)
+
+	if !game.SeriesOver || game.SeriesResult != ResultPlayer0Win {
+		t.Errorf("Expected the side with 50s left over the series to win, got over %v result %v", game.SeriesOver, game.SeriesResult)
+	}
+}
+
+// TestSeries_LeaderWinsWhenRoundsRunOut tests that a lead without a majority still wins once the rounds are played
+func TestSeries_LeaderWinsWhenRoundsRunOut(t *testing.T) {
+	game := newTestSeries(3, TieBreakSuddenDeath)
+	game.finish(ResultPlayer0Win)
+	game.NextRound()
+	drawRounds(t, game, 2)
+
+	// The round winner moved to side 1 with the first swap and back to side 0 with the second
+	if !game.SeriesOver || game.SeriesResult != ResultPlayer0Win {
+		t.Errorf("Expected the leader to win, got over %v result %v with score %v", game.SeriesOver, game.SeriesResult, game.GetScore())
+	}
+}
@@ -0,0 +1,60 @@
+// Copyright (c) 2025 Haute école d'ingénierie et d'architecture de Fribourg
+// SPDX-License-Identifier: Apache-2.0
+// Author: Marvin Egger marvin.egger@hotmail.ch
+// Created: 16.10.2026
+
+package lib
+
+// Side groups the players controlling one token color
+// Classic games have a single member per side, team games rotate members after each move
+type Side struct {
+	Members []*Player
+	active  int
+}
+
+// Active returns the member currently allowed to move for this side
+func (s *Side) Active() *Player {
+	if len(s.Members) == 0 {
+		return nil
+	}
+	return s.Members[s.active]
+}
+
+// Has checks if a player is a member of this side
+func (s *Side) Has(id PlayerID) bool {
+	for _, p := range s.Members {
+		if p != nil && p.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// Size returns the number of members of this side
+func (s *Side) Size() int {
+	return len(s.Members)
+}
+
+// Add appends a member to this side
+func (s *Side) Add(player *Player) {
+	s.Members = append(s.Members, player)
+}
+
+// Rotate hands control to the next member of this side
+func (s *Side) Rotate() {
+	if len(s.Members) > 1 {
+		s.active = (s.active + 1) % len(s.Members)
+	}
+}
+
+// RotateBack hands control back to the previous member of this side
+func (s *Side) RotateBack() {
+	if len(s.Members) > 1 {
+		s.active = (s.active + len(s.Members) - 1) % len(s.Members)
+	}
+}
+
+// ResetRotation gives control back to the first member
+func (s *Side) ResetRotation() {
+	s.active = 0
+}
@@ -0,0 +1,102 @@
+// Copyright (c) 2025 Haute école d'ingénierie et d'architecture de Fribourg
+// SPDX-License-Identifier: Apache-2.0
+// Author: Marvin Egger marvin.egger@hotmail.ch
+// Created: 16.10.2026
+
+package lib
+
+// MaxSpectators bounds the players watching a single game
+const MaxSpectators = 20
+
+// Watchable checks if anyone may follow the game, matchmaking, bot and invite-only games are private
+// Both fields are set before the game is shared, so they are read without the lock
+func (g *Game) Watchable() bool {
+	return g.Public && g.Invite == ""
+}
+
+// AddSpectator registers a player watching the game, members of the game cannot spectate it
+// Adding a player already watching is a no-op
+func (g *Game) AddSpectator(player *Player) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if !g.Watchable() {
+		return ErrPrivateSpectate
+	}
+	if g.Sides[0].Has(player.ID) || g.Sides[1].Has(player.ID) {
+		return ErrSpectateOwnGame
+	}
+	if _, watching := g.spectators[player.ID]; watching {
+		return nil
+	}
+	if len(g.spectators) >= MaxSpectators {
+		return ErrTooManySpectators
+	}
+
+	if g.spectators == nil {
+		g.spectators = make(map[PlayerID]*Player)
+	}
+	g.spectators[player.ID] = player
+	return nil
+}
+
+// RemoveSpectator stops sending the game to a player, returns false if it was not watching
+func (g *Game) RemoveSpectator(id PlayerID) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if _, watching := g.spectators[id]; !watching {
+		return false
+	}
+	delete(g.spectators, id)
+	return true
+}
+
+// GetSpectators returns the players watching the game
+func (g *Game) GetSpectators() []*Player {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	spectators := make([]*Player, 0, len(g.spectators))
+	for _, player := range g.spectators {
+		spectators = append(spectators, player)
+	}
+	return spectators
+}
+
+// BroadcastToSpectators sends a message to the players watching the game once SpectatorDelay has passed
+// Players who stopped watching in the meantime do not receive it
+func (g *Game) BroadcastToSpectators(msg Message) {
+	g.mu.Lock()
+	if len(g.spectators) == 0 {
+		g.mu.Unlock()
+		return
+	}
+	if g.spectatorFeed == nil {
+		g.spectatorFeed = NewDelayedFeed(g.SpectatorDelay, g.sendToSpectators)
+	}
+	feed := g.spectatorFeed
+	g.mu.Unlock()
+
+	feed.Push(msg)
+}
+
+// SendToSpectator sends a message to one spectator once SpectatorDelay has passed, unless it stopped watching
+func (g *Game) SendToSpectator(player *Player, msg Message) {
+	NewDelayedFeed(g.SpectatorDelay, func(msg Message) {
+		g.mu.RLock()
+		_, watching := g.spectators[player.ID]
+		g.mu.RUnlock()
+
+		if watching {
+			player.Send(msg)
+		}
+	}).Push(msg)
+}
+
+// sendToSpectators delivers a message from the spectator feed to the current spectators
+func (g *Game) sendToSpectators(msg Message) {
+	for _, player := range g.GetSpectators() {
+		player.Send(msg)
+	}
+}
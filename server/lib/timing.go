@@ -0,0 +1,43 @@
+// Copyright (c) 2025 Haute école d'ingénierie et d'architecture de Fribourg
+// SPDX-License-Identifier: Apache-2.0
+// Author: Marvin Egger marvin.egger@hotmail.ch
+// Created: 16.10.2026
+
+package lib
+
+import "time"
+
+// Move timing thresholds used to flag implausibly fast players
+const (
+	SuspiciousAverageMoveTime = 300 * time.Millisecond
+	MinMovesForTimingCheck    = 5
+)
+
+// MoveTiming tracks how long one side thinks before its moves
+type MoveTiming struct {
+	Count int
+	Total time.Duration
+	Min   time.Duration
+}
+
+// Record adds the think time of a move
+func (m *MoveTiming) Record(think time.Duration) {
+	if m.Count == 0 || think < m.Min {
+		m.Min = think
+	}
+	m.Count++
+	m.Total += think
+}
+
+// Average returns the mean think time
+func (m *MoveTiming) Average() time.Duration {
+	if m.Count == 0 {
+		return 0
+	}
+	return m.Total / time.Duration(m.Count)
+}
+
+// IsSuspicious checks if enough moves were played implausibly fast
+func (m *MoveTiming) IsSuspicious() bool {
+	return m.Count >= MinMovesForTimingCheck && m.Average() < SuspiciousAverageMoveTime
+}
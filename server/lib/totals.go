@@ -0,0 +1,90 @@
+// Copyright (c) 2025 Haute école d'ingénierie et d'architecture de Fribourg
+// SPDX-License-Identifier: Apache-2.0
+// Author: Marvin Egger marvin.egger@hotmail.ch
+// Created: 16.10.2026
+
+package lib
+
+import (
+	"sync"
+	"time"
+)
+
+// dayLayout names the UTC day the daily totals belong to
+const dayLayout = "2006-01-02"
+
+// Outcomes counts finished games by result
+type Outcomes struct {
+	Games    int `json:"games"`
+	Decisive int `json:"decisive"`
+	Draws    int `json:"draws"`
+}
+
+// add counts one finished game
+func (o *Outcomes) add(result GameResult) {
+	o.Games++
+	if result == ResultDraw {
+		o.Draws++
+	} else {
+		o.Decisive++
+	}
+}
+
+// TotalsSnapshot is a copy of the game totals, also the form they are saved in
+type TotalsSnapshot struct {
+	AllTime Outcomes `json:"all_time"`
+	Day     string   `json:"day"` // UTC day Today belongs to
+	Today   Outcomes `json:"today"`
+}
+
+// GameTotals counts finished games over all time and for the current UTC day, safe for concurrent use
+type GameTotals struct {
+	mu      sync.Mutex
+	allTime Outcomes
+	day     string
+	today   Outcomes
+}
+
+// NewGameTotals creates empty game totals
+func NewGameTotals() *GameTotals {
+	return &GameTotals{}
+}
+
+// Record counts a finished game, games ended without a result are ignored
+func (t *GameTotals) Record(result GameResult, now time.Time) {
+	if result == ResultNone {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.rollOver(now)
+	t.allTime.add(result)
+	t.today.add(result)
+}
+
+// Snapshot returns a copy of the totals, the daily ones reset once the day is over
+func (t *GameTotals) Snapshot(now time.Time) TotalsSnapshot {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.rollOver(now)
+	return TotalsSnapshot{AllTime: t.allTime, Day: t.day, Today: t.today}
+}
+
+// Restore replaces the totals with saved ones, daily totals of another day are dropped on the next read
+func (t *GameTotals) Restore(snapshot TotalsSnapshot) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.allTime = snapshot.AllTime
+	t.day = snapshot.Day
+	t.today = snapshot.Today
+}
+
+// rollOver starts new daily totals when the UTC day changed, the caller holds the lock
+func (t *GameTotals) rollOver(now time.Time) {
+	day := now.UTC().Format(dayLayout)
+	if day != t.day {
+		t.day = day
+		t.today = Outcomes{}
+	}
+}
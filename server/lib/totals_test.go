@@ -0,0 +1,36 @@
+// Copyright (c) 2025 Haute école d'ingénierie et d'architecture de Fribourg
+// SPDX-License-Identifier: Apache-2.0
+// Author: Marvin Egger marvin.egger@hotmail.ch
+// Created: 16.10.2026
+
+package lib
+
+import (
+	"testing"
+	"time"
+)
+
+// TestGameTotals_DayRollOver tests that daily totals restart with the UTC day while all-time ones keep counting
+func TestGameTotals_DayRollOver(t *testing.T) {
+	totals := NewGameTotals()
+	evening := time.Date(2026, 10, 16, 23, 30, 0, 0, time.UTC)
+	totals.Record(ResultPlayer0Win, evening)
+	totals.Record(ResultDraw, evening)
+	totals.Record(ResultNone, evening)
+
+	snapshot := totals.Snapshot(evening)
+	if snapshot.Today != (Outcomes{Games: 2, Decisive: 1, Draws: 1}) {
+		t.Errorf("Expected 2 games today, aborted ones ignored, got %+v", snapshot.Today)
+	}
+
+	morning := evening.Add(time.Hour)
+	totals.Record(ResultPlayer1Win, morning)
+
+	snapshot = totals.Snapshot(morning)
+	if snapshot.Day != "2026-10-17" || snapshot.Today != (Outcomes{Games: 1, Decisive: 1}) {
+		t.Errorf("Expected a fresh day with 1 game, got %s %+v", snapshot.Day, snapshot.Today)
+	}
+	if snapshot.AllTime != (Outcomes{Games: 3, Decisive: 2, Draws: 1}) {
+		t.Errorf("Expected 3 games all time, got %+v", snapshot.AllTime)
+	}
+}
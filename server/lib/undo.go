@@ -0,0 +1,104 @@
+// Copyright (c) 2025 Haute école d'ingénierie et d'architecture de Fribourg
+// SPDX-License-Identifier: Apache-2.0
+// Author: Marvin Egger marvin.egger@hotmail.ch
+// Created: 16.10.2026
+
+package lib
+
+import "time"
+
+// RequestUndo asks to take back the last move, only the side that made it may ask
+func (g *Game) RequestUndo(playerIdx int) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if err := g.checkInPlay(); err != nil {
+		return err
+	}
+	if len(g.moves) == 0 || playerIdx == g.CurrentTurn {
+		return ErrNothingToUndo
+	}
+
+	g.UndoRequests[playerIdx] = true
+	return nil
+}
+
+// AnswerUndo accepts or declines the takeback asked by the other side, returns whether the move was taken back
+func (g *Game) AnswerUndo(playerIdx int, accept bool) (bool, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if err := g.checkInPlay(); err != nil {
+		return false, err
+	}
+	if !g.UndoRequests[1-playerIdx] {
+		return false, ErrNoUndoRequest
+	}
+
+	g.UndoRequests = [2]bool{}
+	if !accept {
+		return false, nil
+	}
+	return true, g.undo()
+}
+
+// Undo takes the last move off the board and gives the turn back to the side that made it
+// The game ends as a draw when this repeats a position too often
+func (g *Game) Undo() error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if err := g.checkInPlay(); err != nil {
+		return err
+	}
+	return g.undo()
+}
+
+// checkInPlay refuses takebacks and draw offers once the game is over or while its clock is frozen
+func (g *Game) checkInPlay() error {
+	if g.Status != StatusPlaying {
+		return ErrGameNotPlaying
+	}
+	if g.Paused {
+		return ErrGamePaused
+	}
+	return nil
+}
+
+// undo pops the last move, expects g.mu to be held
+func (g *Game) undo() error {
+	if len(g.moves) == 0 {
+		return ErrNothingToUndo
+	}
+	last := g.moves[len(g.moves)-1]
+
+	g.stopTimer()
+	g.Board.undo(last.Col)
+	g.moves = g.moves[:len(g.moves)-1]
+	g.MoveCount--
+	g.UndoRequests = [2]bool{}
+
+	g.LastMove = nil
+	if len(g.moves) > 0 {
+		previous := g.moves[len(g.moves)-1].LastMove
+		g.LastMove = &previous
+	}
+
+	// The mover gets back the member who played, the clock without its increment and its think times
+	mover := 1 - g.CurrentTurn
+	g.unrotateSide(mover)
+	g.TimeRemaining[mover] -= last.increment
+	g.Timing[mover] = last.timing
+
+	g.CurrentTurn = mover
+
+	// The position played back to occurs once more, takebacks cannot loop forever
+	if g.recordPosition(g.CurrentTurn) {
+		g.finishAsDraw(DrawRepetition)
+		return nil
+	}
+
+	g.TurnStartedAt = time.Now()
+	g.startTimer()
+	return nil
+}
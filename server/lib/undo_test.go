@@ -0,0 +1,126 @@
+// Copyright (c) 2025 Haute école d'ingénierie et d'architecture de Fribourg
+// SPDX-License-Identifier: Apache-2.0
+// Author: Marvin Egger marvin.egger@hotmail.ch
+// Created: 16.10.2026
+
+package lib
+
+import (
+	"testing"
+	"time"
+)
+
+// TestAnswerUndo_TakesBackLastMove tests that an accepted takeback restores the board, the turn and the count
+func TestAnswerUndo_TakesBackLastMove(t *testing.T) {
+	game := NewGame(time.Minute, 0)
+	game.AddPlayer(NewPlayer("Alice", 0))
+	game.AddPlayer(NewPlayer("Bob", 0))
+	defer game.Cleanup()
+
+	first := game.CurrentTurn
+	game.Play(first, 3)
+	game.Play(1-first, 4)
+
+	if err := game.RequestUndo(first); err != ErrNothingToUndo {
+		t.Errorf("Only the side that just moved may ask, got %v", err)
+	}
+	if err := game.RequestUndo(1 - first); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := game.AnswerUndo(1-first, true); err != ErrNoUndoRequest {
+		t.Errorf("A side cannot accept its own request, got %v", err)
+	}
+
+	undone, err := game.AnswerUndo(first, true)
+	if err != nil || !undone {
+		t.Fatalf("Expected the move taken back, got %v %v", undone, err)
+	}
+	if game.CurrentTurn != 1-first || game.MoveCount != 1 {
+		t.Errorf("Expected the turn back to the requester after 1 move, got turn %d after %d", game.CurrentTurn, game.MoveCount)
+	}
+	if game.Board.GetNode(Rows-1, 4).Owner != CellEmpty || game.Board.GetNode(Rows-1, 3).Owner == CellEmpty {
+		t.Error("Only the last token should leave the board")
+	}
+	if game.LastMove == nil || game.LastMove.Col != 3 {
+		t.Errorf("Expected the previous move to be the last one again, got %+v", game.LastMove)
+	}
+
+	// The column is free again for the side that took its move back
+	if err := game.Play(1-first, 4); err != nil {
+		t.Errorf("Unexpected error replaying the move: %v", err)
+	}
+}
+
+// TestUndo_Refused tests the takebacks that are not possible
+func TestUndo_Refused(t *testing.T) {
+	game := NewGame(time.Minute, 0)
+	game.AddPlayer(NewPlayer("Alice", 0))
+	game.AddPlayer(NewPlayer("Bob", 0))
+	defer game.Cleanup()
+
+	if err := game.Undo(); err != ErrNothingToUndo {
+		t.Errorf("Expected ErrNothingToUndo on an empty board, got %v", err)
+	}
+
+	first := game.CurrentTurn
+	game.Play(first, 0)
+	game.RequestUndo(first)
+	if undone, err := game.AnswerUndo(1-first, false); undone || err != nil {
+		t.Errorf("A declined takeback should leave the move, got %v %v", undone, err)
+	}
+	if game.MoveCount != 1 {
+		t.Errorf("Expected the move to stay, got %d moves", game.MoveCount)
+	}
+
+	game.Forfeit(first)
+	if err := game.Undo(); err != ErrGameNotPlaying {
+		t.Errorf("Expected ErrGameNotPlaying once finished, got %v", err)
+	}
+}
+
+// TestUndo_TeamRotation tests that a takeback hands the side back to the member who made the move
+func TestUndo_TeamRotation(t *testing.T) {
+	game := NewGame(time.Minute, 0)
+	game.TeamSize = 2
+	for _, name := range []string{"Alice", "Bob", "Carol", "Dave"} {
+		game.AddPlayer(NewPlayer(name, 0))
+	}
+	defer game.Cleanup()
+
+	side := game.CurrentTurn
+	first := game.Players[side]
+	game.Play(side, 0)
+
+	if err := game.Undo(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if game.Players[side] != first || game.Sides[side].Active() != first {
+		t.Error("The member who made the move should play it again")
+	}
+}
+
+// TestUndo_RestoresIncrementAndTiming tests that a takeback removes the increment and the think time of the move
+func TestUndo_RestoresIncrementAndTiming(t *testing.T) {
+	increment := 5 * time.Second
+	game := NewGame(time.Minute, increment)
+	game.AddPlayer(NewPlayer("Alice", 0))
+	game.AddPlayer(NewPlayer("Bob", 0))
+	defer game.Cleanup()
+
+	first := game.CurrentTurn
+	before := game.TimeRemaining[first]
+	game.Play(first, 3)
+	if game.TimeRemaining[first] <= before {
+		t.Fatal("The move should have earned the increment")
+	}
+
+	if err := game.Undo(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if remaining := game.TimeRemaining[first]; remaining > before || remaining < before-time.Second {
+		t.Errorf("Expected the clock back near %v without the increment, got %v", before, remaining)
+	}
+	if timing := game.GetTiming()[first]; timing.Count != 0 || timing.Total != 0 {
+		t.Errorf("Expected the think time of the move dropped, got %+v", timing)
+	}
+}
@@ -0,0 +1,74 @@
+// Copyright (c) 2025 Haute école d'ingénierie et d'architecture de Fribourg
+// SPDX-License-Identifier: Apache-2.0
+// Author: Marvin Egger marvin.egger@hotmail.ch
+// Created: 16.10.2026
+
+package lib
+
+// Cosmetics unlocked by winning, the IDs match the client disc skins
+const (
+	UnlockFruitSkin  = "fruit"
+	UnlockHeartsSkin = "hearts"
+)
+
+// Wins needed in a session for each unlock
+const (
+	UnlockFruitWins  = 3
+	UnlockHeartsWins = 10
+)
+
+// unlockMilestones lists unlocks by increasing win count
+var unlockMilestones = []struct {
+	wins   int
+	unlock string
+}{
+	{UnlockFruitWins, UnlockFruitSkin},
+	{UnlockHeartsWins, UnlockHeartsSkin},
+}
+
+// UnlocksFor returns the cosmetics available after a number of wins
+func UnlocksFor(wins int) []string {
+	unlocks := []string{}
+	for _, milestone := range unlockMilestones {
+		if wins >= milestone.wins {
+			unlocks = append(unlocks, milestone.unlock)
+		}
+	}
+	return unlocks
+}
+
+// RecordWin counts a won game and returns the cosmetics it just unlocked
+func (p *Player) RecordWin() []string {
+	p.Lock()
+	defer p.Unlock()
+
+	before := len(UnlocksFor(p.wins))
+	p.wins++
+	p.streak++
+	p.bestStreak = max(p.bestStreak, p.streak)
+	return UnlocksFor(p.wins)[before:]
+}
+
+// BreakStreak ends the current win streak after a loss or a draw, reporting whether one was running
+func (p *Player) BreakStreak() bool {
+	p.Lock()
+	defer p.Unlock()
+
+	broken := p.streak > 0
+	p.streak = 0
+	return broken
+}
+
+// GetStreaks returns the current and best win streaks of this session
+func (p *Player) GetStreaks() (current, best int) {
+	p.RLock()
+	defer p.RUnlock()
+	return p.streak, p.bestStreak
+}
+
+// GetWins returns the number of games won this session
+func (p *Player) GetWins() int {
+	p.RLock()
+	defer p.RUnlock()
+	return p.wins
+}
@@ -0,0 +1,84 @@
+// Copyright (c) 2025 Haute école d'ingénierie et d'architecture de Fribourg
+// SPDX-License-Identifier: Apache-2.0
+// Author: Marvin Egger marvin.egger@hotmail.ch
+// Created: 16.10.2026
+
+package lib
+
+import "sync"
+
+// WinMethod describes how a game was won
+type WinMethod string
+
+const (
+	WinNone       WinMethod = ""
+	WinHorizontal WinMethod = "horizontal"
+	WinVertical   WinMethod = "vertical"
+	WinDiagonal   WinMethod = "diagonal"
+	WinTimeout    WinMethod = "timeout"
+	WinForfeit    WinMethod = "forfeit"
+)
+
+// WinMethodFor classifies the direction of a winning line
+func WinMethodFor(dir Direction) WinMethod {
+	switch {
+	case dir.IsHorizontal():
+		return WinHorizontal
+	case dir.IsVertical():
+		return WinVertical
+	}
+	return WinDiagonal
+}
+
+// WinStats counts finished games by how they were won, safe for concurrent use
+type WinStats struct {
+	mu     sync.Mutex
+	counts map[WinMethod]int
+}
+
+// NewWinStats creates empty win stats
+func NewWinStats() *WinStats {
+	return &WinStats{counts: make(map[WinMethod]int)}
+}
+
+// Record counts a won game, draws and unfinished games are ignored
+func (s *WinStats) Record(method WinMethod) {
+	if method == WinNone {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.counts[method]++
+}
+
+// Snapshot returns a copy of the counts with every method present
+func (s *WinStats) Snapshot() map[WinMethod]int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	snapshot := map[WinMethod]int{
+		WinHorizontal: 0,
+		WinVertical:   0,
+		WinDiagonal:   0,
+		WinTimeout:    0,
+		WinForfeit:    0,
+	}
+	for method, count := range s.counts {
+		snapshot[method] = count
+	}
+	return snapshot
+}
+
+// Restore replaces the counts with saved ones, unknown methods are dropped
+func (s *WinStats) Restore(counts map[WinMethod]int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.counts = make(map[WinMethod]int)
+	for _, method := range []WinMethod{WinHorizontal, WinVertical, WinDiagonal, WinTimeout, WinForfeit} {
+		if count := counts[method]; count > 0 {
+			s.counts[method] = count
+		}
+	}
+}
@@ -6,26 +6,65 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 )
 
 const (
 	listenAddress = ":8080"
 	webFolder     = "./client"
+
+	shutdownTimeout = 10 * time.Second // Time given to connections to close before exiting anyway
 )
 
+// buildHash identifies this server build, set with -ldflags "-X main.buildHash=<hash>"
+var buildHash = "dev"
+
 // main entry point
 func main() {
 	// Create and start server
 	server := NewServer()
 	server.StartPeriodicCleanup()
 
-	// Register the web socket handler
+	// Register the web socket, event stream, stats, leaderboard, admin and monitoring handlers
 	http.HandleFunc("/ws", server.handleWebSocket)
+	http.HandleFunc("/game/{code}/events", server.handleGameEvents)
+	http.HandleFunc("/stats", server.handleStats)
+	http.HandleFunc("/api/leaderboard", server.handleLeaderboard)
+	http.HandleFunc("/admin/announce", server.handleAnnounce)
+	http.HandleFunc("/healthz", server.handleHealth)
+	http.HandleFunc("/metrics", server.handleMetrics)
 	http.Handle("/", http.FileServer(http.Dir(webFolder)))
 
+	httpServer := &http.Server{Addr: listenAddress}
+	server.httpServer = httpServer
+
+	// Stop gracefully on Ctrl-C or when the process manager asks
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+	shutdownDone := make(chan struct{})
+	go func() {
+		defer close(shutdownDone)
+		sig := <-signals
+		log.Printf("Received %v, shutting down", sig)
+
+		ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		if err := server.Shutdown(ctx); err != nil {
+			log.Printf("Shutdown did not complete cleanly: %v", err)
+		}
+	}()
+
 	fmt.Printf("Server starting on %s\n", listenAddress)
-	log.Fatal(http.ListenAndServe(listenAddress, nil))
+	if err := httpServer.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		log.Fatal(err)
+	}
+	<-shutdownDone
 }
@@ -6,6 +6,9 @@
 package main
 
 import (
+	"log"
+	"math"
+	"sort"
 	"time"
 
 	"github.com/marvinEgger/GOnnect4/server/lib"
@@ -13,26 +16,91 @@ import (
 
 const minPlayersForMatch = 2
 
+// queueEntry is a player waiting for a match, with the time they started waiting
+type queueEntry struct {
+	playerID lib.PlayerID
+	queuedAt time.Time
+}
+
+// queueIndex returns the position of a player in the matchmaking queue, -1 if not queued
+func (srv *Server) queueIndex(id lib.PlayerID) int {
+	for i, entry := range srv.matchmakingQueue {
+		if entry.playerID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// removeFromQueue takes a player out of the matchmaking queue, reporting whether they were in it
+func (srv *Server) removeFromQueue(id lib.PlayerID) bool {
+	i := srv.queueIndex(id)
+	if i < 0 {
+		return false
+	}
+	srv.matchmakingQueue = append(srv.matchmakingQueue[:i], srv.matchmakingQueue[i+1:]...)
+	return true
+}
+
+// requeue puts a player back in the queue at the place their original wait time gives them
+// Players who waited longer stay ahead, however often the matches around them fail
+func (srv *Server) requeue(entry queueEntry) {
+	i := sort.Search(len(srv.matchmakingQueue), func(i int) bool {
+		return srv.matchmakingQueue[i].queuedAt.After(entry.queuedAt)
+	})
+	srv.matchmakingQueue = append(srv.matchmakingQueue, queueEntry{})
+	copy(srv.matchmakingQueue[i+1:], srv.matchmakingQueue[i:])
+	srv.matchmakingQueue[i] = entry
+}
+
 // handleJoinMatchmaking adds player to matchmaking queue
 func (srv *Server) handleJoinMatchmaking(client *lib.Client) {
 	srv.mu.Lock()
 	defer srv.mu.Unlock()
 
+	if !srv.matchmakingEnabled {
+		srv.sendError(client, lib.ErrMatchmakingDisabled)
+		return
+	}
+
 	player := srv.lobby[client.PlayerID]
 	if player == nil {
 		srv.sendError(client, lib.ErrPlayerNotFound)
 		return
 	}
 
-	// Check if already in queue
-	for _, pid := range srv.matchmakingQueue {
-		if pid == client.PlayerID {
-			return
-		}
+	// A hosted game still waiting for an opponent is abandoned for matchmaking
+	srv.closeWaitingGame(client)
+
+	// Players already in an active game cannot search for another one
+	if srv.activeGameFor(player.ID) != nil {
+		srv.sendError(client, lib.ErrPlayerAlreadyInGame)
+		return
+	}
+
+	// Players who recently left a ranked game must wait
+	if remaining := player.CooldownRemaining(time.Now()); remaining > 0 {
+		player.Send(lib.Message{
+			Type: lib.MsgCooldown,
+			Data: lib.CooldownData{SecondsRemaining: int(math.Ceil(remaining.Seconds()))},
+		})
+		return
 	}
 
+	// Check if already in queue or confirming a match
+	if srv.readyChecks[client.PlayerID] != nil {
+		return
+	}
+	if srv.queueIndex(client.PlayerID) >= 0 {
+		return
+	}
+
+	// Searching for an opponent ends watching another game or a puzzle rush
+	srv.stopSpectating(client)
+	srv.stopPuzzleRun(client.PlayerID)
+
 	// Add to queue
-	srv.matchmakingQueue = append(srv.matchmakingQueue, client.PlayerID)
+	srv.matchmakingQueue = append(srv.matchmakingQueue, queueEntry{playerID: client.PlayerID, queuedAt: time.Now()})
 
 	// Send searching confirmation
 	player.Send(lib.Message{
@@ -54,14 +122,11 @@ func (srv *Server) handleLeaveMatchmaking(client *lib.Client) {
 	srv.mu.Lock()
 	defer srv.mu.Unlock()
 
+	// Leaving during a ready check declines the match
+	srv.declineReadyCheck(client.PlayerID)
+
 	// Remove from queue
-	for i, pid := range srv.matchmakingQueue {
-		if pid == client.PlayerID {
-			// Removes this player from the matchmaking lobby queue by rebuilding the queue slice without them (skip index i)
-			srv.matchmakingQueue = append(srv.matchmakingQueue[:i], srv.matchmakingQueue[i+1:]...)
-			break
-		}
-	}
+	srv.removeFromQueue(client.PlayerID)
 
 	// Broadcast queue update
 	srv.broadcastQueueUpdate()
@@ -74,49 +139,77 @@ func (srv *Server) tryMatchPlayers() {
 	}
 
 	// Take first two players
-	player1ID := srv.matchmakingQueue[0]
-	player2ID := srv.matchmakingQueue[1]
+	entries := [2]queueEntry{srv.matchmakingQueue[0], srv.matchmakingQueue[1]}
 
 	// Remove from queue
 	srv.matchmakingQueue = srv.matchmakingQueue[2:]
 
 	// Get players from lobby when it's their turn to play
-	player1 := srv.lobby[player1ID]
-	player2 := srv.lobby[player2ID]
+	player1 := srv.lobby[entries[0].playerID]
+	player2 := srv.lobby[entries[1].playerID]
 
-	// Verify both players still exist and are connected
-	if player1 == nil || player2 == nil || !player1.IsConnected() || !player2.IsConnected() {
+	// Verify both players still exist, are connected and not already playing elsewhere
+	if !srv.canBeMatched(player1) || !srv.canBeMatched(player2) {
 		// If one is missing, put the other back in queue
-		if player1 != nil && player1.IsConnected() {
-			srv.matchmakingQueue = append([]lib.PlayerID{player1ID}, srv.matchmakingQueue...)
+		if srv.canBeMatched(player1) {
+			srv.requeue(entries[0])
 		}
-		if player2 != nil && player2.IsConnected() {
-			srv.matchmakingQueue = append([]lib.PlayerID{player2ID}, srv.matchmakingQueue...)
+		if srv.canBeMatched(player2) {
+			srv.requeue(entries[1])
 		}
 		srv.broadcastQueueUpdate()
 		return
 	}
 
-	// Create game
-	game := lib.NewGame(initialClockDuration)
+	// Broadcast queue update after matching
+	srv.broadcastQueueUpdate()
+
+	// Both players must confirm before the game starts
+	srv.startReadyCheck(player1, player2, [2]time.Time{entries[0].queuedAt, entries[1].queuedAt})
+}
+
+// canBeMatched checks if a queued player is still available for a new game
+func (srv *Server) canBeMatched(player *lib.Player) bool {
+	return player != nil && player.IsConnected() && srv.activeGameFor(player.ID) == nil
+}
+
+// startMatchedGame creates a ranked game for two players who passed the ready check
+// It reports false without registering anything when the game could not be set up
+func (srv *Server) startMatchedGame(player1, player2 *lib.Player) bool {
+	game := lib.NewGame(initialClockDuration, srv.increment)
 	game.TimerCallback = srv.handleTimeout
-	game.AddPlayer(player1)
-	game.AddPlayer(player2)
+	game.AllowReplay = false
+	game.Ranked = true
+	game.Public = false
+	game.ReconnectGrace = rankedGracePeriod
+	game.SpectatorDelay = srv.spectatorDelay
+	game.DetectRepetition = true // Takebacks can bring a position back
+	game.ManualStart = true
+
+	// Both seats must be taken before the clock runs, never leave a one-player game behind
+	if !game.AddPlayer(player1) || !game.AddPlayer(player2) {
+		log.Printf("Could not seat matched players %q and %q in game %s", player1.ID, player2.ID, game.Code)
+		game.Cleanup()
+		return false
+	}
+	if err := game.Start(); err != nil {
+		log.Printf("Could not start matched game %s: %v", game.Code, err)
+		game.Cleanup()
+		return false
+	}
 	srv.gamesByCode[game.Code] = game
+	srv.metrics.gamesCreated.Add(1)
 
-	// Broadcast queue update after matching
-	srv.broadcastQueueUpdate()
+	// A rush started during the ready check ends with the game
+	srv.stopPuzzleRun(player1.ID)
+	srv.stopPuzzleRun(player2.ID)
 
 	// Notify both players
 	srv.broadcastToGame(game, lib.Message{
 		Type: lib.MsgGameStart,
-		Data: lib.GameStartData{
-			Code:          game.Code,
-			CurrentTurn:   game.CurrentTurn,
-			Players:       srv.getPlayerInfos(game),
-			TimeRemaining: srv.getTimeRemaining(game),
-		},
+		Data: srv.buildGameStart(game),
 	})
+	return true
 }
 
 // broadcastQueueUpdate sends queue size to all connected players
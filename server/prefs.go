@@ -0,0 +1,27 @@
+// Copyright (c) 2025 Haute école d'ingénierie et d'architecture de Fribourg
+// SPDX-License-Identifier: Apache-2.0
+// Author: Marvin Egger marvin.egger@hotmail.ch
+// Created: 16.10.2026
+
+package main
+
+import "github.com/marvinEgger/GOnnect4/server/lib"
+
+// handleSavePrefs stores the display preferences a player opted to sync, they come back on welcome
+func (srv *Server) handleSavePrefs(client *lib.Client, data lib.SavePrefsData) {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+
+	player := srv.lobby[client.PlayerID]
+	if player == nil {
+		srv.sendError(client, lib.ErrPlayerNotFound)
+		return
+	}
+
+	if err := data.Prefs.Validate(); err != nil {
+		srv.sendError(client, err)
+		return
+	}
+
+	player.SetPrefs(data.Prefs)
+}
@@ -0,0 +1,50 @@
+// Copyright (c) 2025 Haute école d'ingénierie et d'architecture de Fribourg
+// SPDX-License-Identifier: Apache-2.0
+// Author: Marvin Egger marvin.egger@hotmail.ch
+// Created: 16.10.2026
+
+package main
+
+import (
+	"testing"
+
+	"github.com/marvinEgger/GOnnect4/server/lib"
+)
+
+// TestHandleSavePrefs_ReturnedOnWelcome tests that saved preferences come back with the next welcome
+func TestHandleSavePrefs_ReturnedOnWelcome(t *testing.T) {
+	srv := NewServer()
+	defer srv.cancelFunc()
+
+	alice := loginTestPlayer(srv, "Alice")
+	srv.handleSavePrefs(alice, lib.SavePrefsData{Prefs: lib.Prefs{"discSkin": "emoji"}})
+
+	srv.handleLeaveLobby(alice)
+
+	for _, msg := range drainMessages(alice) {
+		if msg.Type != lib.MsgWelcome {
+			continue
+		}
+		if prefs := msg.Data.(lib.WelcomeData).Prefs; prefs["discSkin"] != "emoji" {
+			t.Errorf("Expected saved preferences on welcome, got %v", prefs)
+		}
+		return
+	}
+	t.Error("Expected a welcome message")
+}
+
+// TestHandleSavePrefs_RejectsUnknownKeys tests that only whitelisted preferences are stored
+func TestHandleSavePrefs_RejectsUnknownKeys(t *testing.T) {
+	srv := NewServer()
+	defer srv.cancelFunc()
+
+	alice := loginTestPlayer(srv, "Alice")
+	srv.handleSavePrefs(alice, lib.SavePrefsData{Prefs: lib.Prefs{"resumeToken": "stolen"}})
+
+	if !hasError(drainMessages(alice), lib.ErrInvalidPrefs) {
+		t.Error("Unknown preference keys should be rejected")
+	}
+	if srv.lobby[alice.PlayerID].GetPrefs() != nil {
+		t.Error("Rejected preferences should not be stored")
+	}
+}
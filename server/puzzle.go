@@ -0,0 +1,185 @@
+// Copyright (c) 2025 Haute école d'ingénierie et d'architecture de Fribourg
+// SPDX-License-Identifier: Apache-2.0
+// Author: Marvin Egger marvin.egger@hotmail.ch
+// Created: 16.10.2026
+
+package main
+
+import (
+	mathrand "math/rand/v2"
+	"time"
+
+	"github.com/marvinEgger/GOnnect4/server/lib"
+)
+
+// puzzleRun is a puzzle rush in progress, it ends on the first wrong answer or timeout
+type puzzleRun struct {
+	puzzle    lib.Puzzle
+	number    int
+	score     int
+	startedAt time.Time // When the current puzzle was sent
+	timer     *time.Timer
+
+	// Positions are generated without the server lock, from a source owned by the run
+	rng        *mathrand.Rand
+	generating bool // The next puzzle is being searched, answers wait for it
+}
+
+// handlePuzzleStart starts a new puzzle rush, replacing any run in progress
+func (srv *Server) handlePuzzleStart(client *lib.Client) {
+	if run := srv.startPuzzleRun(client); run != nil {
+		srv.advancePuzzleRun(client.PlayerID, run)
+	}
+}
+
+// startPuzzleRun registers a new run waiting for its first puzzle, nil if the player cannot start one
+func (srv *Server) startPuzzleRun(client *lib.Client) *puzzleRun {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+
+	player := srv.lobby[client.PlayerID]
+	if player == nil {
+		srv.sendError(client, lib.ErrPlayerNotFound)
+		return nil
+	}
+
+	if srv.activeGameFor(player.ID) != nil {
+		srv.sendError(client, lib.ErrPlayerAlreadyInGame)
+		return nil
+	}
+
+	srv.stopSpectating(client)
+	srv.stopPuzzleRun(player.ID)
+	run := &puzzleRun{
+		rng:        mathrand.New(mathrand.NewPCG(srv.puzzleRand.Uint64(), srv.puzzleRand.Uint64())),
+		generating: true,
+	}
+	srv.puzzleRuns[player.ID] = run
+	return run
+}
+
+// handlePuzzleAnswer judges the column played on the current puzzle
+func (srv *Server) handlePuzzleAnswer(client *lib.Client, data lib.PuzzleAnswerData) {
+	if run := srv.judgePuzzleAnswer(client, data); run != nil {
+		srv.advancePuzzleRun(client.PlayerID, run)
+	}
+}
+
+// judgePuzzleAnswer scores or ends the run, it returns the run when it goes on to a next puzzle
+func (srv *Server) judgePuzzleAnswer(client *lib.Client, data lib.PuzzleAnswerData) *puzzleRun {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+
+	player := srv.lobby[client.PlayerID]
+	if player == nil {
+		srv.sendError(client, lib.ErrPlayerNotFound)
+		return nil
+	}
+
+	run := srv.puzzleRuns[player.ID]
+	if run == nil || run.generating {
+		srv.sendError(client, lib.ErrNoPuzzle)
+		return nil
+	}
+	run.timer.Stop()
+
+	if !run.puzzle.IsSolution(data.Column) {
+		srv.endPuzzleRun(player, run, lib.PuzzleResultData{Column: data.Column})
+		return nil
+	}
+
+	points := lib.PuzzlePoints(time.Since(run.startedAt), lib.PuzzleTimeLimit)
+	run.score += points
+	player.Send(lib.Message{
+		Type: lib.MsgPuzzleResult,
+		Data: lib.PuzzleResultData{
+			Correct:   true,
+			Column:    data.Column,
+			Solutions: run.puzzle.Solutions,
+			Points:    points,
+			Score:     run.score,
+			HighScore: max(run.score, player.GetPuzzleHighScore()),
+		},
+	})
+	run.generating = true
+	return run
+}
+
+// advancePuzzleRun searches the next position of a run before taking the server lock, then sends it
+// The position is dropped if the run was stopped or replaced during the search
+func (srv *Server) advancePuzzleRun(playerID lib.PlayerID, run *puzzleRun) {
+	puzzle := lib.GeneratePuzzle(run.rng, lib.MaxPuzzleDepth)
+
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+
+	player := srv.lobby[playerID]
+	if player == nil || srv.puzzleRuns[playerID] != run {
+		return
+	}
+	srv.sendNextPuzzle(player, run, puzzle)
+}
+
+// handlePuzzleTimeout ends a run whose current puzzle was not answered in time
+func (srv *Server) handlePuzzleTimeout(playerID lib.PlayerID, run *puzzleRun, number int) {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+
+	// The run may have moved on or been replaced while the timer fired
+	if srv.puzzleRuns[playerID] != run || run.number != number {
+		return
+	}
+
+	if player := srv.lobby[playerID]; player != nil {
+		srv.endPuzzleRun(player, run, lib.PuzzleResultData{TimedOut: true, Column: -1})
+	}
+}
+
+// sendNextPuzzle sends the next position of a run and starts its clock
+func (srv *Server) sendNextPuzzle(player *lib.Player, run *puzzleRun, puzzle lib.Puzzle) {
+	run.puzzle = puzzle
+	run.generating = false
+	run.number++
+	run.startedAt = time.Now()
+
+	playerID, number := player.ID, run.number
+	run.timer = time.AfterFunc(lib.PuzzleTimeLimit, func() {
+		srv.handlePuzzleTimeout(playerID, run, number)
+	})
+
+	player.Send(lib.Message{
+		Type: lib.MsgPuzzleNext,
+		Data: lib.PuzzleData{
+			Number:    run.number,
+			Board:     run.puzzle.Board,
+			PlayerIdx: int(run.puzzle.ToMove) - int(lib.CellPlayer0),
+			Depth:     run.puzzle.Depth,
+			TimeLimit: lib.PuzzleTimeLimit.Milliseconds(),
+			Score:     run.score,
+			HighScore: max(run.score, player.GetPuzzleHighScore()),
+		},
+	})
+}
+
+// endPuzzleRun reports the final result of a run and records the high score
+func (srv *Server) endPuzzleRun(player *lib.Player, run *puzzleRun, result lib.PuzzleResultData) {
+	delete(srv.puzzleRuns, player.ID)
+
+	result.Solutions = run.puzzle.Solutions
+	result.Score = run.score
+	result.NewHighScore = player.RecordPuzzleScore(run.score)
+	result.HighScore = player.GetPuzzleHighScore()
+	result.Over = true
+	player.Send(lib.Message{Type: lib.MsgPuzzleResult, Data: result})
+}
+
+// stopPuzzleRun drops the run of a player without a result, when they leave or start over
+func (srv *Server) stopPuzzleRun(playerID lib.PlayerID) {
+	if run := srv.puzzleRuns[playerID]; run != nil {
+		// A run still searching its first puzzle has no clock yet
+		if run.timer != nil {
+			run.timer.Stop()
+		}
+		delete(srv.puzzleRuns, playerID)
+	}
+}
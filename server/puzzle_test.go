@@ -0,0 +1,120 @@
+// Copyright (c) 2025 Haute école d'ingénierie et d'architecture de Fribourg
+// SPDX-License-Identifier: Apache-2.0
+// Author: Marvin Egger marvin.egger@hotmail.ch
+// Created: 16.10.2026
+
+package main
+
+import (
+	"testing"
+
+	"github.com/marvinEgger/GOnnect4/server/lib"
+)
+
+// puzzleResult returns the last puzzle result sent to a client
+func puzzleResult(msgs []lib.Message) (lib.PuzzleResultData, bool) {
+	var result lib.PuzzleResultData
+	found := false
+	for _, msg := range msgs {
+		if data, ok := msg.Data.(lib.PuzzleResultData); ok && msg.Type == lib.MsgPuzzleResult {
+			result, found = data, true
+		}
+	}
+	return result, found
+}
+
+// TestPuzzleRush_CorrectThenWrong tests scoring, advancing and ending a run on a wrong answer
+func TestPuzzleRush_CorrectThenWrong(t *testing.T) {
+	srv := NewServer()
+	defer srv.cancelFunc()
+
+	alice := loginTestPlayer(srv, "Alice")
+	srv.handlePuzzleStart(alice)
+	if !hasMessage(drainMessages(alice), lib.MsgPuzzleNext) {
+		t.Fatal("Starting a rush should send the first puzzle")
+	}
+
+	run := srv.puzzleRuns[alice.PlayerID]
+	srv.handlePuzzleAnswer(alice, lib.PuzzleAnswerData{Column: run.puzzle.Solutions[0]})
+
+	msgs := drainMessages(alice)
+	result, ok := puzzleResult(msgs)
+	if !ok || !result.Correct || result.Over || result.Score <= 0 {
+		t.Fatalf("Expected a scored correct answer, got %+v", result)
+	}
+	if !hasMessage(msgs, lib.MsgPuzzleNext) || run.number != 2 {
+		t.Fatal("A correct answer should advance to the next puzzle")
+	}
+
+	wrong := -1
+	for col := 0; col < lib.Cols; col++ {
+		if !run.puzzle.IsSolution(col) {
+			wrong = col
+			break
+		}
+	}
+	srv.handlePuzzleAnswer(alice, lib.PuzzleAnswerData{Column: wrong})
+
+	result, _ = puzzleResult(drainMessages(alice))
+	if result.Correct || !result.Over || !result.NewHighScore {
+		t.Errorf("Expected the run to end with a new high score, got %+v", result)
+	}
+	if _, running := srv.puzzleRuns[alice.PlayerID]; running {
+		t.Error("The run should be over")
+	}
+	if srv.lobby[alice.PlayerID].GetPuzzleHighScore() != result.Score {
+		t.Error("The high score should be kept for the session")
+	}
+}
+
+// TestPuzzleRush_Timeout tests that an unanswered puzzle ends the run, stale timers are ignored
+func TestPuzzleRush_Timeout(t *testing.T) {
+	srv := NewServer()
+	defer srv.cancelFunc()
+
+	alice := loginTestPlayer(srv, "Alice")
+	srv.handlePuzzleStart(alice)
+	drainMessages(alice)
+
+	run := srv.puzzleRuns[alice.PlayerID]
+	srv.handlePuzzleTimeout(alice.PlayerID, run, run.number-1)
+	if srv.puzzleRuns[alice.PlayerID] == nil {
+		t.Fatal("A timer of an earlier puzzle should be ignored")
+	}
+
+	srv.handlePuzzleTimeout(alice.PlayerID, run, run.number)
+	result, ok := puzzleResult(drainMessages(alice))
+	if !ok || !result.TimedOut || !result.Over {
+		t.Errorf("Expected the run to end on timeout, got %+v", result)
+	}
+}
+
+// TestPuzzleRush_StopsOnCreateGame tests that starting a game drops the run and its clock
+func TestPuzzleRush_StopsOnCreateGame(t *testing.T) {
+	srv := NewServer()
+	defer srv.cancelFunc()
+
+	alice := loginTestPlayer(srv, "Alice")
+	srv.handlePuzzleStart(alice)
+	drainMessages(alice)
+
+	srv.handleCreateGame(alice, lib.CreateGameData{})
+	if _, running := srv.puzzleRuns[alice.PlayerID]; running {
+		t.Error("Creating a game should end the puzzle rush")
+	}
+}
+
+// TestPuzzleRush_StopsOnMatchmaking tests that queueing for a match drops the run
+func TestPuzzleRush_StopsOnMatchmaking(t *testing.T) {
+	srv := NewServer()
+	defer srv.cancelFunc()
+
+	alice := loginTestPlayer(srv, "Alice")
+	srv.handlePuzzleStart(alice)
+	drainMessages(alice)
+
+	srv.handleJoinMatchmaking(alice)
+	if _, running := srv.puzzleRuns[alice.PlayerID]; running {
+		t.Error("Joining matchmaking should end the puzzle rush")
+	}
+}
@@ -0,0 +1,76 @@
+// Copyright (c) 2025 Haute école d'ingénierie et d'architecture de Fribourg
+// SPDX-License-Identifier: Apache-2.0
+// Author: Marvin Egger marvin.egger@hotmail.ch
+// Created: 16.10.2026
+
+package main
+
+import "github.com/marvinEgger/GOnnect4/server/lib"
+
+// applyRatingChange moves the ratings of both players of a finished ranked game, once per round
+// Forfeits and timeouts are decided in favor of the opponent, so they count as a loss
+// It returns the points each side gained, zero when the game is not rated
+func (srv *Server) applyRatingChange(game *lib.Game) [2]int {
+	var gains [2]int
+	if !game.Ranked || game.Rated || game.GetStatus() != lib.StatusFinished {
+		return gains
+	}
+
+	var score float64
+	switch game.Result {
+	case lib.ResultPlayer0Win:
+		score = 1
+	case lib.ResultPlayer1Win:
+		score = 0
+	case lib.ResultDraw:
+		score = 0.5
+	default:
+		return gains
+	}
+
+	players := game.GetPlayers()
+	if players[0] == nil || players[1] == nil {
+		return gains
+	}
+	game.Rated = true
+
+	// The change comes from the ratings before the game, what one side gains the other loses
+	gains[0] = lib.RatingChange(players[0].GetRating(), players[1].GetRating(), score)
+	gains[1] = -gains[0]
+	for idx, player := range players {
+		player.AdjustRating(gains[idx])
+	}
+	return gains
+}
+
+// handleRatingLeaderboard sends the best rated players online, at most maxLeaderboardSize
+func (srv *Server) handleRatingLeaderboard(client *lib.Client, data lib.LeaderboardRequestData) {
+	srv.mu.RLock()
+	player := srv.lobby[client.PlayerID]
+	srv.mu.RUnlock()
+
+	if player == nil {
+		srv.sendError(client, lib.ErrPlayerNotFound)
+		return
+	}
+
+	limit := data.Limit
+	if limit <= 0 {
+		limit = defaultLeaderboardSize
+	}
+	ranking := srv.rankByRating()
+	player.Send(lib.Message{
+		Type: lib.MsgLeaderboard,
+		Data: lib.LeaderboardData{Players: ranking[:min(limit, maxLeaderboardSize, len(ranking))]},
+	})
+}
+
+// rankByRating sorts the connected players by rating, ties by username
+func (srv *Server) rankByRating() []lib.RatingEntry {
+	ranked := srv.rankConnected((*lib.Player).GetRating)
+	ranking := make([]lib.RatingEntry, len(ranked))
+	for i, entry := range ranked {
+		ranking[i] = lib.RatingEntry{Rank: entry.rank, Username: entry.username, Tag: entry.tag, Rating: entry.score}
+	}
+	return ranking
+}
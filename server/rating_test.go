@@ -0,0 +1,121 @@
+// Copyright (c) 2025 Haute école d'ingénierie et d'architecture de Fribourg
+// SPDX-License-Identifier: Apache-2.0
+// Author: Marvin Egger marvin.egger@hotmail.ch
+// Created: 16.10.2026
+
+package main
+
+import (
+	"testing"
+
+	"github.com/marvinEgger/GOnnect4/server/lib"
+)
+
+// TestApplyRatingChange_ForfeitIsALoss tests that a ranked forfeit moves both ratings once and tells the players
+func TestApplyRatingChange_ForfeitIsALoss(t *testing.T) {
+	srv := NewServer()
+	defer srv.cancelFunc()
+
+	alice := loginTestPlayer(srv, "Alice")
+	bob := loginTestPlayer(srv, "Bob")
+	matchTestPlayers(srv, alice, bob)
+	game := srv.findGameForClient(alice)
+	if game == nil {
+		t.Fatal("Players should have been matched")
+	}
+	drainMessages(alice)
+	drainMessages(bob)
+
+	srv.handleForfeit(alice)
+	srv.handleForfeit(alice) // Announcing the same game again must not rate it twice
+
+	if rating := srv.lobby[alice.PlayerID].GetRating(); rating != lib.DefaultRating-16 {
+		t.Errorf("Expected the forfeiting player at %d, got %d", lib.DefaultRating-16, rating)
+	}
+	if rating := srv.lobby[bob.PlayerID].GetRating(); rating != lib.DefaultRating+16 {
+		t.Errorf("Expected the winner at %d, got %d", lib.DefaultRating+16, rating)
+	}
+
+	gained := false
+	for _, msg := range drainMessages(alice) {
+		if stats, ok := msg.Data.(lib.StatsData); ok && stats.RatingGain == -16 {
+			gained = true
+		}
+	}
+	if !gained {
+		t.Error("The loser should be told about the rating change")
+	}
+}
+
+// TestApplyRatingChange_DrawIsZeroSum tests that a ranked draw between unequal ratings moves no points in or out
+func TestApplyRatingChange_DrawIsZeroSum(t *testing.T) {
+	srv := NewServer()
+	defer srv.cancelFunc()
+
+	alice := loginTestPlayer(srv, "Alice")
+	bob := loginTestPlayer(srv, "Bob")
+	srv.lobby[alice.PlayerID].AdjustRating(137)
+	matchTestPlayers(srv, alice, bob)
+	game := srv.findGameForClient(alice)
+	if game == nil {
+		t.Fatal("Players should have been matched")
+	}
+	before := srv.lobby[alice.PlayerID].GetRating() + srv.lobby[bob.PlayerID].GetRating()
+
+	srv.handleDrawOffer(alice)
+	srv.handleDrawResponse(bob, lib.DrawResponseData{Accept: true})
+	if game.Result != lib.ResultDraw {
+		t.Fatalf("Expected a draw, got result %v", game.Result)
+	}
+
+	after := srv.lobby[alice.PlayerID].GetRating() + srv.lobby[bob.PlayerID].GetRating()
+	if after != before {
+		t.Errorf("Expected the rating total to stay %d, got %d", before, after)
+	}
+	if srv.lobby[alice.PlayerID].GetRating() >= lib.DefaultRating+137 {
+		t.Error("The higher rated player should lose points on a draw")
+	}
+}
+
+// TestApplyRatingChange_FriendGameUnrated tests that games outside matchmaking leave ratings alone
+func TestApplyRatingChange_FriendGameUnrated(t *testing.T) {
+	srv := NewServer()
+	defer srv.cancelFunc()
+
+	mover, game := startTestGame(srv)
+	srv.handleForfeit(mover)
+
+	for _, player := range game.GetPlayers() {
+		if rating := player.GetRating(); rating != lib.DefaultRating {
+			t.Errorf("Expected %d after a friend game, got %d", lib.DefaultRating, rating)
+		}
+	}
+}
+
+// TestHandleRatingLeaderboard_Ranks tests the order by rating and the limit
+func TestHandleRatingLeaderboard_Ranks(t *testing.T) {
+	srv := NewServer()
+	defer srv.cancelFunc()
+
+	ratings := map[string]int{"Alice": 1250, "Bob": 1300, "Carol": 1250}
+	var client *lib.Client
+	for name, rating := range ratings {
+		client = loginTestPlayer(srv, name)
+		srv.lobby[client.PlayerID].AdjustRating(rating - lib.DefaultRating)
+	}
+
+	srv.handleRatingLeaderboard(client, lib.LeaderboardRequestData{Limit: 2})
+	msgs := drainMessages(client)
+	if len(msgs) != 1 || msgs[0].Type != lib.MsgLeaderboard {
+		t.Fatalf("Expected a leaderboard, got %v", msgs)
+	}
+
+	board := msgs[0].Data.(lib.LeaderboardData)
+	if len(board.Players) != 2 {
+		t.Fatalf("Expected 2 players, got %d", len(board.Players))
+	}
+	if first, second := board.Players[0], board.Players[1]; first.Username != "Bob" || first.Rank != 1 ||
+		second.Username != "Alice" || second.Rank != 2 {
+		t.Errorf("Unexpected ranking: %+v", board.Players)
+	}
+}
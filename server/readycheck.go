@@ -0,0 +1,145 @@
+// Copyright (c) 2025 Haute école d'ingénierie et d'architecture de Fribourg
+// SPDX-License-Identifier: Apache-2.0
+// Author: Marvin Egger marvin.egger@hotmail.ch
+// Created: 16.10.2026
+
+package main
+
+import (
+	"time"
+
+	"github.com/marvinEgger/GOnnect4/server/lib"
+)
+
+const readyCheckTimeout = 10 * time.Second
+
+// readyCheck holds two matched players until both confirm they are ready
+type readyCheck struct {
+	players  [2]lib.PlayerID
+	accepted [2]bool
+	queuedAt [2]time.Time // When each player joined the queue, kept if they are requeued
+	timer    *time.Timer
+}
+
+// index returns the position of a player in the ready check
+func (check *readyCheck) index(id lib.PlayerID) int {
+	for i, pid := range check.players {
+		if pid == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// startReadyCheck asks two matched players to confirm before the game is created
+func (srv *Server) startReadyCheck(player1, player2 *lib.Player, queuedAt [2]time.Time) {
+	check := &readyCheck{players: [2]lib.PlayerID{player1.ID, player2.ID}, queuedAt: queuedAt}
+	srv.readyChecks[player1.ID] = check
+	srv.readyChecks[player2.ID] = check
+
+	// Lock needed because timer callback runs in separate goroutine
+	check.timer = time.AfterFunc(readyCheckTimeout, func() {
+		srv.mu.Lock()
+		defer srv.mu.Unlock()
+		srv.expireReadyCheck(check)
+	})
+
+	msg := lib.Message{
+		Type: lib.MsgReadyCheck,
+		Data: lib.ReadyCheckData{TimeoutSeconds: int(readyCheckTimeout.Seconds())},
+	}
+	player1.Send(msg)
+	player2.Send(msg)
+}
+
+// handleReadyResponse processes a player's answer to a ready check
+func (srv *Server) handleReadyResponse(client *lib.Client, data lib.ReadyResponseData) {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+
+	check := srv.readyChecks[client.PlayerID]
+	if check == nil {
+		return
+	}
+
+	if !data.Accept {
+		srv.failReadyCheck(check)
+		return
+	}
+
+	check.accepted[check.index(client.PlayerID)] = true
+	if !check.accepted[0] || !check.accepted[1] {
+		return
+	}
+
+	srv.closeReadyCheck(check)
+
+	player1 := srv.lobby[check.players[0]]
+	player2 := srv.lobby[check.players[1]]
+	if !srv.canBeMatched(player1) || !srv.canBeMatched(player2) {
+		srv.requeueReadyPlayers(check)
+		return
+	}
+
+	if !srv.startMatchedGame(player1, player2) {
+		srv.requeueReadyPlayers(check)
+	}
+}
+
+// declineReadyCheck cancels the ready check of a player who left or disconnected
+func (srv *Server) declineReadyCheck(id lib.PlayerID) {
+	if check := srv.readyChecks[id]; check != nil {
+		check.accepted[check.index(id)] = false
+		srv.failReadyCheck(check)
+	}
+}
+
+// expireReadyCheck cancels a ready check nobody completed in time
+func (srv *Server) expireReadyCheck(check *readyCheck) {
+	// The check may already have been resolved while waiting for the lock
+	if srv.readyChecks[check.players[0]] != check {
+		return
+	}
+	srv.failReadyCheck(check)
+}
+
+// failReadyCheck puts players who accepted back in the queue and drops the others
+func (srv *Server) failReadyCheck(check *readyCheck) {
+	srv.closeReadyCheck(check)
+	srv.requeueReadyPlayers(check)
+}
+
+// requeueReadyPlayers requeues accepting players at the place their wait time gives them
+// A player who started another game during the check is left out, like in tryMatchPlayers
+func (srv *Server) requeueReadyPlayers(check *readyCheck) {
+	for i, pid := range check.players {
+		player := srv.lobby[pid]
+		if player == nil {
+			continue
+		}
+
+		ready := check.accepted[i] && srv.canBeMatched(player)
+		if ready {
+			srv.requeue(queueEntry{playerID: pid, queuedAt: check.queuedAt[i]})
+		}
+
+		player.Send(lib.Message{
+			Type: lib.MsgReadyCheckFailed,
+			Data: lib.ReadyCheckFailedData{Requeued: ready},
+		})
+	}
+
+	srv.broadcastQueueUpdate()
+
+	if len(srv.matchmakingQueue) >= minPlayersForMatch {
+		srv.tryMatchPlayers()
+	}
+}
+
+// closeReadyCheck stops the timeout and forgets the ready check
+func (srv *Server) closeReadyCheck(check *readyCheck) {
+	check.timer.Stop()
+	for _, pid := range check.players {
+		delete(srv.readyChecks, pid)
+	}
+}
@@ -0,0 +1,165 @@
+// Copyright (c) 2025 Haute école d'ingénierie et d'architecture de Fribourg
+// SPDX-License-Identifier: Apache-2.0
+// Author: Marvin Egger marvin.egger@hotmail.ch
+// Created: 16.10.2026
+
+package main
+
+import (
+	"testing"
+
+	"github.com/marvinEgger/GOnnect4/server/lib"
+)
+
+// queueTestPlayers logs two players in and matches them without answering the ready check
+func queueTestPlayers(srv *Server) (*lib.Client, *lib.Client) {
+	alice := loginTestPlayer(srv, "Alice")
+	bob := loginTestPlayer(srv, "Bob")
+	srv.handleJoinMatchmaking(alice)
+	srv.handleJoinMatchmaking(bob)
+	return alice, bob
+}
+
+// TestReadyCheck_SentOnMatch tests that matched players must confirm before the game exists
+func TestReadyCheck_SentOnMatch(t *testing.T) {
+	srv := NewServer()
+	defer srv.cancelFunc()
+
+	alice, bob := queueTestPlayers(srv)
+
+	if !hasMessage(drainMessages(alice), lib.MsgReadyCheck) || !hasMessage(drainMessages(bob), lib.MsgReadyCheck) {
+		t.Error("Both players should receive a ready check")
+	}
+	if srv.findGameForClient(alice) != nil {
+		t.Error("Game should not start before the ready check")
+	}
+}
+
+// TestReadyCheck_BothAccept tests that the game starts once both players accept
+func TestReadyCheck_BothAccept(t *testing.T) {
+	srv := NewServer()
+	defer srv.cancelFunc()
+
+	alice, bob := queueTestPlayers(srv)
+	srv.handleReadyResponse(alice, lib.ReadyResponseData{Accept: true})
+	srv.handleReadyResponse(bob, lib.ReadyResponseData{Accept: true})
+
+	if !hasMessage(drainMessages(alice), lib.MsgGameStart) {
+		t.Error("Game should start when both players accept")
+	}
+	if len(srv.readyChecks) != 0 {
+		t.Error("Ready check should be closed once the game starts")
+	}
+}
+
+// TestReadyCheck_OneDeclines tests that the accepting player is requeued and the other dropped
+func TestReadyCheck_OneDeclines(t *testing.T) {
+	srv := NewServer()
+	defer srv.cancelFunc()
+
+	alice, bob := queueTestPlayers(srv)
+	srv.handleReadyResponse(alice, lib.ReadyResponseData{Accept: true})
+	srv.handleReadyResponse(bob, lib.ReadyResponseData{Accept: false})
+
+	if srv.findGameForClient(alice) != nil {
+		t.Error("Game should not start when a player declines")
+	}
+	if len(srv.matchmakingQueue) != 1 || srv.matchmakingQueue[0].playerID != alice.PlayerID {
+		t.Errorf("Only the accepting player should be requeued, got %v", srv.matchmakingQueue)
+	}
+}
+
+// TestReadyCheck_Timeout tests that nobody accepting in time drops both players
+func TestReadyCheck_Timeout(t *testing.T) {
+	srv := NewServer()
+	defer srv.cancelFunc()
+
+	alice, bob := queueTestPlayers(srv)
+	srv.handleReadyResponse(alice, lib.ReadyResponseData{Accept: true})
+	drainMessages(bob)
+
+	srv.mu.Lock()
+	srv.expireReadyCheck(srv.readyChecks[bob.PlayerID])
+	srv.mu.Unlock()
+
+	if len(srv.matchmakingQueue) != 1 || srv.matchmakingQueue[0].playerID != alice.PlayerID {
+		t.Errorf("Accepting player should be back in front of the queue, got %v", srv.matchmakingQueue)
+	}
+	if !hasMessage(drainMessages(bob), lib.MsgReadyCheckFailed) {
+		t.Error("Player who did not answer should be told the match was cancelled")
+	}
+	if len(srv.readyChecks) != 0 {
+		t.Error("Expired ready check should be removed")
+	}
+}
+
+// TestReadyCheck_NotRequeuedWhilePlaying tests that an accepting player who started another game stays out of the queue
+func TestReadyCheck_NotRequeuedWhilePlaying(t *testing.T) {
+	srv := NewServer()
+	defer srv.cancelFunc()
+
+	alice, bob := queueTestPlayers(srv)
+	srv.handleReadyResponse(alice, lib.ReadyResponseData{Accept: true})
+	srv.handleCreateGame(alice, lib.CreateGameData{})
+	if srv.activeGameFor(alice.PlayerID) == nil {
+		t.Fatal("The player should have created a game during the check")
+	}
+	drainMessages(alice)
+
+	srv.handleReadyResponse(bob, lib.ReadyResponseData{Accept: false})
+
+	if len(srv.matchmakingQueue) != 0 {
+		t.Errorf("A player already in a game should not be requeued, got %v", srv.matchmakingQueue)
+	}
+	for _, msg := range drainMessages(alice) {
+		if data, ok := msg.Data.(lib.ReadyCheckFailedData); ok && data.Requeued {
+			t.Error("The player should not be told they were requeued")
+		}
+	}
+}
+
+// TestReadyCheck_RequeueKeepsWaitOrder tests that players requeued after failed matches keep their wait order
+func TestReadyCheck_RequeueKeepsWaitOrder(t *testing.T) {
+	srv := NewServer()
+	defer srv.cancelFunc()
+
+	alice, bob := queueTestPlayers(srv)
+	carol := loginTestPlayer(srv, "Carol")
+	dave := loginTestPlayer(srv, "Dave")
+	srv.handleJoinMatchmaking(carol)
+	srv.handleJoinMatchmaking(dave)
+
+	// Alice waited longest, so she goes back first even though Carol's match fails last
+	srv.handleReadyResponse(alice, lib.ReadyResponseData{Accept: true})
+	srv.handleReadyResponse(bob, lib.ReadyResponseData{Accept: false})
+	srv.handleReadyResponse(carol, lib.ReadyResponseData{Accept: true})
+	srv.handleReadyResponse(dave, lib.ReadyResponseData{Accept: false})
+
+	check := srv.readyChecks[alice.PlayerID]
+	if check == nil || check != srv.readyChecks[carol.PlayerID] {
+		t.Fatal("The requeued players should be matched together")
+	}
+	if check.players[0] != alice.PlayerID {
+		t.Error("Alice waited longer and should be ahead of Carol")
+	}
+}
+
+// TestStartMatchedGame_SeatingFails tests that a matched game that cannot seat both players is never registered
+func TestStartMatchedGame_SeatingFails(t *testing.T) {
+	srv := NewServer()
+	defer srv.cancelFunc()
+
+	alice := loginTestPlayer(srv, "Alice")
+	player := srv.lobby[alice.PlayerID]
+
+	// The same player cannot take both sides of a game
+	if srv.startMatchedGame(player, player) {
+		t.Fatal("A game with the same player on both sides should not start")
+	}
+	if len(srv.gamesByCode) != 0 {
+		t.Error("A game that failed to start should not be registered")
+	}
+	if srv.findGameForClient(alice) != nil || hasMessage(drainMessages(alice), lib.MsgGameStart) {
+		t.Error("The player should not be sent into a game that failed to start")
+	}
+}
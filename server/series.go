@@ -0,0 +1,40 @@
+// Copyright (c) 2025 Haute école d'ingénierie et d'architecture de Fribourg
+// SPDX-License-Identifier: Apache-2.0
+// Author: Marvin Egger marvin.egger@hotmail.ch
+// Created: 16.10.2026
+
+package main
+
+import "github.com/marvinEgger/GOnnect4/server/lib"
+
+// handleResignRound concedes the current round of a series and starts the next one
+// Resigning the whole match still goes through handleForfeit
+func (srv *Server) handleResignRound(client *lib.Client) {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+
+	game := srv.findGameForClient(client)
+	if game == nil {
+		srv.sendError(client, lib.ErrGameNotFound)
+		return
+	}
+
+	playerIdx := game.GetPlayerIndex(client.PlayerID)
+	if playerIdx < 0 {
+		srv.sendError(client, lib.ErrPlayerNotInGame)
+		return
+	}
+
+	if err := game.ResignRound(playerIdx); err != nil {
+		srv.sendError(client, err)
+		return
+	}
+	srv.announceGameOver(game)
+
+	if game.NextRound() {
+		srv.broadcastToGame(game, lib.Message{
+			Type: lib.MsgGameStart,
+			Data: srv.buildGameStart(game),
+		})
+	}
+}
@@ -0,0 +1,135 @@
+// Copyright (c) 2025 Haute école d'ingénierie et d'architecture de Fribourg
+// SPDX-License-Identifier: Apache-2.0
+// Author: Marvin Egger marvin.egger@hotmail.ch
+// Created: 16.10.2026
+
+package main
+
+import (
+	"testing"
+
+	"github.com/marvinEgger/GOnnect4/server/lib"
+)
+
+// startTestSeries starts a best-of series between Alice and Bob
+func startTestSeries(srv *Server, bestOf int) (alice, bob *lib.Client, game *lib.Game) {
+	alice = loginTestPlayer(srv, "Alice")
+	bob = loginTestPlayer(srv, "Bob")
+	srv.handleCreateGame(alice, lib.CreateGameData{BestOf: bestOf})
+	srv.handleJoinGame(bob, lib.JoinGameData{Code: alice.GameCode})
+	game = srv.findGameForClient(alice)
+	drainMessages(alice)
+	drainMessages(bob)
+	return alice, bob, game
+}
+
+// TestHandleResignRound_NextRound tests that resigning a round scores it and starts the next one
+func TestHandleResignRound_NextRound(t *testing.T) {
+	srv := NewServer()
+	defer srv.cancelFunc()
+
+	alice, bob, game := startTestSeries(srv, 3)
+	srv.handleResignRound(alice)
+
+	bobIdx := game.GetPlayerIndex(bob.PlayerID)
+	if score := game.GetScore(); score[bobIdx] != 1 || score[1-bobIdx] != 0 {
+		t.Errorf("Expected the round to be credited to Bob, got score %v with Bob on side %d", score, bobIdx)
+	}
+	if game.GetStatus() != lib.StatusPlaying {
+		t.Error("Next round should start right away")
+	}
+
+	msgs := drainMessages(bob)
+	if !hasMessage(msgs, lib.MsgGameOver) || !hasMessage(msgs, lib.MsgGameStart) {
+		t.Errorf("Expected the round to end and the next one to start, got %v", msgs)
+	}
+}
+
+// TestHandleTimeout_MidSeries tests that running out of time loses the round, not the match
+func TestHandleTimeout_MidSeries(t *testing.T) {
+	srv := NewServer()
+	defer srv.cancelFunc()
+
+	alice, bob, game := startTestSeries(srv, 3)
+	loser := game.GetPlayers()[game.CurrentTurn].ID
+	game.TimeRemaining[game.CurrentTurn] = 0
+	srv.handleTimeout(game.Code, game.CurrentTurn)
+
+	if game.SeriesOver {
+		t.Fatal("A timeout in the first round should not end a best of 3")
+	}
+	loserIdx := game.GetPlayerIndex(loser)
+	if score := game.GetScore(); score[1-loserIdx] != 1 || score[loserIdx] != 0 {
+		t.Errorf("Expected the round to be credited to the opponent, got score %v", score)
+	}
+	if game.GetStatus() != lib.StatusPlaying {
+		t.Error("Next round should start right away")
+	}
+	for _, client := range []*lib.Client{alice, bob} {
+		if msgs := drainMessages(client); !hasMessage(msgs, lib.MsgGameOver) || !hasMessage(msgs, lib.MsgGameStart) {
+			t.Errorf("Expected the round to end and the next one to start, got %v", msgs)
+		}
+	}
+
+	// The same player running out again decides the series
+	if game.CurrentTurn != loserIdx {
+		game.Play(game.CurrentTurn, 0)
+	}
+	game.TimeRemaining[loserIdx] = 0
+	srv.handleTimeout(game.Code, loserIdx)
+	if !game.SeriesOver || game.GetStatus() != lib.StatusFinished {
+		t.Errorf("Expected the series to be over, got score %v", game.GetScore())
+	}
+}
+
+// TestHandleResignRound_DecidesSeries tests that a deciding concession ends the match
+func TestHandleResignRound_DecidesSeries(t *testing.T) {
+	srv := NewServer()
+	defer srv.cancelFunc()
+
+	alice, bob, game := startTestSeries(srv, 3)
+	srv.handleResignRound(alice)
+	srv.handleResignRound(alice)
+
+	if game.GetStatus() != lib.StatusFinished || !game.SeriesOver {
+		t.Fatal("Second conceded round should decide a best of 3")
+	}
+	if score := game.GetScore(); score[game.GetPlayerIndex(bob.PlayerID)] != 2 {
+		t.Errorf("Expected Bob to win the series 2-0, got %v", score)
+	}
+	for _, msg := range drainMessages(bob) {
+		if data, ok := msg.Data.(lib.GameOverData); ok && data.SeriesOver {
+			return
+		}
+	}
+	t.Error("Expected a game over ending the series")
+}
+
+// TestHandleResignRound_NotInSeries tests that single games must be forfeited instead
+func TestHandleResignRound_NotInSeries(t *testing.T) {
+	srv := NewServer()
+	defer srv.cancelFunc()
+
+	mover, game := startTestGame(srv)
+	srv.handleResignRound(mover)
+
+	if game.GetStatus() != lib.StatusPlaying {
+		t.Error("Resigning a round should not end a single game")
+	}
+	if !hasError(drainMessages(mover), lib.ErrNotInSeries) {
+		t.Error("Expected an error for a game outside a series")
+	}
+}
+
+// TestHandleForfeit_EndsSeries tests that forfeiting resigns the whole match
+func TestHandleForfeit_EndsSeries(t *testing.T) {
+	srv := NewServer()
+	defer srv.cancelFunc()
+
+	alice, _, game := startTestSeries(srv, 5)
+	srv.handleForfeit(alice)
+
+	if game.GetStatus() != lib.StatusFinished || !game.SeriesOver {
+		t.Error("Forfeit should end the series")
+	}
+}
@@ -7,6 +7,13 @@ package main
 
 import (
 	"context"
+	"crypto/rand"
+	"log"
+	mathrand "math/rand/v2"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -15,22 +22,129 @@ import (
 
 const (
 	initialClockDuration = 150 * time.Second // 2min 30s
-	reconnectGracePeriod = 120 * time.Second
+	reconnectGracePeriod = 120 * time.Second // Games without a mode of their own, and friend games
+	rankedGracePeriod    = 60 * time.Second  // Shorter so abandoned matchmaking games free their players sooner
+	pausedGameMaxAge     = 2 * time.Hour     // Safety net for paused friend games
 	cleanupInterval      = 30 * time.Second
+	timeSyncInterval     = 5 * time.Second // Client clocks drift by less than this between two syncs
 	queueUpdateDelay     = 500 * time.Millisecond
+
+	resumeSecretEnv    = "GONNECT4_RESUME_SECRET"
+	resumeSecretLength = 32
+
+	minMoveTimeEnv = "GONNECT4_MIN_MOVE_TIME"
+
+	incrementEnv = "GONNECT4_INCREMENT"
+	maxIncrement = time.Minute // Longer increments would let a game run on for hours
+
+	// Bounds of the clock a host may pick for a friend game
+	minCustomClock = 10 * time.Second
+	maxCustomClock = 30 * time.Minute
+
+	modesEnv = "GONNECT4_MODES"
+	chatEnv  = "GONNECT4_CHAT"
+
+	adminTokenEnv = "GONNECT4_ADMIN_TOKEN"
+
+	maxConnsPerIPEnv     = "GONNECT4_MAX_CONNS_PER_IP"
+	defaultMaxConnsPerIP = 10 // Generous enough for a household with several tabs open
+	trustProxyEnv        = "GONNECT4_TRUST_PROXY"
+
+	seriesTieBreakEnv = "GONNECT4_SERIES_TIEBREAK"
+
+	statsFileEnv = "GONNECT4_STATS_FILE"
+
+	historySizeEnv     = "GONNECT4_HISTORY_SIZE"
+	defaultHistorySize = 10
+	historyFileEnv     = "GONNECT4_HISTORY_FILE"
+
+	spectatorDelayEnv = "GONNECT4_SPECTATOR_DELAY"
+	maxSpectatorDelay = 10 * time.Minute
 )
 
 // Server manages all games and player connections
+// A player is in at most one active (waiting or playing) game at a time, create, join and
+// matchmaking paths check activeGameFor under mu before adding a player to a game
 type Server struct {
 	mu               sync.RWMutex
 	gamesByCode      map[string]*lib.Game
 	lobby            map[lib.PlayerID]*lib.Player
-	matchmakingQueue []lib.PlayerID
+	matchmakingQueue []queueEntry
+	readyChecks      map[lib.PlayerID]*readyCheck
 
 	// Background cleanup
 	ctx        context.Context
 	cancelFunc context.CancelFunc
 
+	// Open websocket connections, told and closed on shutdown
+	clients map[*lib.Client]struct{}
+
+	// Cleanup loop, websocket handlers and write pumps, Shutdown waits for all of them
+	background sync.WaitGroup
+
+	// HTTP server closed by Shutdown, nil when the handlers are served some other way
+	httpServer *http.Server
+
+	// Secret used to sign resume tokens
+	resumeSecret []byte
+
+	// Moves played sooner after the turn started are dropped, 0 disables the check
+	minMoveTime time.Duration
+
+	// Time added to a player's clock after each move, 0 for none
+	increment time.Duration
+
+	// How far behind the players spectators and event streams see new games, 0 for live
+	spectatorDelay time.Duration
+
+	// Open websocket connections per remote IP, upgrades beyond maxConnsPerIP are refused
+	connsPerIP    map[string]int
+	maxConnsPerIP int  // 0 disables the limit
+	trustProxy    bool // Take the client IP from X-Forwarded-For, only safe behind a proxy setting it
+
+	// Optional features offered by this instance, all enabled by default
+	friendGamesEnabled bool
+	matchmakingEnabled bool
+	chatEnabled        bool
+
+	// Decides best-of series that run out of rounds on an even score
+	seriesTieBreak lib.SeriesTieBreak
+
+	// Bearer token of the admin endpoints, empty disables them
+	adminToken       string
+	lastAnnouncement time.Time
+
+	// Puzzle rushes in progress, each run draws its positions from a source seeded by puzzleRand
+	puzzleRuns map[lib.PlayerID]*puzzleRun
+	puzzleRand *mathrand.Rand
+
+	// Read-only event streams of each game, keyed by game code
+	eventSubs map[string]map[*eventSubscriber]struct{}
+
+	// Game in progress the lobby is pointed to, picked again on every cleanup tick
+	featuredCode      string
+	featuredCriterion featuredCriterion
+
+	// Build announced to clients so stale bundles can be detected
+	buildHash string
+
+	// How finished games were won and ended, served on /stats
+	winStats   *lib.WinStats
+	gameTotals *lib.GameTotals
+
+	// Finished games kept in each player's history
+	historySize int
+
+	// Finished games of every player, saved to a JSON lines file when one is configured
+	history *lib.HistoryStore
+
+	// File the stats survive restarts in, empty keeps them in memory only
+	statsPath   string
+	statsFileMu sync.Mutex
+
+	// Counters served on /metrics
+	metrics serverMetrics
+
 	// Queue update throttling
 	queueUpdatePending bool
 	queueUpdateTimer   *time.Timer
@@ -39,24 +153,217 @@ type Server struct {
 // NewServer creates a new game server
 func NewServer() *Server {
 	ctx, cancel := context.WithCancel(context.Background())
-	return &Server{
-		gamesByCode:      make(map[string]*lib.Game),
-		lobby:            make(map[lib.PlayerID]*lib.Player),
-		matchmakingQueue: make([]lib.PlayerID, 0),
-		ctx:              ctx,
-		cancelFunc:       cancel,
+	friendGames, matchmaking := loadModes()
+	srv := &Server{
+		gamesByCode:       make(map[string]*lib.Game),
+		lobby:             make(map[lib.PlayerID]*lib.Player),
+		matchmakingQueue:  make([]queueEntry, 0),
+		readyChecks:       make(map[lib.PlayerID]*readyCheck),
+		clients:           make(map[*lib.Client]struct{}),
+		ctx:               ctx,
+		cancelFunc:        cancel,
+		resumeSecret:      loadResumeSecret(),
+		minMoveTime:       loadMinMoveTime(),
+		increment:         loadIncrement(),
+		spectatorDelay:    loadSpectatorDelay(),
+		connsPerIP:        make(map[string]int),
+		maxConnsPerIP:     loadMaxConnsPerIP(),
+		trustProxy:        os.Getenv(trustProxyEnv) == "on",
+		winStats:          lib.NewWinStats(),
+		gameTotals:        lib.NewGameTotals(),
+		statsPath:         os.Getenv(statsFileEnv),
+		puzzleRuns:        make(map[lib.PlayerID]*puzzleRun),
+		eventSubs:         make(map[string]map[*eventSubscriber]struct{}),
+		buildHash:         buildHash,
+		featuredCriterion: loadFeaturedCriterion(),
+		seriesTieBreak:    loadSeriesTieBreak(),
+		historySize:       loadHistorySize(),
+		puzzleRand:        mathrand.New(mathrand.NewPCG(mathrand.Uint64(), mathrand.Uint64())),
+		adminToken:        os.Getenv(adminTokenEnv),
+
+		friendGamesEnabled: friendGames,
+		matchmakingEnabled: matchmaking,
+		chatEnabled:        os.Getenv(chatEnv) != "off",
+	}
+	srv.loadStats()
+	srv.history = openHistory(os.Getenv(historyFileEnv), srv.historySize)
+	return srv
+}
+
+// capabilities lists the optional features of this server for the welcome message
+func (srv *Server) capabilities() *lib.ServerCapabilities {
+	return &lib.ServerCapabilities{
+		FriendGames: srv.friendGamesEnabled,
+		Matchmaking: srv.matchmakingEnabled,
+		Chat:        srv.chatEnabled,
+		Series:      srv.friendGamesEnabled,
+		Bot:         true,
+		FlatBoard:   true,
+		Puzzles:     true,
+	}
+}
+
+// loadModes reads the enabled game modes from the environment, e.g. "friend" or "friend,matchmaking"
+// Unset, or without any known mode, both modes stay enabled
+func loadModes() (friendGames, matchmaking bool) {
+	value := os.Getenv(modesEnv)
+	if value == "" {
+		return true, true
+	}
+
+	for _, mode := range strings.Split(value, ",") {
+		switch strings.TrimSpace(mode) {
+		case "friend":
+			friendGames = true
+		case "matchmaking":
+			matchmaking = true
+		default:
+			log.Printf("Ignoring unknown mode %q in %s", mode, modesEnv)
+		}
+	}
+
+	if !friendGames && !matchmaking {
+		log.Printf("No valid mode in %s %q, enabling all modes", modesEnv, value)
+		return true, true
+	}
+	return friendGames, matchmaking
+}
+
+// loadMinMoveTime reads the minimum think time per move from the environment, e.g. "200ms"
+func loadMinMoveTime() time.Duration {
+	value := os.Getenv(minMoveTimeEnv)
+	if value == "" {
+		return 0
+	}
+
+	minMoveTime, err := time.ParseDuration(value)
+	if err != nil || minMoveTime < 0 {
+		log.Printf("Ignoring invalid %s %q", minMoveTimeEnv, value)
+		return 0
+	}
+	return minMoveTime
+}
+
+// loadIncrement reads the time added per move from the environment, e.g. "2s", none by default
+func loadIncrement() time.Duration {
+	value := os.Getenv(incrementEnv)
+	if value == "" {
+		return 0
+	}
+
+	increment, err := time.ParseDuration(value)
+	if err != nil || increment < 0 || increment > maxIncrement {
+		log.Printf("Ignoring invalid %s %q", incrementEnv, value)
+		return 0
+	}
+	return increment
+}
+
+// loadSpectatorDelay reads how far behind spectators watch games from the environment, e.g. "30s", live by default
+func loadSpectatorDelay() time.Duration {
+	value := os.Getenv(spectatorDelayEnv)
+	if value == "" {
+		return 0
+	}
+
+	delay, err := time.ParseDuration(value)
+	if err != nil || delay < 0 || delay > maxSpectatorDelay {
+		log.Printf("Ignoring invalid %s %q", spectatorDelayEnv, value)
+		return 0
+	}
+	return delay
+}
+
+// timeControlFor returns the clock and increment picked for a friend game
+// Without a choice the game gets the server defaults, a clock left unset keeps the default clock
+func (srv *Server) timeControlFor(data lib.CreateGameData) (clock, increment time.Duration, err error) {
+	if data.ClockSeconds == 0 && data.IncrementSeconds == 0 {
+		return initialClockDuration, srv.increment, nil
+	}
+
+	clock = time.Duration(data.ClockSeconds) * time.Second
+	if data.ClockSeconds == 0 {
+		clock = initialClockDuration
+	}
+	increment = time.Duration(data.IncrementSeconds) * time.Second
+	if clock < minCustomClock || clock > maxCustomClock || increment < 0 || increment > maxIncrement {
+		return 0, 0, lib.ErrInvalidTimeControl
+	}
+	return clock, increment, nil
+}
+
+// loadMaxConnsPerIP reads the per-IP connection limit from the environment, 0 disables it
+func loadMaxConnsPerIP() int {
+	value := os.Getenv(maxConnsPerIPEnv)
+	if value == "" {
+		return defaultMaxConnsPerIP
+	}
+
+	limit, err := strconv.Atoi(value)
+	if err != nil || limit < 0 {
+		log.Printf("Ignoring invalid %s %q", maxConnsPerIPEnv, value)
+		return defaultMaxConnsPerIP
 	}
+	return limit
+}
+
+// loadHistorySize reads how many finished games each player keeps, at least one
+func loadHistorySize() int {
+	value := os.Getenv(historySizeEnv)
+	if value == "" {
+		return defaultHistorySize
+	}
+
+	size, err := strconv.Atoi(value)
+	if err != nil || size < 1 {
+		log.Printf("Ignoring invalid %s %q", historySizeEnv, value)
+		return defaultHistorySize
+	}
+	return size
+}
+
+// loadSeriesTieBreak reads how even series are decided, sudden death by default
+func loadSeriesTieBreak() lib.SeriesTieBreak {
+	value := os.Getenv(seriesTieBreakEnv)
+	if value == "" {
+		return lib.TieBreakSuddenDeath
+	}
+
+	tieBreak, ok := lib.ParseSeriesTieBreak(value)
+	if !ok {
+		log.Printf("Ignoring invalid %s %q", seriesTieBreakEnv, value)
+		return lib.TieBreakSuddenDeath
+	}
+	return tieBreak
+}
+
+// loadResumeSecret reads the resume token secret from the environment
+// Without it a random secret is used, so tokens do not survive a restart
+func loadResumeSecret() []byte {
+	if secret := os.Getenv(resumeSecretEnv); secret != "" {
+		return []byte(secret)
+	}
+
+	secret := make([]byte, resumeSecretLength)
+	if _, err := rand.Read(secret); err != nil {
+		panic("failed to generate resume secret: " + err.Error())
+	}
+	return secret
 }
 
 // StartPeriodicCleanup starts a background goroutine that cleans up stale games
 func (srv *Server) StartPeriodicCleanup() {
 	// Create ticker that fires every 30 seconds
 	ticker := time.NewTicker(cleanupInterval)
+	syncTicker := time.NewTicker(timeSyncInterval)
 
 	// Start background goroutine (runs independently)
+	srv.background.Add(1)
 	go func() {
 		// Stop ticker when goroutine exits to free resources
+		defer srv.background.Done()
 		defer ticker.Stop()
+		defer syncTicker.Stop()
 
 		for {
 			// Select waits for one of these events
@@ -65,10 +372,24 @@ func (srv *Server) StartPeriodicCleanup() {
 				// Ticker fired that will run cleanup
 				srv.mu.Lock()
 				srv.cleanupStaleGames()
+				srv.refreshFeatured()
 				srv.mu.Unlock()
 
+				// Stats have locks of their own, the file is written outside mu
+				if err := srv.persistStats(); err != nil {
+					log.Printf("Failed to save stats: %v", err)
+				}
+
+			case <-syncTicker.C:
+				srv.mu.RLock()
+				srv.syncClocks()
+				srv.mu.RUnlock()
+
 			case <-srv.ctx.Done():
-				// When server shutdown, exit go routine
+				// When server shutdown, save the stats a last time and exit go routine
+				if err := srv.persistStats(); err != nil {
+					log.Printf("Failed to save stats: %v", err)
+				}
 				return
 			}
 		}
@@ -83,6 +404,42 @@ func (srv *Server) sendError(client *lib.Client, err error) {
 	})
 }
 
+// sendErrorCode sends an error message with a machine readable code to a client
+func (srv *Server) sendErrorCode(client *lib.Client, err error, code string) {
+	client.Send(lib.Message{
+		Type: lib.MsgError,
+		Data: lib.ErrorData{Message: err.Error(), Code: code},
+	})
+}
+
+// sendWelcome sends the welcome message with the server protocol version
+func (srv *Server) sendWelcome(player *lib.Player) {
+	player.Send(lib.Message{
+		Type:    lib.MsgWelcome,
+		Version: lib.ProtocolVersion,
+		Data: lib.WelcomeData{
+			PlayerID:     player.ID,
+			Username:     player.Username,
+			ResumeToken:  lib.MintResumeToken(player.ID, srv.resumeSecret, time.Now()),
+			Prefs:        player.GetPrefs(),
+			Wins:         player.GetWins(),
+			Unlocks:      lib.UnlocksFor(player.GetWins()),
+			Capabilities: srv.capabilities(),
+			BuildHash:    srv.buildHash,
+		},
+	})
+}
+
+// activeGameFor returns the waiting or playing game of a player, if any
+func (srv *Server) activeGameFor(id lib.PlayerID) *lib.Game {
+	for _, game := range srv.gamesByCode {
+		if game.GetStatus() != lib.StatusFinished && game.HasPlayer(id) {
+			return game
+		}
+	}
+	return nil
+}
+
 // findGameForClient finds and caches the game for a client
 func (srv *Server) findGameForClient(client *lib.Client) *lib.Game {
 	// Try cached game code first
@@ -117,6 +474,7 @@ func (srv *Server) buildGameState(game *lib.Game, playerID lib.PlayerID) lib.Gam
 		Code:           game.Code,
 		Status:         game.GetStatus(),
 		Result:         game.Result,
+		DrawReason:     game.DrawReason,
 		Board:          game.Board.ToArray(),
 		Players:        srv.getPlayerInfos(game),
 		PlayerIdx:      game.GetPlayerIndex(playerID),
@@ -124,18 +482,158 @@ func (srv *Server) buildGameState(game *lib.Game, playerID lib.PlayerID) lib.Gam
 		MoveCount:      game.MoveCount,
 		TimeRemaining:  srv.getTimeRemaining(game),
 		ReplayRequests: game.ReplayRequests,
+		AllowReplay:    game.AllowReplay,
+		Ranked:         game.Ranked,
+		Paused:         game.IsPaused(),
+		GraceRemaining: graceRemaining(game, time.Now()).Milliseconds(),
 		LastMove:       game.LastMove,
+		Moves:          game.GetMoves(),
+		BestOf:         game.BestOf,
+		Score:          game.GetScore(),
+		SeriesOver:     game.SeriesOver,
+		SeriesResult:   game.GetSeriesResult(),
 	}
 }
 
-// broadcastToGame sends a message to all players in a game
+// buildGameStart constructs game start data
+func (srv *Server) buildGameStart(game *lib.Game) lib.GameStartData {
+	return lib.GameStartData{
+		Code:          game.Code,
+		CurrentTurn:   game.CurrentTurn,
+		Players:       srv.getPlayerInfos(game),
+		TimeRemaining: srv.getTimeRemaining(game),
+		AllowReplay:   game.AllowReplay,
+		Ranked:        game.Ranked,
+		BestOf:        game.BestOf,
+		Score:         game.GetScore(),
+	}
+}
+
+// buildGameOver constructs game over data
+func (srv *Server) buildGameOver(game *lib.Game) lib.GameOverData {
+	return lib.GameOverData{
+		Result:       game.Result,
+		DrawReason:   game.DrawReason,
+		Board:        game.Board.ToArray(),
+		WinningCells: winningCells(game.WinningCells),
+		Score:        game.GetScore(),
+		SeriesOver:   !game.InSeries() || game.SeriesOver,
+		SeriesResult: game.GetSeriesResult(),
+	}
+}
+
+// winningCells lists the cells of a winning line as row and column pairs
+func winningCells(line []lib.LastMove) [][2]int {
+	if len(line) == 0 {
+		return nil
+	}
+
+	cells := make([][2]int, len(line))
+	for i, cell := range line {
+		cells[i] = [2]int{cell.Row, cell.Col}
+	}
+	return cells
+}
+
+// broadcastToGame sends a message to all players in a game and to its spectators
 func (srv *Server) broadcastToGame(game *lib.Game, msg lib.Message) {
-	players := game.GetPlayers()
-	for _, p := range players {
+	for _, p := range game.GetMembers() {
 		if p != nil {
 			p.Send(msg)
 		}
 	}
+
+	// Starts and states are built for the players, spectators get the observer state instead
+	switch msg.Type {
+	case lib.MsgGameStart, lib.MsgGameState:
+		msg = lib.Message{Type: lib.MsgGameState, Data: srv.buildObserverState(game)}
+	}
+	game.BroadcastToSpectators(msg)
+}
+
+// syncClocks sends the authoritative clocks to every game in progress, the caller holds mu
+// Waiting, paused and finished games have frozen clocks and are skipped
+func (srv *Server) syncClocks() {
+	for _, game := range srv.gamesByCode {
+		if game.GetStatus() != lib.StatusPlaying || game.IsPaused() {
+			continue
+		}
+		srv.broadcastToGame(game, lib.Message{
+			Type: lib.MsgTimeSync,
+			Data: lib.TimeSyncData{
+				TimeRemaining: srv.getTimeRemaining(game),
+				CurrentTurn:   game.CurrentTurn,
+			},
+		})
+	}
+}
+
+// broadcastGameState sends each player of a game its own view of the state, spectators the observer view
+func (srv *Server) broadcastGameState(game *lib.Game) {
+	for _, p := range game.GetMembers() {
+		if p != nil {
+			srv.sendGameState(p, game)
+		}
+	}
+
+	if len(game.GetSpectators()) == 0 {
+		return
+	}
+	game.BroadcastToSpectators(lib.Message{Type: lib.MsgGameState, Data: srv.buildObserverState(game)})
+}
+
+// pauseForDisconnect pauses the game of a disconnected client if its policy allows it
+// and tells the remaining players that their opponent dropped
+func (srv *Server) pauseForDisconnect(client *lib.Client) {
+	game := srv.findGameForClient(client)
+	if game == nil {
+		return
+	}
+
+	if game.PauseOnDisconnect && game.Pause() {
+		srv.broadcastGameState(game)
+	}
+	srv.announcePresence(game, client.PlayerID, lib.MsgOpponentDisconnected)
+}
+
+// announcePresence tells the players of a running game that a side dropped or came back
+func (srv *Server) announcePresence(game *lib.Game, playerID lib.PlayerID, msgType lib.MessageType) {
+	if game.GetStatus() != lib.StatusPlaying {
+		return
+	}
+
+	idx := game.GetPlayerIndex(playerID)
+	if idx < 0 {
+		return
+	}
+
+	srv.broadcastToGame(game, lib.Message{
+		Type: msgType,
+		Data: lib.PresenceData{PlayerIdx: idx, Paused: game.IsPaused()},
+	})
+}
+
+// resumeIfReconnected resumes a paused game once all its players are back
+func (srv *Server) resumeIfReconnected(game *lib.Game) bool {
+	if !game.IsPaused() {
+		return false
+	}
+
+	for _, p := range game.GetMembers() {
+		if p == nil || !p.IsConnected() {
+			return false
+		}
+	}
+
+	if !game.Resume() {
+		return false
+	}
+
+	srv.broadcastGameState(game)
+
+	// A bot reply dropped while the game was paused is played now
+	srv.scheduleBotTurn(game)
+	return true
 }
 
 // handleTimeout is called when a player's timer expires
@@ -152,21 +650,135 @@ func (srv *Server) handleTimeout(gameCode string, loserIdx int) {
 		return
 	}
 
+	// Running out of time while disconnected counts as leaving
+	if players := game.GetPlayers(); players[loserIdx] != nil && !players[loserIdx].IsConnected() {
+		srv.penalizeEarlyLeave(game, loserIdx)
+	}
+
 	// Player loses by timeout
-	game.Forfeit(loserIdx)
+	game.ForfeitOnTime(loserIdx)
 
 	// Notify both players if game actually ended
-	if game.GetStatus() == lib.StatusFinished {
+	if game.GetStatus() != lib.StatusFinished {
+		return
+	}
+	srv.announceGameOver(game)
+
+	// A timeout only loses the round, an undecided series goes on
+	if game.NextRound() {
 		srv.broadcastToGame(game, lib.Message{
-			Type: lib.MsgGameOver,
-			Data: lib.GameOverData{
-				Result: game.Result,
-				Board:  game.Board.ToArray(),
-			},
+			Type: lib.MsgGameStart,
+			Data: srv.buildGameStart(game),
 		})
 	}
 }
 
+// announceGameOver tells the players how a game ended and credits the winner
+// Every finished game goes through here so no win is missed
+func (srv *Server) announceGameOver(game *lib.Game) {
+	gameOver := lib.Message{
+		Type: lib.MsgGameOver,
+		Data: srv.buildGameOver(game),
+	}
+	srv.broadcastToGame(game, gameOver)
+	srv.publishGameEvent(game, gameOver)
+	srv.flagSuspiciousTiming(game)
+
+	srv.winStats.Record(game.WinMethod)
+	srv.gameTotals.Record(game.Result, time.Now())
+
+	// An aborted game has no result, it is neither kept in the history nor breaks a streak
+	if game.Result == lib.ResultNone {
+		return
+	}
+
+	if err := srv.history.Append(game.Record(time.Now()), game.Seats()); err != nil {
+		log.Printf("Failed to save game %s to the history: %v", game.Code, err)
+	}
+
+	gains := srv.applyRatingChange(game)
+
+	// Result sides are those of the round that just ended, seats swap only when the next one starts
+	players := game.GetPlayers()
+	for idx, player := range players {
+		if player == nil {
+			continue
+		}
+		if game.Result != lib.GameResult(idx+1) {
+			// Only a broken streak or a rating change is worth telling the player about
+			if player.BreakStreak() || gains[idx] != 0 {
+				srv.sendStats(player, nil, gains[idx])
+			}
+			continue
+		}
+		srv.sendStats(player, player.RecordWin(), gains[idx])
+	}
+}
+
+// sendStats tells a player their session wins, streaks and rating, with the points the last game moved it by
+func (srv *Server) sendStats(player *lib.Player, newUnlocks []string, ratingGain int) {
+	streak, bestStreak := player.GetStreaks()
+	player.Send(lib.Message{
+		Type: lib.MsgStats,
+		Data: lib.StatsData{
+			Wins:       player.GetWins(),
+			Unlocks:    lib.UnlocksFor(player.GetWins()),
+			NewUnlocks: newUnlocks,
+			Streak:     streak,
+			BestStreak: bestStreak,
+			Rating:     player.GetRating(),
+			RatingGain: ratingGain,
+		},
+	})
+}
+
+// penalizeEarlyLeave applies a matchmaking cooldown to a player leaving an undecided ranked game
+func (srv *Server) penalizeEarlyLeave(game *lib.Game, playerIdx int) {
+	if !game.Ranked || game.GetStatus() != lib.StatusPlaying {
+		return
+	}
+
+	player := game.GetPlayers()[playerIdx]
+	if player == nil {
+		return
+	}
+
+	cooldown := player.ApplyCooldown(time.Now())
+	log.Printf("Player %q left a ranked game early, matchmaking cooldown %v", player.ID, cooldown)
+}
+
+// gracePeriod returns how long a game waits for its players to come back
+func gracePeriod(game *lib.Game) time.Duration {
+	if game.ReconnectGrace > 0 {
+		return game.ReconnectGrace
+	}
+	return reconnectGracePeriod
+}
+
+// graceDeadline returns when an idle game gets cleaned up, ok is false while the game is active
+func graceDeadline(game *lib.Game) (deadline time.Time, ok bool) {
+	switch game.GetStatus() {
+	case lib.StatusFinished:
+		return game.LastPlayedAt.Add(gracePeriod(game)), true
+	case lib.StatusWaiting:
+		return game.CreatedAt.Add(gracePeriod(game)), true
+	case lib.StatusPlaying:
+		if game.IsPaused() {
+			return game.LastPlayedAt.Add(pausedGameMaxAge), true
+		}
+	}
+	return time.Time{}, false
+}
+
+// graceRemaining returns how long an idle game is still kept for reconnection, zero for active games
+func graceRemaining(game *lib.Game, now time.Time) time.Duration {
+	deadline, ok := graceDeadline(game)
+	if !ok || !deadline.After(now) {
+		return 0
+	}
+	return deadline.Sub(now)
+}
+
 // cleanupStaleGames removes finished games and disconnected players
 func (srv *Server) cleanupStaleGames() {
 	now := time.Now()
@@ -175,16 +787,12 @@ func (srv *Server) cleanupStaleGames() {
 	for code, game := range srv.gamesByCode {
 		shouldDelete := false
 
-		// If finished games, keep alive for a time to allow reconnection, then delete
-		if game.GetStatus() == lib.StatusFinished {
-			if now.Sub(game.LastPlayedAt) > reconnectGracePeriod {
-				shouldDelete = true
-			}
+		// Finished, waiting and paused games are kept until their grace period expires
+		if deadline, idle := graceDeadline(game); idle {
+			shouldDelete = now.After(deadline)
 
-			// For waiting games delete if creator left or waited too long
-		} else if game.GetStatus() == lib.StatusWaiting {
-			players := game.GetPlayers()
-			if players[0] == nil || now.Sub(game.CreatedAt) > reconnectGracePeriod {
+			// Waiting games are also deleted if their creator left
+			if game.GetStatus() == lib.StatusWaiting && game.GetPlayers()[0] == nil {
 				shouldDelete = true
 			}
 
@@ -199,7 +807,7 @@ func (srv *Server) cleanupStaleGames() {
 				}
 			}
 
-			if bothDisconnected && now.Sub(game.LastPlayedAt) > reconnectGracePeriod {
+			if bothDisconnected && now.Sub(game.LastPlayedAt) > gracePeriod(game) {
 				shouldDelete = true
 			}
 		}
@@ -208,6 +816,7 @@ func (srv *Server) cleanupStaleGames() {
 			// Stop timers and free resources
 			game.Cleanup()
 			delete(srv.gamesByCode, code)
+			srv.closeGameEvents(code)
 		}
 	}
 
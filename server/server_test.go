@@ -0,0 +1,237 @@
+// Copyright (c) 2025 Haute école d'ingénierie et d'architecture de Fribourg
+// SPDX-License-Identifier: Apache-2.0
+// Author: Marvin Egger marvin.egger@hotmail.ch
+// Created: 16.10.2026
+
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/marvinEgger/GOnnect4/server/lib"
+)
+
+// TestGraceRemaining_Decreases tests that finished games count down to their cleanup
+func TestGraceRemaining_Decreases(t *testing.T) {
+	game := lib.NewGame(initialClockDuration, 0)
+	game.AddPlayer(lib.NewPlayer("Alice", 0))
+	game.AddPlayer(lib.NewPlayer("Bob", 0))
+	game.Forfeit(0)
+	defer game.Cleanup()
+
+	now := time.Now()
+	game.LastPlayedAt = now.Add(-30 * time.Second)
+
+	first := graceRemaining(game, now)
+	if first != reconnectGracePeriod-30*time.Second {
+		t.Errorf("Expected %v remaining, got %v", reconnectGracePeriod-30*time.Second, first)
+	}
+
+	second := graceRemaining(game, now.Add(10*time.Second))
+	if first-second != 10*time.Second {
+		t.Errorf("Remaining time should decrease by 10s, got %v", first-second)
+	}
+
+	if graceRemaining(game, now.Add(reconnectGracePeriod)) != 0 {
+		t.Error("Remaining time should be zero once the grace period expired")
+	}
+}
+
+// TestGraceRemaining_ActiveGame tests that active games report no grace countdown
+func TestGraceRemaining_ActiveGame(t *testing.T) {
+	game := lib.NewGame(initialClockDuration, 0)
+	game.AddPlayer(lib.NewPlayer("Alice", 0))
+	game.AddPlayer(lib.NewPlayer("Bob", 0))
+	defer game.Cleanup()
+
+	if graceRemaining(game, time.Now()) != 0 {
+		t.Error("Active game should not report a grace countdown")
+	}
+}
+
+// TestCleanupStaleGames_GracePerMode tests that ranked games are cleaned up sooner than friend games
+func TestCleanupStaleGames_GracePerMode(t *testing.T) {
+	srv := NewServer()
+	defer srv.cancelFunc()
+
+	friendMover, friend := startTestGame(srv)
+	srv.handleForfeit(friendMover)
+
+	carol := loginTestPlayer(srv, "Carol")
+	dave := loginTestPlayer(srv, "Dave")
+	matchTestPlayers(srv, carol, dave)
+	ranked := srv.findGameForClient(carol)
+	srv.handleForfeit(carol)
+
+	// Past the ranked grace, still within the friend game one
+	idle := time.Now().Add(-(rankedGracePeriod + reconnectGracePeriod) / 2)
+	friend.LastPlayedAt = idle
+	ranked.LastPlayedAt = idle
+
+	srv.mu.Lock()
+	srv.cleanupStaleGames()
+	srv.mu.Unlock()
+
+	if srv.gamesByCode[ranked.Code] != nil {
+		t.Error("Ranked game should be cleaned up after its shorter grace")
+	}
+	if srv.gamesByCode[friend.Code] == nil {
+		t.Error("Friend game should still be kept for its players")
+	}
+}
+
+// TestGraceRemaining_UsesGameGrace tests that the countdown follows the grace of the game
+func TestGraceRemaining_UsesGameGrace(t *testing.T) {
+	game := lib.NewGame(initialClockDuration, 0)
+	game.ReconnectGrace = 10 * time.Second
+	game.AddPlayer(lib.NewPlayer("Alice", 0))
+	game.AddPlayer(lib.NewPlayer("Bob", 0))
+	game.Forfeit(0)
+	defer game.Cleanup()
+
+	now := time.Now()
+	game.LastPlayedAt = now
+	if remaining := graceRemaining(game, now); remaining != 10*time.Second {
+		t.Errorf("Expected 10s remaining, got %v", remaining)
+	}
+}
+
+// TestHandleStats_CountsWinMethods tests that finished games are counted by how they were won
+func TestHandleStats_CountsWinMethods(t *testing.T) {
+	srv := NewServer()
+	defer srv.cancelFunc()
+
+	mover, game := startTestGame(srv)
+	srv.handleForfeit(mover)
+	if game.WinMethod != lib.WinForfeit {
+		t.Fatalf("Expected a forfeit win, got %q", game.WinMethod)
+	}
+
+	recorder := httptest.NewRecorder()
+	srv.handleStats(recorder, httptest.NewRequest(http.MethodGet, "/stats", nil))
+
+	var stats statsResponse
+	if err := json.NewDecoder(recorder.Body).Decode(&stats); err != nil {
+		t.Fatalf("Failed to decode stats: %v", err)
+	}
+	if stats.Wins[lib.WinForfeit] != 1 || stats.Wins[lib.WinHorizontal] != 0 {
+		t.Errorf("Expected one forfeit win, got %v", stats.Wins)
+	}
+}
+
+// TestAnnounceGameOver_RecordsHistory tests that finished games land in both players' bounded history
+func TestAnnounceGameOver_RecordsHistory(t *testing.T) {
+	t.Setenv(historySizeEnv, "2")
+	srv := NewServer()
+	defer srv.cancelFunc()
+
+	mover, game := startTestGame(srv)
+	srv.handleForfeit(mover)
+
+	// Two more results for the same pair push the forfeit out
+	game.Result = lib.ResultDraw
+	srv.announceGameOver(game)
+	game.Result = lib.ResultPlayer0Win
+	srv.announceGameOver(game)
+
+	for _, player := range game.GetPlayers() {
+		history := srv.history.Recent(lib.HistoryKey(player.Username), 0)
+		if len(history) != 2 {
+			t.Fatalf("Expected 2 games in the history of %s, got %d", player.Username, len(history))
+		}
+		if history[0].Result != lib.ResultPlayer0Win || history[1].Result != lib.ResultDraw {
+			t.Errorf("Expected the two latest results newest first, got %v then %v", history[0].Result, history[1].Result)
+		}
+		if history[0].Code != game.Code || history[0].Players[0] == "" || history[0].Players[1] == "" {
+			t.Errorf("Expected the record to name the game and both players, got %+v", history[0])
+		}
+	}
+}
+
+// TestHandleHistoryRequest_ListsFinishedGames tests that a player gets its finished games with its seat
+func TestHandleHistoryRequest_ListsFinishedGames(t *testing.T) {
+	srv := NewServer()
+	defer srv.cancelFunc()
+
+	mover, game := startTestGame(srv)
+	seat := game.GetPlayerIndex(mover.PlayerID)
+	srv.handleForfeit(mover)
+	drainMessages(mover)
+
+	srv.handleHistoryRequest(mover, lib.HistoryRequestData{Limit: 5})
+	msgs := drainMessages(mover)
+	if len(msgs) != 1 || msgs[0].Type != lib.MsgHistoryResponse {
+		t.Fatalf("Expected a history response, got %v", msgs)
+	}
+	games := msgs[0].Data.(lib.HistoryResponseData).Games
+	if len(games) != 1 || games[0].Code != game.Code || games[0].Seat != seat {
+		t.Errorf("Expected the forfeited game on seat %d, got %+v", seat, games)
+	}
+
+	stranger := newTestClient()
+	srv.handleHistoryRequest(stranger, lib.HistoryRequestData{})
+	if !hasError(drainMessages(stranger), lib.ErrPlayerNotFound) {
+		t.Error("A client that never logged in should be rejected")
+	}
+}
+
+// TestHandleHistoryRequest_SurvivesRestart tests that a player logging in after a restart finds its saved games
+func TestHandleHistoryRequest_SurvivesRestart(t *testing.T) {
+	t.Setenv(historyFileEnv, filepath.Join(t.TempDir(), "history.jsonl"))
+	srv := NewServer()
+	mover, game := startTestGame(srv)
+	username := srv.lobby[mover.PlayerID].Username
+	srv.handleForfeit(mover)
+	srv.cancelFunc()
+	srv.history.Close()
+
+	// The new session gets a new player ID
+	restarted := NewServer()
+	defer restarted.cancelFunc()
+	defer restarted.history.Close()
+	client := loginTestPlayer(restarted, username)
+
+	restarted.handleHistoryRequest(client, lib.HistoryRequestData{})
+	msgs := drainMessages(client)
+	if len(msgs) != 1 || msgs[0].Type != lib.MsgHistoryResponse {
+		t.Fatalf("Expected a history response, got %v", msgs)
+	}
+	if games := msgs[0].Data.(lib.HistoryResponseData).Games; len(games) != 1 || games[0].Code != game.Code {
+		t.Errorf("Expected the game played before the restart, got %+v", games)
+	}
+}
+
+// TestSyncClocks_OnlyPlayingGames tests that clocks are pushed to games in progress and nowhere else
+func TestSyncClocks_OnlyPlayingGames(t *testing.T) {
+	srv := NewServer()
+	defer srv.cancelFunc()
+
+	mover, game := startTestGame(srv)
+	host := loginTestPlayer(srv, "Carol")
+	srv.handleCreateGame(host, lib.CreateGameData{})
+	drainMessages(host)
+
+	srv.syncClocks()
+
+	var clocks *lib.TimeSyncData
+	for _, msg := range drainMessages(mover) {
+		if data, ok := msg.Data.(lib.TimeSyncData); ok && msg.Type == lib.MsgTimeSync {
+			clocks = &data
+		}
+	}
+	if clocks == nil {
+		t.Fatal("Players of a game in progress should get the clocks")
+	}
+	if clocks.CurrentTurn != game.CurrentTurn || clocks.TimeRemaining[clocks.CurrentTurn] > initialClockDuration.Milliseconds() {
+		t.Errorf("Unexpected clocks: %+v", *clocks)
+	}
+
+	if msgs := drainMessages(host); len(msgs) != 0 {
+		t.Errorf("A waiting game should not be synced, got %v", msgs)
+	}
+}
@@ -0,0 +1,76 @@
+// Copyright (c) 2025 Haute école d'ingénierie et d'architecture de Fribourg
+// SPDX-License-Identifier: Apache-2.0
+// Author: Marvin Egger marvin.egger@hotmail.ch
+// Created: 16.10.2026
+
+package main
+
+import (
+	"context"
+	"errors"
+
+	"github.com/marvinEgger/GOnnect4/server/lib"
+)
+
+// trackClient registers a new websocket connection so Shutdown can reach it
+// It reports false once the server is shutting down, the connection must then be refused
+func (srv *Server) trackClient(client *lib.Client) bool {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+
+	if srv.ctx.Err() != nil {
+		return false
+	}
+	srv.clients[client] = struct{}{}
+	srv.background.Add(1)
+	return true
+}
+
+// Shutdown stops the server: players are told, every game and timer is stopped and the connections are closed
+// It returns once the background goroutines have exited, or with the context error when ctx ends first
+func (srv *Server) Shutdown(ctx context.Context) error {
+	srv.mu.Lock()
+	// Cancelled under mu so no connection is tracked past this point
+	srv.cancelFunc()
+
+	for client := range srv.clients {
+		client.Send(lib.Message{Type: lib.MsgServerShutdown})
+		client.Shutdown()
+	}
+
+	for code, game := range srv.gamesByCode {
+		game.Cleanup()
+		srv.closeGameEvents(code)
+	}
+	for _, check := range srv.readyChecks {
+		check.timer.Stop()
+	}
+	for _, run := range srv.puzzleRuns {
+		run.timer.Stop()
+	}
+	if srv.queueUpdateTimer != nil {
+		srv.queueUpdateTimer.Stop()
+	}
+	srv.mu.Unlock()
+
+	var err error
+	if srv.httpServer != nil {
+		err = srv.httpServer.Shutdown(ctx)
+	}
+
+	// The cleanup loop saves the stats a last time before exiting
+	done := make(chan struct{})
+	go func() {
+		srv.background.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		if err == nil {
+			err = ctx.Err()
+		}
+	}
+
+	return errors.Join(err, srv.history.Close())
+}
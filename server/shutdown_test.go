@@ -0,0 +1,90 @@
+// Copyright (c) 2025 Haute école d'ingénierie et d'architecture de Fribourg
+// SPDX-License-Identifier: Apache-2.0
+// Author: Marvin Egger marvin.egger@hotmail.ch
+// Created: 16.10.2026
+
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/coder/websocket"
+	"github.com/coder/websocket/wsjson"
+	"github.com/marvinEgger/GOnnect4/server/lib"
+)
+
+// TestShutdown_NotifiesAndClosesConnections tests that an open connection is told, then closed as going away
+func TestShutdown_NotifiesAndClosesConnections(t *testing.T) {
+	srv := NewServer()
+	srv.StartPeriodicCleanup()
+	httpServer := httptest.NewServer(http.HandlerFunc(srv.handleWebSocket))
+	defer httpServer.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	conn, _, err := websocket.Dial(ctx, "ws"+strings.TrimPrefix(httpServer.URL, "http"), nil)
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	defer conn.CloseNow()
+
+	if err := wsjson.Write(ctx, conn, lib.Message{Type: lib.MsgLogin, Data: lib.LoginData{Username: "Alice"}}); err != nil {
+		t.Fatalf("Failed to log in: %v", err)
+	}
+	var welcome lib.Message
+	if err := wsjson.Read(ctx, conn, &welcome); err != nil || welcome.Type != lib.MsgWelcome {
+		t.Fatalf("Expected a welcome, got %v (%v)", welcome.Type, err)
+	}
+
+	// Read concurrently, the close handshake needs the client to answer
+	types := make(chan lib.MessageType, 16)
+	closeErr := make(chan error, 1)
+	go func() {
+		for {
+			var msg lib.Message
+			if err := wsjson.Read(ctx, conn, &msg); err != nil {
+				closeErr <- err
+				return
+			}
+			types <- msg.Type
+		}
+	}()
+
+	if err := srv.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown failed: %v", err)
+	}
+
+	if err := <-closeErr; websocket.CloseStatus(err) != websocket.StatusGoingAway {
+		t.Errorf("Expected the connection to be closed as going away, got %v", err)
+	}
+	close(types)
+	notified := false
+	for msgType := range types {
+		notified = notified || msgType == lib.MsgServerShutdown
+	}
+	if !notified {
+		t.Error("Expected a shutdown notice before the close")
+	}
+}
+
+// TestShutdown_StopsGames tests that game clocks stop and new connections are refused
+func TestShutdown_StopsGames(t *testing.T) {
+	srv := NewServer()
+	_, game := startTestGame(srv)
+
+	if err := srv.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown failed: %v", err)
+	}
+
+	if game.Timer != nil {
+		t.Error("Game timer should be stopped")
+	}
+	if srv.trackClient(newTestClient()) {
+		t.Error("Connections should be refused once shut down")
+	}
+}
@@ -0,0 +1,66 @@
+// Copyright (c) 2025 Haute école d'ingénierie et d'architecture de Fribourg
+// SPDX-License-Identifier: Apache-2.0
+// Author: Marvin Egger marvin.egger@hotmail.ch
+// Created: 16.10.2026
+
+package main
+
+import (
+	"strings"
+
+	"github.com/marvinEgger/GOnnect4/server/lib"
+)
+
+// handleSpectate registers a lobby player as a read-only watcher of a public game
+// Spectators receive the observer state, then every update broadcast to the game, all held back by its SpectatorDelay
+func (srv *Server) handleSpectate(client *lib.Client, data lib.SpectateData) {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+
+	player := srv.lobby[client.PlayerID]
+	if player == nil {
+		srv.sendError(client, lib.ErrPlayerNotFound)
+		return
+	}
+
+	// Watching while playing would mix both games on one screen
+	if srv.activeGameFor(client.PlayerID) != nil {
+		srv.sendError(client, lib.ErrPlayerAlreadyInGame)
+		return
+	}
+
+	if len(data.Code) > maxGameCodeLength {
+		data.Code = data.Code[:maxGameCodeLength]
+	}
+	game, exists := srv.gamesByCode[strings.ToUpper(data.Code)]
+	if !exists {
+		srv.sendError(client, lib.ErrGameNotFound)
+		return
+	}
+
+	// A player watches one game at a time
+	if client.Spectating != game.Code {
+		srv.stopSpectating(client)
+	}
+	if err := game.AddSpectator(player); err != nil {
+		srv.sendError(client, err)
+		return
+	}
+	client.Spectating = game.Code
+
+	game.SendToSpectator(player, lib.Message{
+		Type: lib.MsgGameState,
+		Data: srv.buildObserverState(game),
+	})
+}
+
+// stopSpectating removes a client from the game it watches, expects srv.mu to be held
+func (srv *Server) stopSpectating(client *lib.Client) {
+	if client.Spectating == "" {
+		return
+	}
+	if game := srv.gamesByCode[client.Spectating]; game != nil {
+		game.RemoveSpectator(client.PlayerID)
+	}
+	client.Spectating = ""
+}
@@ -0,0 +1,164 @@
+// Copyright (c) 2025 Haute école d'ingénierie et d'architecture de Fribourg
+// SPDX-License-Identifier: Apache-2.0
+// Author: Marvin Egger marvin.egger@hotmail.ch
+// Created: 16.10.2026
+
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/marvinEgger/GOnnect4/server/lib"
+)
+
+// TestHandleSpectate_FollowsGame tests that a spectator gets the state and every move but cannot play
+func TestHandleSpectate_FollowsGame(t *testing.T) {
+	srv := NewServer()
+	defer srv.cancelFunc()
+
+	mover, game := startTestGame(srv)
+	defer game.Cleanup()
+	carol := loginTestPlayer(srv, "Carol")
+
+	srv.handleSpectate(carol, lib.SpectateData{Code: game.Code})
+	msgs := drainMessages(carol)
+	if len(msgs) != 1 || msgs[0].Type != lib.MsgGameState {
+		t.Fatalf("Expected the game state, got %v", msgs)
+	}
+	state := msgs[0].Data.(lib.GameStateData)
+	if state.PlayerIdx != -1 || state.Players[0].ID != "" || state.Players[1].ID != "" {
+		t.Errorf("A spectator should get the observer state, got %+v", state)
+	}
+
+	srv.handlePlay(mover, lib.PlayData{Column: 3})
+	if !hasMessage(drainMessages(carol), lib.MsgMove) {
+		t.Error("A spectator should receive the moves")
+	}
+
+	// Whoever's turn it is, the spectator never takes it
+	srv.handlePlay(carol, lib.PlayData{Column: 0})
+	if msgs := drainMessages(carol); !hasMessage(msgs, lib.MsgError) || hasMessage(msgs, lib.MsgMove) {
+		t.Errorf("A spectator should not be able to play, got %v", msgs)
+	}
+	if game.GetMoveCount() != 1 {
+		t.Errorf("Expected 1 move on the board, got %d", game.GetMoveCount())
+	}
+}
+
+// TestHandleSpectate_StopsOnLeave tests that leaving to the lobby stops the updates
+func TestHandleSpectate_StopsOnLeave(t *testing.T) {
+	srv := NewServer()
+	defer srv.cancelFunc()
+
+	mover, game := startTestGame(srv)
+	defer game.Cleanup()
+	carol := loginTestPlayer(srv, "Carol")
+
+	srv.handleSpectate(carol, lib.SpectateData{Code: game.Code})
+	srv.handleLeaveLobby(carol)
+	drainMessages(carol)
+
+	srv.handlePlay(mover, lib.PlayData{Column: 3})
+	if hasMessage(drainMessages(carol), lib.MsgMove) {
+		t.Error("A former spectator should not receive moves")
+	}
+	if len(game.GetSpectators()) != 0 {
+		t.Error("The spectator should be removed from the game")
+	}
+}
+
+// TestHandleSpectate_Rejected tests the games a player may not watch
+func TestHandleSpectate_Rejected(t *testing.T) {
+	srv := NewServer()
+	defer srv.cancelFunc()
+
+	mover, game := startTestGame(srv)
+	defer game.Cleanup()
+
+	srv.handleSpectate(mover, lib.SpectateData{Code: game.Code})
+	if !hasError(drainMessages(mover), lib.ErrPlayerAlreadyInGame) {
+		t.Error("A player should not spectate while playing")
+	}
+
+	carol := loginTestPlayer(srv, "Carol")
+	srv.handleSpectate(carol, lib.SpectateData{Code: "NOPE1"})
+	if !hasError(drainMessages(carol), lib.ErrGameNotFound) {
+		t.Error("Expected ErrGameNotFound for an unknown code")
+	}
+
+	// Matchmaking games and reserved friend games stay private
+	game.Public = false
+	srv.handleSpectate(carol, lib.SpectateData{Code: game.Code})
+	if !hasError(drainMessages(carol), lib.ErrPrivateSpectate) {
+		t.Error("Expected ErrPrivateSpectate for a matchmaking game")
+	}
+	game.Public = true
+	game.Invite = "Dave"
+	srv.handleSpectate(carol, lib.SpectateData{Code: game.Code})
+	if !hasError(drainMessages(carol), lib.ErrPrivateSpectate) {
+		t.Error("Expected ErrPrivateSpectate for a reserved game")
+	}
+	if len(game.GetSpectators()) != 0 {
+		t.Error("No spectator should have been added")
+	}
+}
+
+// TestHandleSpectate_Delayed tests that the state and the moves reach spectators only after the game's delay
+func TestHandleSpectate_Delayed(t *testing.T) {
+	srv := NewServer()
+	defer srv.cancelFunc()
+
+	mover, game := startTestGame(srv)
+	defer game.Cleanup()
+	game.SpectatorDelay = 50 * time.Millisecond
+	carol := loginTestPlayer(srv, "Carol")
+
+	srv.handleSpectate(carol, lib.SpectateData{Code: game.Code})
+	srv.handlePlay(mover, lib.PlayData{Column: 3})
+	if msgs := drainMessages(carol); len(msgs) != 0 {
+		t.Fatalf("Expected nothing before the delay, got %v", msgs)
+	}
+
+	time.Sleep(100 * time.Millisecond)
+	msgs := drainMessages(carol)
+	if !hasMessage(msgs, lib.MsgGameState) || !hasMessage(msgs, lib.MsgMove) {
+		t.Errorf("Expected the state and the move after the delay, got %v", msgs)
+	}
+}
+
+// TestHandleSpectate_StopsOnPlay tests that creating a game or queueing ends watching another one
+func TestHandleSpectate_StopsOnPlay(t *testing.T) {
+	srv := NewServer()
+	defer srv.cancelFunc()
+
+	_, game := startTestGame(srv)
+	defer game.Cleanup()
+
+	carol := loginTestPlayer(srv, "Carol")
+	srv.handleSpectate(carol, lib.SpectateData{Code: game.Code})
+	srv.handleCreateGame(carol, lib.CreateGameData{})
+	if carol.Spectating != "" || len(game.GetSpectators()) != 0 {
+		t.Error("Creating a game should stop spectating")
+	}
+
+	dave := loginTestPlayer(srv, "Dave")
+	srv.handleSpectate(dave, lib.SpectateData{Code: game.Code})
+	srv.handleJoinMatchmaking(dave)
+	if dave.Spectating != "" || len(game.GetSpectators()) != 0 {
+		t.Error("Joining matchmaking should stop spectating")
+	}
+}
+
+// TestHandleCreateGame_SpectatorDelay tests that new games hold spectators back by the configured delay
+func TestHandleCreateGame_SpectatorDelay(t *testing.T) {
+	srv := NewServer()
+	defer srv.cancelFunc()
+	srv.spectatorDelay = 30 * time.Second
+
+	_, game := startTestGame(srv)
+	defer game.Cleanup()
+	if game.SpectatorDelay != srv.spectatorDelay {
+		t.Errorf("Expected a spectator delay of %v, got %v", srv.spectatorDelay, game.SpectatorDelay)
+	}
+}
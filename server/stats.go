@@ -0,0 +1,106 @@
+// Copyright (c) 2025 Haute école d'ingénierie et d'architecture de Fribourg
+// SPDX-License-Identifier: Apache-2.0
+// Author: Marvin Egger marvin.egger@hotmail.ch
+// Created: 16.10.2026
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/marvinEgger/GOnnect4/server/lib"
+)
+
+// statsResponse is the body served on /stats, the stats file holds the same document
+type statsResponse struct {
+	Wins  map[lib.WinMethod]int `json:"wins"`  // Finished games by how they were won
+	Games lib.TotalsSnapshot    `json:"games"` // Finished games by outcome, all time and today
+}
+
+// statsSnapshot copies the aggregate play statistics
+func (srv *Server) statsSnapshot() statsResponse {
+	return statsResponse{
+		Wins:  srv.winStats.Snapshot(),
+		Games: srv.gameTotals.Snapshot(time.Now()),
+	}
+}
+
+// handleStats serves aggregate play statistics as JSON
+func (srv *Server) handleStats(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(srv.statsSnapshot()); err != nil {
+		log.Printf("Failed to write stats: %v", err)
+	}
+}
+
+// loadStats restores the totals saved by a previous run, a missing file starts from zero
+func (srv *Server) loadStats() {
+	if srv.statsPath == "" {
+		return
+	}
+
+	data, err := os.ReadFile(srv.statsPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return
+	}
+	var saved statsResponse
+	if err == nil {
+		err = json.Unmarshal(data, &saved)
+	}
+	if err != nil {
+		log.Printf("Ignoring unreadable stats file %q: %v", srv.statsPath, err)
+		return
+	}
+
+	srv.winStats.Restore(saved.Wins)
+	srv.gameTotals.Restore(saved.Games)
+}
+
+// persistStats saves the totals to the stats file, if one is configured
+// The file is written next to its destination and renamed over it, so a crash never leaves it half written
+func (srv *Server) persistStats() error {
+	if srv.statsPath == "" {
+		return nil
+	}
+
+	// Writers take turns so an older snapshot never replaces a newer one
+	srv.statsFileMu.Lock()
+	defer srv.statsFileMu.Unlock()
+
+	data, err := json.MarshalIndent(srv.statsSnapshot(), "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(srv.statsPath), filepath.Base(srv.statsPath)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // Only does anything when the rename did not happen
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), srv.statsPath)
+}
@@ -0,0 +1,70 @@
+// Copyright (c) 2025 Haute école d'ingénierie et d'architecture de Fribourg
+// SPDX-License-Identifier: Apache-2.0
+// Author: Marvin Egger marvin.egger@hotmail.ch
+// Created: 16.10.2026
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/marvinEgger/GOnnect4/server/lib"
+)
+
+// TestPersistStats_RoundTrip tests that a restarted server picks up the saved totals
+func TestPersistStats_RoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "stats.json")
+	t.Setenv(statsFileEnv, path)
+
+	srv := NewServer()
+	defer srv.cancelFunc()
+	srv.winStats.Record(lib.WinDiagonal)
+	srv.winStats.Record(lib.WinTimeout)
+	srv.gameTotals.Record(lib.ResultPlayer0Win, time.Now())
+	srv.gameTotals.Record(lib.ResultPlayer1Win, time.Now())
+	srv.gameTotals.Record(lib.ResultDraw, time.Now())
+
+	if err := srv.persistStats(); err != nil {
+		t.Fatalf("Saving the stats failed: %v", err)
+	}
+
+	restarted := NewServer()
+	defer restarted.cancelFunc()
+	if got, want := restarted.statsSnapshot(), srv.statsSnapshot(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected the saved stats back, got %+v want %+v", got, want)
+	}
+	if games := restarted.statsSnapshot().Games.AllTime; games.Games != 3 || games.Draws != 1 {
+		t.Errorf("Expected 3 games with 1 draw, got %+v", games)
+	}
+
+	// Only the stats file is left behind, the temporary file was renamed over it
+	entries, err := os.ReadDir(filepath.Dir(path))
+	if err != nil || len(entries) != 1 {
+		t.Errorf("Expected only the stats file in the directory, got %v (%v)", entries, err)
+	}
+}
+
+// TestLoadStats_Unreadable tests that a missing or corrupt file starts the server from zero
+func TestLoadStats_Unreadable(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "stats.json")
+	t.Setenv(statsFileEnv, path)
+
+	srv := NewServer()
+	srv.cancelFunc()
+	if games := srv.statsSnapshot().Games.AllTime; games.Games != 0 {
+		t.Errorf("A missing file should start from zero, got %+v", games)
+	}
+
+	if err := os.WriteFile(path, []byte("{not json"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	srv = NewServer()
+	srv.cancelFunc()
+	if games := srv.statsSnapshot().Games.AllTime; games.Games != 0 {
+		t.Errorf("A corrupt file should start from zero, got %+v", games)
+	}
+}
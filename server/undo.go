@@ -0,0 +1,81 @@
+// Copyright (c) 2025 Haute école d'ingénierie et d'architecture de Fribourg
+// SPDX-License-Identifier: Apache-2.0
+// Author: Marvin Egger marvin.egger@hotmail.ch
+// Created: 16.10.2026
+
+package main
+
+import "github.com/marvinEgger/GOnnect4/server/lib"
+
+// handleUndoRequest asks the opponent to let the player take back its last move
+func (srv *Server) handleUndoRequest(client *lib.Client) {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+
+	game := srv.findGameForClient(client)
+	if game == nil {
+		srv.sendError(client, lib.ErrGameNotFound)
+		return
+	}
+
+	playerIdx := game.GetPlayerIndex(client.PlayerID)
+	if playerIdx < 0 {
+		srv.sendError(client, lib.ErrPlayerNotInGame)
+		return
+	}
+
+	// A bot never answers, its reply may already be on its way
+	if opponent := game.GetPlayers()[1-playerIdx]; opponent != nil && opponent.Bot != nil {
+		srv.sendError(client, lib.ErrUndoNotAllowed)
+		return
+	}
+
+	if err := game.RequestUndo(playerIdx); err != nil {
+		srv.sendError(client, err)
+		return
+	}
+
+	srv.broadcastToGame(game, lib.Message{
+		Type: lib.MsgUndoRequest,
+		Data: lib.UndoRequestData{PlayerIdx: playerIdx},
+	})
+}
+
+// handleUndoResponse applies or drops the takeback asked by the opponent
+func (srv *Server) handleUndoResponse(client *lib.Client, data lib.UndoResponseData) {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+
+	game := srv.findGameForClient(client)
+	if game == nil {
+		srv.sendError(client, lib.ErrGameNotFound)
+		return
+	}
+
+	playerIdx := game.GetPlayerIndex(client.PlayerID)
+	if playerIdx < 0 {
+		srv.sendError(client, lib.ErrPlayerNotInGame)
+		return
+	}
+
+	undone, err := game.AnswerUndo(playerIdx, data.Accept)
+	if err != nil {
+		srv.sendError(client, err)
+		return
+	}
+
+	srv.broadcastToGame(game, lib.Message{
+		Type: lib.MsgUndoResponse,
+		Data: lib.UndoResponseData{Accept: undone, PlayerIdx: playerIdx},
+	})
+
+	// The board changed under the players, each gets the state with the turn given back
+	if undone {
+		srv.broadcastGameState(game)
+	}
+
+	// Taking a move back may repeat a position once too often
+	if game.GetStatus() == lib.StatusFinished {
+		srv.announceGameOver(game)
+	}
+}
@@ -0,0 +1,89 @@
+// Copyright (c) 2025 Haute école d'ingénierie et d'architecture de Fribourg
+// SPDX-License-Identifier: Apache-2.0
+// Author: Marvin Egger marvin.egger@hotmail.ch
+// Created: 16.10.2026
+
+package main
+
+import (
+	"testing"
+
+	"github.com/marvinEgger/GOnnect4/server/lib"
+)
+
+// TestHandleUndo_AgreedTakeback tests that both players see the request, the answer and the new state
+func TestHandleUndo_AgreedTakeback(t *testing.T) {
+	srv := NewServer()
+	defer srv.cancelFunc()
+
+	alice := loginTestPlayer(srv, "Alice")
+	bob := loginTestPlayer(srv, "Bob")
+	srv.handleCreateGame(alice, lib.CreateGameData{})
+	srv.handleJoinGame(bob, lib.JoinGameData{Code: alice.GameCode})
+	game := srv.findGameForClient(alice)
+	defer game.Cleanup()
+
+	mover, opponent := alice, bob
+	if game.GetPlayerIndex(bob.PlayerID) == game.CurrentTurn {
+		mover, opponent = bob, alice
+	}
+
+	srv.handlePlay(mover, lib.PlayData{Column: 2})
+	drainMessages(mover)
+	drainMessages(opponent)
+
+	srv.handleUndoRequest(mover)
+	if !hasMessage(drainMessages(opponent), lib.MsgUndoRequest) {
+		t.Fatal("The opponent should be asked about the takeback")
+	}
+
+	srv.handleUndoResponse(opponent, lib.UndoResponseData{Accept: true})
+	msgs := drainMessages(mover)
+	if !hasMessage(msgs, lib.MsgUndoResponse) || !hasMessage(msgs, lib.MsgGameState) {
+		t.Errorf("Expected the answer and the new state, got %v", msgs)
+	}
+	if game.GetMoveCount() != 0 {
+		t.Errorf("Expected the move taken back, got %d moves", game.GetMoveCount())
+	}
+
+	// Answering twice finds nothing to answer
+	srv.handleUndoResponse(opponent, lib.UndoResponseData{Accept: true})
+	if !hasError(drainMessages(opponent), lib.ErrNoUndoRequest) {
+		t.Error("Expected ErrNoUndoRequest without a pending request")
+	}
+}
+
+// TestHandleUndo_RepetitionDraw tests that taking the same move back over and over ends a friend game as a draw
+func TestHandleUndo_RepetitionDraw(t *testing.T) {
+	srv := NewServer()
+	defer srv.cancelFunc()
+
+	alice := loginTestPlayer(srv, "Alice")
+	bob := loginTestPlayer(srv, "Bob")
+	srv.handleCreateGame(alice, lib.CreateGameData{})
+	srv.handleJoinGame(bob, lib.JoinGameData{Code: alice.GameCode})
+	game := srv.findGameForClient(alice)
+	defer game.Cleanup()
+
+	mover, opponent := alice, bob
+	if game.GetPlayerIndex(bob.PlayerID) == game.CurrentTurn {
+		mover, opponent = bob, alice
+	}
+
+	// The starting position comes back after each takeback
+	for takeback := 1; takeback < lib.RepetitionLimit; takeback++ {
+		if game.GetStatus() != lib.StatusPlaying {
+			t.Fatalf("Game ended after %d takebacks", takeback-1)
+		}
+		srv.handlePlay(mover, lib.PlayData{Column: 2})
+		srv.handleUndoRequest(mover)
+		srv.handleUndoResponse(opponent, lib.UndoResponseData{Accept: true})
+	}
+
+	if game.GetStatus() != lib.StatusFinished || game.DrawReason != lib.DrawRepetition {
+		t.Fatalf("Expected a repetition draw, got status %v reason %q", game.GetStatus(), game.DrawReason)
+	}
+	if !hasMessage(drainMessages(mover), lib.MsgGameOver) {
+		t.Error("The players should be told the game is over")
+	}
+}
@@ -6,16 +6,29 @@
 package main
 
 import (
+	"encoding/json"
 	"log"
+	"net"
 	"net/http"
+	"strings"
 
 	"github.com/coder/websocket"
 	"github.com/coder/websocket/wsjson"
 	"github.com/marvinEgger/GOnnect4/server/lib"
 )
 
+const deadLetterPayloadLength = 128
+
 // handleWebSocket handles websocket connections
 func (srv *Server) handleWebSocket(w http.ResponseWriter, r *http.Request) {
+	// Refuse hosts holding too many connections before paying for the upgrade
+	ip := srv.remoteIP(r)
+	if !srv.acquireConnection(ip) {
+		http.Error(w, "too many connections", http.StatusTooManyRequests)
+		return
+	}
+	defer srv.releaseConnection(ip)
+
 	// Upgrade HTTP connection to WebSocket
 	conn, err := websocket.Accept(w, r, &websocket.AcceptOptions{
 		// Allow connections from any origin
@@ -28,10 +41,19 @@ func (srv *Server) handleWebSocket(w http.ResponseWriter, r *http.Request) {
 
 	// Create client wrapper for this connection
 	client := lib.NewClient(conn)
+	if !srv.trackClient(client) {
+		conn.Close(websocket.StatusGoingAway, "Server shutting down")
+		return
+	}
+	defer srv.background.Done()
 
 	// Start write pump in separate goroutine to send messages to client
 	// Runs concurrently to avoid blocking when sending multiple messages
-	go client.WritePump()
+	srv.background.Add(1)
+	go func() {
+		defer srv.background.Done()
+		client.WritePump()
+	}()
 
 	// Cleanup function runs when connection closes (error, disconnect, or normal exit)
 	defer func() {
@@ -40,23 +62,24 @@ func (srv *Server) handleWebSocket(w http.ResponseWriter, r *http.Request) {
 			// Disconnect player from lobby
 			if player := srv.lobby[client.PlayerID]; player != nil {
 				player.SetSender(nil)
+				srv.pauseForDisconnect(client)
 			}
 
-			// Remove from matchmaking queue if present
-			wasInQueue := false
-			for i, pid := range srv.matchmakingQueue {
-				if pid == client.PlayerID {
-					srv.matchmakingQueue = append(srv.matchmakingQueue[:i], srv.matchmakingQueue[i+1:]...)
-					wasInQueue = true
-					break
-				}
-			}
-
-			// Notify other players in queue
-			if wasInQueue {
+			// Remove from matchmaking queue if present and notify the other players in it
+			if srv.removeFromQueue(client.PlayerID) {
 				srv.broadcastQueueUpdate()
 			}
+
+			// Cancel a pending match confirmation
+			srv.declineReadyCheck(client.PlayerID)
+
+			// A puzzle rush cannot be answered offline
+			srv.stopPuzzleRun(client.PlayerID)
+
+			// Stop sending the watched game to a closed connection
+			srv.stopSpectating(client)
 		}
+		delete(srv.clients, client)
 		// Clean up any stale games or disconnected players
 		srv.cleanupStaleGames()
 		srv.mu.Unlock()
@@ -82,36 +105,106 @@ func (srv *Server) handleWebSocket(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-// handleMessage routes messages to appropriate handlers
-func (srv *Server) handleMessage(client *lib.Client, msg lib.Message) {
+// remoteIP returns the address of the client, X-Forwarded-For is only trusted when configured
+// The last forwarded entry is the one added by our proxy, earlier ones can be forged by the client
+func (srv *Server) remoteIP(r *http.Request) string {
+	if srv.trustProxy {
+		if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
+			entries := strings.Split(forwarded, ",")
+			if ip := strings.TrimSpace(entries[len(entries)-1]); ip != "" {
+				return ip
+			}
+		}
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// acquireConnection counts a new connection from ip and reports whether it is within the limit
+func (srv *Server) acquireConnection(ip string) bool {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+
+	if srv.maxConnsPerIP > 0 && srv.connsPerIP[ip] >= srv.maxConnsPerIP {
+		return false
+	}
+	srv.connsPerIP[ip]++
+	return true
+}
+
+// releaseConnection forgets a closed connection from ip
+func (srv *Server) releaseConnection(ip string) {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+
+	srv.connsPerIP[ip]--
+	if srv.connsPerIP[ip] <= 0 {
+		delete(srv.connsPerIP, ip)
+	}
+}
+
+// handleMessage routes messages to appropriate handlers and reports whether the type is known
+// Messages past the rate limit of the client are dropped before routing and count as handled
+func (srv *Server) handleMessage(client *lib.Client, msg lib.Message) bool {
+	if allowed, report := client.AllowMessage(); !allowed {
+		if report {
+			srv.sendErrorCode(client, lib.ErrRateLimited, "RATE_LIMITED")
+		}
+		return true
+	}
+
 	switch msg.Type {
 	case lib.MsgLogin:
 		var data lib.LoginData
 		if err := mapToStruct(msg.Data, &data); err == nil {
+			client.Version = msg.ClientVersion()
 			srv.handleLogin(client, data)
+		} else {
+			srv.reportDeadLetter(client, msg, err)
 		}
 
 	case lib.MsgCreateGame:
-		srv.handleCreateGame(client)
+		var data lib.CreateGameData
+		if err := mapToStruct(msg.Data, &data); err == nil {
+			srv.handleCreateGame(client, data)
+		} else {
+			srv.reportDeadLetter(client, msg, err)
+		}
 
 	case lib.MsgJoinGame:
 		var data lib.JoinGameData
 		if err := mapToStruct(msg.Data, &data); err == nil {
 			srv.handleJoinGame(client, data)
+		} else {
+			srv.reportDeadLetter(client, msg, err)
 		}
 
 	case lib.MsgPlay:
 		var data lib.PlayData
 		if err := mapToStruct(msg.Data, &data); err == nil {
 			srv.handlePlay(client, data)
+		} else {
+			srv.reportDeadLetter(client, msg, err)
 		}
 
 	case lib.MsgReplay:
-		srv.handleReplay(client)
+		var data lib.ReplayData
+		if err := mapToStruct(msg.Data, &data); err == nil {
+			srv.handleReplay(client, data)
+		} else {
+			srv.reportDeadLetter(client, msg, err)
+		}
 
 	case lib.MsgForfeit:
 		srv.handleForfeit(client)
 
+	case lib.MsgResignRound:
+		srv.handleResignRound(client)
+
 	case lib.MsgLeaveLobby:
 		srv.handleLeaveLobby(client)
 
@@ -120,5 +213,127 @@ func (srv *Server) handleMessage(client *lib.Client, msg lib.Message) {
 
 	case lib.MsgLeaveMatchmaking:
 		srv.handleLeaveMatchmaking(client)
+
+	case lib.MsgLobbyChat:
+		var data lib.LobbyChatData
+		if err := mapToStruct(msg.Data, &data); err == nil {
+			srv.handleLobbyChat(client, data)
+		} else {
+			srv.reportDeadLetter(client, msg, err)
+		}
+
+	case lib.MsgSavePrefs:
+		var data lib.SavePrefsData
+		if err := mapToStruct(msg.Data, &data); err == nil {
+			srv.handleSavePrefs(client, data)
+		} else {
+			srv.reportDeadLetter(client, msg, err)
+		}
+
+	case lib.MsgReadyResponse:
+		var data lib.ReadyResponseData
+		if err := mapToStruct(msg.Data, &data); err == nil {
+			srv.handleReadyResponse(client, data)
+		} else {
+			srv.reportDeadLetter(client, msg, err)
+		}
+
+	case lib.MsgSpectateFeatured:
+		srv.handleSpectateFeatured(client)
+
+	case lib.MsgSpectate:
+		var data lib.SpectateData
+		if err := mapToStruct(msg.Data, &data); err == nil {
+			srv.handleSpectate(client, data)
+		} else {
+			srv.reportDeadLetter(client, msg, err)
+		}
+
+	case lib.MsgPlayBot:
+		var data lib.PlayBotData
+		if err := mapToStruct(msg.Data, &data); err == nil {
+			srv.handlePlayBot(client, data)
+		} else {
+			srv.reportDeadLetter(client, msg, err)
+		}
+
+	case lib.MsgHistoryRequest:
+		var data lib.HistoryRequestData
+		if err := mapToStruct(msg.Data, &data); err == nil {
+			srv.handleHistoryRequest(client, data)
+		} else {
+			srv.reportDeadLetter(client, msg, err)
+		}
+
+	case lib.MsgLeaderboard:
+		var data lib.LeaderboardRequestData
+		if err := mapToStruct(msg.Data, &data); err == nil {
+			srv.handleRatingLeaderboard(client, data)
+		} else {
+			srv.reportDeadLetter(client, msg, err)
+		}
+
+	case lib.MsgUndoRequest:
+		srv.handleUndoRequest(client)
+
+	case lib.MsgUndoResponse:
+		var data lib.UndoResponseData
+		if err := mapToStruct(msg.Data, &data); err == nil {
+			srv.handleUndoResponse(client, data)
+		} else {
+			srv.reportDeadLetter(client, msg, err)
+		}
+
+	case lib.MsgDrawOffer:
+		srv.handleDrawOffer(client)
+
+	case lib.MsgDrawResponse:
+		var data lib.DrawResponseData
+		if err := mapToStruct(msg.Data, &data); err == nil {
+			srv.handleDrawResponse(client, data)
+		} else {
+			srv.reportDeadLetter(client, msg, err)
+		}
+
+	case lib.MsgPuzzleStart:
+		srv.handlePuzzleStart(client)
+
+	case lib.MsgPuzzleAnswer:
+		var data lib.PuzzleAnswerData
+		if err := mapToStruct(msg.Data, &data); err == nil {
+			srv.handlePuzzleAnswer(client, data)
+		} else {
+			srv.reportDeadLetter(client, msg, err)
+		}
+
+	default:
+		srv.reportDeadLetter(client, msg, lib.ErrUnknownMessage)
+		return false
+	}
+
+	return true
+}
+
+// reportDeadLetter logs a message that could not be handled and notifies the client
+// Reports are rate limited per client so a spamming client cannot flood the logs
+func (srv *Server) reportDeadLetter(client *lib.Client, msg lib.Message, reason error) {
+	if !client.AllowDeadLetter() {
+		return
+	}
+
+	payload, err := json.Marshal(msg.Data)
+	if err != nil {
+		payload = []byte("<unencodable>")
+	}
+	if len(payload) > deadLetterPayloadLength {
+		payload = append(payload[:deadLetterPayloadLength], "..."...)
+	}
+
+	log.Printf("Dead letter from player %q: type=%q reason=%v payload=%s", client.PlayerID, msg.Type, reason, payload)
+
+	if reason == lib.ErrUnknownMessage {
+		srv.sendErrorCode(client, lib.ErrUnknownMessage, "UNKNOWN_MESSAGE")
+	} else {
+		srv.sendErrorCode(client, lib.ErrMalformedMessage, "MALFORMED_MESSAGE")
 	}
 }
@@ -0,0 +1,222 @@
+// Copyright (c) 2025 Haute école d'ingénierie et d'architecture de Fribourg
+// SPDX-License-Identifier: Apache-2.0
+// Author: Marvin Egger marvin.egger@hotmail.ch
+// Created: 15.10.2026
+
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/marvinEgger/GOnnect4/server/lib"
+)
+
+// newTestClient creates a client without a websocket connection
+func newTestClient() *lib.Client {
+	return lib.NewClient(nil)
+}
+
+// drainMessages returns all messages queued for a client
+func drainMessages(client *lib.Client) []lib.Message {
+	var msgs []lib.Message
+	for {
+		select {
+		case msg := <-client.SendChan:
+			msgs = append(msgs, msg)
+		default:
+			return msgs
+		}
+	}
+}
+
+// TestHandleMessage_AllClientTypesHandled tests that every client message type has a handler
+func TestHandleMessage_AllClientTypesHandled(t *testing.T) {
+	srv := NewServer()
+	defer srv.cancelFunc()
+
+	for _, msgType := range lib.ClientMessageTypes {
+		client := newTestClient()
+		if !srv.handleMessage(client, lib.Message{Type: msgType}) {
+			t.Errorf("Message type %q should have a handler", msgType)
+		}
+	}
+}
+
+// TestHandleMessage_UnknownType tests that unknown types are reported as unhandled
+func TestHandleMessage_UnknownType(t *testing.T) {
+	srv := NewServer()
+	defer srv.cancelFunc()
+
+	if srv.handleMessage(newTestClient(), lib.Message{Type: "bogus"}) {
+		t.Error("Unknown message type should not be handled")
+	}
+}
+
+// TestHandleMessage_UnknownTypeSendsError tests the dead-letter error response
+func TestHandleMessage_UnknownTypeSendsError(t *testing.T) {
+	srv := NewServer()
+	defer srv.cancelFunc()
+
+	client := newTestClient()
+	srv.handleMessage(client, lib.Message{Type: "bogus", Data: "payload"})
+
+	msgs := drainMessages(client)
+	if len(msgs) != 1 || msgs[0].Type != lib.MsgError {
+		t.Fatalf("Expected one error message, got %v", msgs)
+	}
+
+	data, ok := msgs[0].Data.(lib.ErrorData)
+	if !ok || data.Code != "UNKNOWN_MESSAGE" {
+		t.Errorf("Expected UNKNOWN_MESSAGE code, got %v", msgs[0].Data)
+	}
+}
+
+// TestHandleMessage_DeadLetterRateLimited tests that repeated bogus messages are not all reported
+func TestHandleMessage_DeadLetterRateLimited(t *testing.T) {
+	srv := NewServer()
+	defer srv.cancelFunc()
+
+	client := newTestClient()
+	for i := 0; i < 10; i++ {
+		srv.handleMessage(client, lib.Message{Type: "bogus"})
+	}
+
+	if msgs := drainMessages(client); len(msgs) != 1 {
+		t.Errorf("Expected a single error for a burst of bogus messages, got %d", len(msgs))
+	}
+}
+
+// TestHandleMessage_RateLimited tests that a flood is dropped with a single warning and the client stays connected
+func TestHandleMessage_RateLimited(t *testing.T) {
+	srv := NewServer()
+	defer srv.cancelFunc()
+
+	client := newTestClient()
+	for i := 0; i < 100; i++ {
+		srv.handleMessage(client, lib.Message{Type: lib.MsgLeaveMatchmaking})
+	}
+
+	warnings := 0
+	for _, msg := range drainMessages(client) {
+		if data, ok := msg.Data.(lib.ErrorData); ok && data.Code == "RATE_LIMITED" {
+			warnings++
+		}
+	}
+	if warnings != 1 {
+		t.Errorf("Expected a single rate limit warning, got %d", warnings)
+	}
+
+	if allowed, _ := client.AllowMessage(); allowed {
+		t.Error("Messages should still be dropped right after a flood")
+	}
+}
+
+// TestHandleMessage_MalformedPayload tests that malformed payloads are reported
+func TestHandleMessage_MalformedPayload(t *testing.T) {
+	srv := NewServer()
+	defer srv.cancelFunc()
+
+	client := newTestClient()
+	srv.handleMessage(client, lib.Message{Type: lib.MsgPlay, Data: "not an object"})
+
+	msgs := drainMessages(client)
+	if len(msgs) != 1 {
+		t.Fatalf("Expected one error message, got %v", msgs)
+	}
+
+	data, ok := msgs[0].Data.(lib.ErrorData)
+	if !ok || data.Code != "MALFORMED_MESSAGE" {
+		t.Errorf("Expected MALFORMED_MESSAGE code, got %v", msgs[0].Data)
+	}
+}
+
+// TestHandleMessage_LoginVersionMismatch tests that clients without a version are warned but logged in
+func TestHandleMessage_LoginVersionMismatch(t *testing.T) {
+	srv := NewServer()
+	defer srv.cancelFunc()
+
+	client := newTestClient()
+	srv.handleMessage(client, lib.Message{Type: lib.MsgLogin, Data: lib.LoginData{Username: "Alice"}})
+
+	msgs := drainMessages(client)
+	if !hasMessage(msgs, lib.MsgWelcome) {
+		t.Error("Legacy client should still be welcomed")
+	}
+	if !hasMessage(msgs, lib.MsgVersionMismatch) {
+		t.Error("Legacy client should be warned about the version mismatch")
+	}
+	if client.Version != 1 {
+		t.Errorf("Absent version should be treated as v1, got %d", client.Version)
+	}
+}
+
+// TestHandleMessage_LoginCurrentVersion tests that up to date clients get no warning
+func TestHandleMessage_LoginCurrentVersion(t *testing.T) {
+	srv := NewServer()
+	defer srv.cancelFunc()
+
+	client := newTestClient()
+	srv.handleMessage(client, lib.Message{
+		Type:    lib.MsgLogin,
+		Version: lib.ProtocolVersion,
+		Data:    lib.LoginData{Username: "Alice"},
+	})
+
+	msgs := drainMessages(client)
+	if hasMessage(msgs, lib.MsgVersionMismatch) {
+		t.Error("Current client should not be warned")
+	}
+	for _, msg := range msgs {
+		if msg.Type == lib.MsgWelcome && msg.Version != lib.ProtocolVersion {
+			t.Errorf("Welcome should carry server version %d, got %d", lib.ProtocolVersion, msg.Version)
+		}
+	}
+}
+
+// TestRemoteIP_ProxyHeader tests that X-Forwarded-For is only used when the proxy is trusted
+func TestRemoteIP_ProxyHeader(t *testing.T) {
+	srv := NewServer()
+	defer srv.cancelFunc()
+
+	req := httptest.NewRequest(http.MethodGet, "/ws", nil)
+	req.RemoteAddr = "10.0.0.1:51234"
+	req.Header.Set("X-Forwarded-For", "1.2.3.4, 203.0.113.7")
+
+	if ip := srv.remoteIP(req); ip != "10.0.0.1" {
+		t.Errorf("Untrusted proxy header should be ignored, got %q", ip)
+	}
+
+	srv.trustProxy = true
+	if ip := srv.remoteIP(req); ip != "203.0.113.7" {
+		t.Errorf("Expected the address added by the proxy, got %q", ip)
+	}
+}
+
+// TestAcquireConnection_LimitPerIP tests that connections beyond the limit are refused until one closes
+func TestAcquireConnection_LimitPerIP(t *testing.T) {
+	srv := NewServer()
+	defer srv.cancelFunc()
+	srv.maxConnsPerIP = 2
+
+	if !srv.acquireConnection("1.2.3.4") || !srv.acquireConnection("1.2.3.4") {
+		t.Fatal("Connections within the limit should be accepted")
+	}
+	if srv.acquireConnection("1.2.3.4") {
+		t.Error("Connection beyond the limit should be refused")
+	}
+	if !srv.acquireConnection("5.6.7.8") {
+		t.Error("Other hosts should not be affected")
+	}
+
+	srv.releaseConnection("1.2.3.4")
+	if !srv.acquireConnection("1.2.3.4") {
+		t.Error("A closed connection should free a slot")
+	}
+
+	srv.releaseConnection("5.6.7.8")
+	if _, tracked := srv.connsPerIP["5.6.7.8"]; tracked {
+		t.Error("Hosts without connections should be forgotten")
+	}
+}